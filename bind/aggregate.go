@@ -0,0 +1,352 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// ItemSymbol is the item in the predicate expression of the filter helper
+const ItemSymbol = "$item"
+
+// aggregateHelpers returns the helpers computing values across a slice, like
+// the number of the items that satisfy a predicate. A predicate is either the
+// name of a bool field of the items (like `Done`), or a helper or method that
+// takes an item and returns a bool.
+//
+// Usage:
+//	<span bind-text="count(Entries, `Done`)"></span> done
+//	<span bind-text="count(Entries, isIncomplete)"></span> items left
+//	<span bind-text="sum(Items, `Price`)"></span>
+//	<li bind-each="filter(Entries, `Done`) -> _, entry"><% entry.Title %></li>
+//	<li bind-each="sort(Entries, `Title`, true) -> _, entry"><% entry.Title %></li>
+// Like for any bind string, they are evaluated again when the slice field changes.
+//
+// The predicate of filter may also be a bool expression, evaluated for each item with the
+// fields of the item in scope (ItemSymbol is the item itself), before the symbols of the
+// bind scope. The fields of the bind scope used in the predicate are watched too,
+// so the filtered view is updated when they change, for example:
+//	<li bind-each="filter(Todos, Mode == 'all' || Done == (Mode == 'completed')) -> _, todo">
+func aggregateHelpers() map[string]interface{} {
+	return map[string]interface{}{
+		"count":  countItems,
+		"sum":    sumItems,
+		"filter": filterItems,
+		"sort":   sortItems,
+	}
+}
+
+func sliceValue(helper string, slice interface{}) reflect.Value {
+	v := reflect.ValueOf(slice)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v
+	case reflect.Invalid:
+		return reflect.ValueOf([]interface{}{})
+	}
+
+	panic(fmt.Errorf(`%v helper: unsupported type %v, expected a slice.`, helper, v.Type()))
+}
+
+// itemField returns the value of the field (possibly dotted, like `Author.Name`) of an item
+func itemField(helper string, item reflect.Value, field string) reflect.Value {
+	item = unwrapValue(item)
+	if field == "" {
+		return item
+	}
+
+	oe, ok := evaluateObjField(field, item)
+	if !ok {
+		panic(fmt.Errorf(`%v helper: unable to get the field "%v" of an item of type %v.`, helper, field, item.Type()))
+	}
+	return oe.fieldRefl
+}
+
+// itemPredicate converts a field name or a function to a predicate on the items
+func itemPredicate(helper string, pred interface{}) func(reflect.Value) bool {
+	switch p := pred.(type) {
+	case string:
+		return func(item reflect.Value) bool {
+			v := itemField(helper, item, p)
+			if v.Kind() != reflect.Bool {
+				panic(fmt.Errorf(`%v helper: the field "%v" is a %v, it must be a bool.`, helper, p, v.Type()))
+			}
+			return v.Bool()
+		}
+	}
+
+	fn := reflect.ValueOf(pred)
+	ftype := fn.Type()
+	if fn.Kind() != reflect.Func || ftype.NumIn() != 1 || ftype.NumOut() != 1 || ftype.Out(0).Kind() != reflect.Bool {
+		panic(fmt.Errorf(`%v helper: the predicate must be a field name or a function taking an item and returning a bool, got %v.`, helper, ftype))
+	}
+
+	return func(item reflect.Value) bool {
+		item = unwrapValue(item)
+		if !item.IsValid() || !item.Type().AssignableTo(ftype.In(0)) {
+			panic(fmt.Errorf(`%v helper: an item of type %v cannot be passed to the predicate %v.`, helper, item.Type(), ftype))
+		}
+		return fn.Call([]reflect.Value{item})[0].Bool()
+	}
+}
+
+// countItems returns the number of items satisfying the predicate,
+// or the number of items if there's none
+func countItems(slice interface{}, pred ...interface{}) int {
+	v := sliceValue("count", slice)
+	if len(pred) == 0 {
+		return v.Len()
+	}
+
+	match := itemPredicate("count", pred[0])
+	n := 0
+	for i := 0; i < v.Len(); i++ {
+		if match(v.Index(i)) {
+			n++
+		}
+	}
+	return n
+}
+
+// sumItems returns the sum of the given number field of the items, or of the
+// items themselves if no field is given. The sum is an int unless a value is a float.
+func sumItems(slice interface{}, field ...string) interface{} {
+	v := sliceValue("sum", slice)
+	fname := ""
+	if len(field) > 0 {
+		fname = field[0]
+	}
+
+	var isum int64
+	var fsum float64
+	isFloat := false
+	for i := 0; i < v.Len(); i++ {
+		fv := unwrapValue(itemField("sum", v.Index(i), fname))
+		switch {
+		case isIntKind(fv.Kind()):
+			isum += toInt64(fv)
+		case isFloatKind(fv.Kind()):
+			isFloat = true
+			fsum += fv.Float()
+		default:
+			panic(fmt.Errorf(`sum helper: cannot sum values of kind %v.`, fv.Kind()))
+		}
+	}
+
+	if isFloat {
+		return fsum + float64(isum)
+	}
+	return int(isum)
+}
+
+// filterItems returns a slice of the same type with the items satisfying the predicate
+func filterItems(slice interface{}, pred interface{}) interface{} {
+	v := sliceValue("filter", slice)
+	match := itemPredicate("filter", pred)
+	result := reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		if match(v.Index(i)) {
+			result = reflect.Append(result, v.Index(i))
+		}
+	}
+	return result.Interface()
+}
+
+// compareKeys compares two sort keys of the same kind, it returns a negative number
+// if a comes before b, 0 if they are equal and a positive number otherwise
+func compareKeys(a, b reflect.Value) int {
+	switch {
+	case a.Type() == timeType && b.Type() == timeType:
+		ta, tb := a.Interface().(time.Time), b.Interface().(time.Time)
+		switch {
+		case ta.Before(tb):
+			return -1
+		case ta.After(tb):
+			return 1
+		}
+		return 0
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		switch {
+		case a.String() < b.String():
+			return -1
+		case a.String() > b.String():
+			return 1
+		}
+		return 0
+	}
+
+	fa, aok := toFloat(a)
+	fb, bok := toFloat(b)
+	if !aok || !bok {
+		panic(fmt.Errorf(`sort helper: cannot compare values of types %v and %v.`, a.Type(), b.Type()))
+	}
+	switch {
+	case fa < fb:
+		return -1
+	case fa > fb:
+		return 1
+	}
+	return 0
+}
+
+// sortedItems sorts items by their keys
+type sortedItems struct {
+	items []reflect.Value
+	keys  []reflect.Value
+	asc   bool
+}
+
+func (s sortedItems) Len() int { return len(s.items) }
+
+func (s sortedItems) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+func (s sortedItems) Less(i, j int) bool {
+	c := compareKeys(s.keys[i], s.keys[j])
+	if s.asc {
+		return c < 0
+	}
+	return c > 0
+}
+
+// sortItems returns a sorted copy of the slice, by the given field (possibly dotted)
+// of the items, or by the items themselves if the field is empty. The field may be
+// a string, a number or a time.Time. The order is ascending unless asc is false,
+// items with equal keys keep their order.
+func sortItems(slice interface{}, field string, asc ...bool) interface{} {
+	v := sliceValue("sort", slice)
+	s := sortedItems{
+		items: make([]reflect.Value, v.Len()),
+		keys:  make([]reflect.Value, v.Len()),
+		asc:   len(asc) == 0 || asc[0],
+	}
+	for i := range s.items {
+		s.items[i] = v.Index(i)
+		s.keys[i] = unwrapValue(itemField("sort", v.Index(i), field))
+	}
+	sort.Stable(s)
+
+	result := reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), 0, v.Len())
+	for _, item := range s.items {
+		result = reflect.Append(result, item)
+	}
+	return result.Interface()
+}
+
+var filterHelperPtr = reflect.ValueOf(filterItems).Pointer()
+
+// isFilterCall returns whether the expression is a call of the filter helper,
+// whose predicate is evaluated for each item
+func (b *bindScope) isFilterCall(e *expr) bool {
+	if e.typ != CallExpr || len(e.args) != 2 {
+		return false
+	}
+
+	sym, err := b.scope.lookup(e.name)
+	if err != nil {
+		return false
+	}
+	fs, ok := sym.(funcSymbol)
+	return ok && fs.fn.Pointer() == filterHelperPtr
+}
+
+// itemSymbolTable gives access to the fields of an item in the predicate
+// of filter, they are not watched since the slice is
+type itemSymbolTable struct {
+	item reflect.Value
+}
+
+func (st itemSymbolTable) lookup(symbol string) (sym scopeSymbol, ok bool) {
+	if symbol == ItemSymbol {
+		return valueSymbol{symbol, st.item}, true
+	}
+
+	item := unwrapValue(st.item)
+	if !item.IsValid() || item.Kind() == reflect.Ptr && item.IsNil() {
+		return
+	}
+	if sym, ok = (modelSymbolTable{item}).lookup(symbol); ok {
+		sym = itemFieldSymbol{sym}
+	}
+	return
+}
+
+// itemFieldSymbol is a field of an item, it's not bindable
+type itemFieldSymbol struct {
+	sym scopeSymbol
+}
+
+func (s itemFieldSymbol) value() (reflect.Value, error) {
+	return s.sym.value()
+}
+
+func (s itemFieldSymbol) call(args []reflect.Value) (reflect.Value, error) {
+	return s.sym.call(args)
+}
+
+// evaluateFilter evaluates a call of the filter helper. The predicate is evaluated for
+// each item, with the item's fields in scope. If it gives a bool, it decides whether
+// the item is kept, otherwise it's a field name or a function, as for the other helpers.
+// The fields of the scope that the predicate uses are returned to be watched.
+func (b *bindScope) evaluateFilter(e *expr) (v reflect.Value, blist []bindable, err error) {
+	slice, blist, err := b.evaluateRec(e.args[0])
+	if err != nil {
+		return
+	}
+
+	var si interface{}
+	if slice = unwrapValue(slice); slice.IsValid() && slice.CanInterface() {
+		si = slice.Interface()
+	}
+	items := sliceValue("filter", si)
+	eval := func(item reflect.Value) (reflect.Value, []bindable, error) {
+		s := newScope(itemSymbolTable{item})
+		s.merge(b.scope)
+		return (&bindScope{s}).evaluateRec(e.args[1])
+	}
+
+	// the dependencies are the same for all the items, they are taken from a zero
+	// item so that they are watched even while the slice is empty
+	_, pblist, _ := eval(zeroItem(items.Type().Elem()))
+	blist = append(blist, pblist...)
+
+	result := reflect.MakeSlice(reflect.SliceOf(items.Type().Elem()), 0, items.Len())
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i)
+		var pred reflect.Value
+		pred, _, err = eval(item)
+		if err != nil {
+			err = fmt.Errorf("filter helper: %v", err.Error())
+			return
+		}
+
+		pred = unwrapValue(pred)
+		keep := false
+		switch {
+		case pred.Kind() == reflect.Bool:
+			keep = pred.Bool()
+		case pred.IsValid() && pred.CanInterface():
+			keep = itemPredicate("filter", pred.Interface())(item)
+		default:
+			err = fmt.Errorf("filter helper: invalid predicate.")
+			return
+		}
+		if keep {
+			result = reflect.Append(result, item)
+		}
+	}
+
+	v = result
+	return
+}
+
+// zeroItem returns a zero item of the type, pointing to a zero value for pointer types
+func zeroItem(t reflect.Type) reflect.Value {
+	if t.Kind() == reflect.Ptr {
+		return reflect.New(t.Elem())
+	}
+	return reflect.Zero(t)
+}
@@ -0,0 +1,76 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type AsyncStatus int
+
+const (
+	AsyncIdle AsyncStatus = iota
+	AsyncPending
+	AsyncResolved
+	AsyncFailed
+)
+
+// AsyncSource is implemented by async data sources whose state can be shown
+// with the loading binder
+type AsyncSource interface {
+	AsyncStatus() AsyncStatus
+}
+
+// AsyncState keeps track of the state of an async operation, a model can
+// embed it or hold it as a field for each data source it loads.
+type AsyncState struct {
+	Status AsyncStatus
+	Error  string
+}
+
+func (s AsyncState) AsyncStatus() AsyncStatus {
+	return s.Status
+}
+
+// Start marks the operation as pending
+func (s *AsyncState) Start() {
+	s.Status = AsyncPending
+	s.Error = ""
+}
+
+// Resolve marks the operation as successfully finished
+func (s *AsyncState) Resolve() {
+	s.Status = AsyncResolved
+	s.Error = ""
+}
+
+// Fail marks the operation as failed with the given error
+func (s *AsyncState) Fail(err error) {
+	s.Status = AsyncFailed
+	s.Error = err.Error()
+}
+
+var loadingStates = map[string]AsyncStatus{
+	"":         AsyncPending,
+	"pending":  AsyncPending,
+	"resolved": AsyncResolved,
+	"error":    AsyncFailed,
+}
+
+// loadingVisible checks whether an element bound with the loading binder
+// for the given state should be shown for the value
+func loadingVisible(state string, value interface{}) (bool, error) {
+	want, ok := loadingStates[state]
+	if !ok {
+		return false, fmt.Errorf(`Unknown loading state "%v", must be one of "pending", "resolved" or "error".`, state)
+	}
+
+	switch v := value.(type) {
+	case AsyncStatus:
+		return v == want, nil
+	case AsyncSource:
+		return v.AsyncStatus() == want, nil
+	}
+
+	return false, fmt.Errorf("Wrong type %v for the loading binder, must be an AsyncStatus or an AsyncSource.",
+		reflect.TypeOf(value))
+}
@@ -0,0 +1,52 @@
+package bind
+
+import (
+	"errors"
+	"testing"
+)
+
+type testLoader struct {
+	Users AsyncState
+}
+
+func TestLoadingVisible(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testLoader{}
+	states := []string{"", "resolved", "error"}
+
+	expectShown := func(shown string) {
+		_, _, v, err := b.evaluate("Users", model)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, state := range states {
+			visible, err := loadingVisible(state, v)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if visible != (state == shown) {
+				t.Errorf("Status %v: expected the %q element visible to be %v.", model.Users.Status, state, !visible)
+			}
+		}
+	}
+
+	model.Users.Start()
+	expectShown("")
+	model.Users.Resolve()
+	expectShown("resolved")
+	model.Users.Fail(errors.New("timeout"))
+	expectShown("error")
+	if model.Users.Error != "timeout" {
+		t.Errorf("Expected the error to be kept, got %q.", model.Users.Error)
+	}
+
+	if visible, _ := loadingVisible("pending", AsyncPending); !visible {
+		t.Errorf("Expected a pending status to show the pending element.")
+	}
+	if _, err := loadingVisible("done", AsyncResolved); err == nil {
+		t.Errorf("Expected an error for an unknown state.")
+	}
+	if _, err := loadingVisible("", 1); err == nil {
+		t.Errorf("Expected an error for a value that's not an async source.")
+	}
+}
@@ -6,6 +6,10 @@ import (
 	jq "github.com/gopherjs/jquery"
 )
 
+// WrapperTag is the tag of the elements that group other elements without
+// being rendered themselves
+const WrapperTag = "wrapper"
+
 type ModelUpdateFn func(value string)
 
 // DomBinder is the common interface for Dom binders.
@@ -28,6 +32,25 @@ type DomBinder interface {
 	BindInstance() DomBinder
 }
 
+// OneShotBinder is implemented by binders that evaluate their expression themselves
+// with DomBind.Eval, like the init binder. Their expression is not evaluated before
+// Bind and it's not watched, DomBind.Value is nil.
+type OneShotBinder interface {
+	DomBinder
+	OneShot()
+}
+
+// TwoWayBinder is implemented by the 2-way binders, whose Watch updates the model.
+// A single output after "->" of a 2-way binder names a setter, a method or helper
+// taking the new value as a string, which is called instead of setting the field.
+//
+// Usage:
+//	bind-value="joinCsv(Tags) -> SetTags"
+type TwoWayBinder interface {
+	DomBinder
+	TwoWay()
+}
+
 type DomBind struct {
 	Elem    jq.JQuery
 	Value   interface{}
@@ -36,10 +59,16 @@ type DomBind struct {
 
 	binding  *Binding
 	scope    *scope
+	binds    []bindable
+	once     bool
+	expr     string
 	metadata string
 }
 
 func (d DomBind) bind(elem jq.JQuery, model interface{}, once bool, bindrelem bool) {
+	if model != nil {
+		markScoped(elem, bindrelem)
+	}
 	s := newModelScope(model)
 	s.merge(d.scope)
 	d.binding.bindWithScope(elem, once, bindrelem, s)
@@ -65,6 +94,44 @@ func (d DomBind) ProduceOutputs(elem jq.JQuery, optional bool, once bool, output
 	}
 }
 
+// Unwrap replaces the element with its contents if it's a <wrapper>, which is only
+// used to group elements in a template and is not rendered. The bindings of the
+// wrapper keep working on its contents. It returns the nodes that stand for
+// the element in the document.
+func (d DomBind) Unwrap(elem jq.JQuery) jq.JQuery {
+	if !elem.Is(WrapperTag) {
+		return elem
+	}
+
+	nodes := elem.Contents()
+	d.binding.replaceElem(elem, nodes)
+	return nodes
+}
+
+// Eval evaluates an expression in the scope of the binding, with the same models
+// and helpers as the bound value. Binders use it to read additional model data,
+// like an argument given by an attribute. The expression is not watched.
+func (d DomBind) Eval(expr string) (value interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	_, _, value, err = (&bindScope{d.scope}).evaluate(expr)
+	return
+}
+
+// ValueString returns the bound value converted to a string for displaying,
+// formatted with the type formatter of its type if one is registered
+func (d DomBind) ValueString() string {
+	if d.binding == nil {
+		return toString(d.Value)
+	}
+
+	return d.binding.formatValue(d.Value)
+}
+
 func (d DomBind) Panic(msg string) {
 	panic(d.metadata + ": " + msg)
 }
@@ -2,8 +2,12 @@ package bind
 
 import (
 	"fmt"
+	"math"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	jq "github.com/gopherjs/jquery"
 )
@@ -14,43 +18,409 @@ const (
 
 func defaultBinders() map[string]DomBinder {
 	return map[string]DomBinder{
-		"value": &ValueBinder{},
-		"html":  &HtmlBinder{},
-		"attr":  &AttrBinder{},
-		"on":    &EventBinder{},
-		"each":  new(EachBinder),
-		"page":  &PageBinder{},
-		"if":    new(IfBinder),
-		"ifn":   &UnlessBinder{&IfBinder{}},
+		"value":    &ValueBinder{},
+		"checked":  &CheckedBinder{},
+		"model":    new(ModelBinder),
+		"text":     &TextBinder{},
+		"html":     &HtmlBinder{},
+		"attr":     &AttrBinder{},
+		"on":       &EventBinder{},
+		"each":     new(EachBinder),
+		"page":     &PageBinder{},
+		"if":       new(IfBinder),
+		"ifn":      &UnlessBinder{&IfBinder{}},
+		"validate": new(ValidateBinder),
+		"class":    &ClassBinder{},
+		"is":       new(IsBinder),
+		"loading":  &LoadingBinder{},
+		"list":     new(ListBinder),
+		"ref":      &RefBinder{},
+		"stream":   new(StreamBinder),
+		"lazy":     new(LazyBinder),
+		"editable": new(EditableBinder),
+		"hidden":   &HiddenBinder{},
+		"init":     &InitBinder{},
+		"query":    new(QueryBinder),
+		"markdown": new(MarkdownBinder),
+		"scroll":   new(ScrollBinder),
+		"progress": new(ProgressBinder),
+		"error":    new(ErrorBinder),
+		"paged":    new(PagedBinder),
+		"template": new(TemplateBinder),
+		"title":    &TitleBinder{},
+		"size":     &SizeBinder{width: true, height: true},
+		"width":    &SizeBinder{width: true},
+		"height":   &SizeBinder{height: true},
+		"number":   new(NumberBinder),
+		"srcset":   new(SrcsetBinder),
+		"feed":     new(FeedBinder),
+		"live":     new(LiveBinder),
 	}
 }
 
 // ValueBinder is a 2-way binder that binds an element's value attribute.
 // It takes no extra dash args.
-// Meant to be used for <input>.
+// Meant to be used for <input>, <textarea> and <select>.
+// The output after "->" may name a setter, a helper or method that accepts
+// the new string value, it's called instead of setting the bound field.
+//
+// For number and range inputs, the min, max and step attributes are respected:
+// the values are snapped to the step and clamped, both when written to the model
+// and when displayed. An input that is not a number doesn't change the model.
+//
+// A time.Time field bound to a date, datetime-local, month or time input is displayed
+// in the input's format, and the input's value is parsed back to a time.Time with
+// the layout of the input's type. The date of a time input and the time of day of a
+// date input are kept from the field. An empty or invalid date doesn't change the model.
+//
+// A <select multiple> is bound to a slice field, like a []string: the options whose
+// value is in the slice are selected, and the slice is set to the values of the
+// selected options, in the order of the options.
 //
 // Usage:
 //	bind-value="Expression"
-type ValueBinder struct{ *BaseBinder }
+// Or
+//	bind-value="GetterExpression -> Setter"
+type ValueBinder struct {
+	*BaseBinder
+	value interface{}
+}
 
 // Update sets the element's value attribute to a new value
 func (b *ValueBinder) Update(d DomBind) {
-	d.Elem.SetVal(toString(d.Value))
+	b.value = d.Value
+	if isMultiSelect(d.Elem) {
+		selectValues(d.Elem.Find("option"), d.Value)
+		return
+	}
+
+	value := d.ValueString()
+	if tv, ok := inputTimeValue(d.Elem.Attr("type"), d.Value); ok {
+		value = tv
+	}
+	if c, ok := numberConstraintsOf(d.Elem); ok {
+		if cv, ok := c.apply(value); ok {
+			value = cv
+		}
+	}
+	d.Elem.SetVal(value)
 }
 
 // Watch watches for javascript change event on the element
 func (b *ValueBinder) Watch(elem jq.JQuery, ufn ModelUpdateFn) {
 	tagname := strings.ToUpper(elem.Prop("tagName").(string))
-	if tagname != "INPUT" {
+	if tagname != "INPUT" && tagname != "TEXTAREA" && tagname != "SELECT" {
 		println(tagname)
 		panic("Can only watch for changes on html input, textarea and select.")
 	}
 
+	if isMultiSelect(elem) {
+		elem.On(jq.CHANGE, func(evt jq.Event) {
+			ufn(encodeSelection(selectedValues(elem.Find("option"))))
+		})
+		return
+	}
+
+	elem.On(jq.CHANGE, func(evt jq.Event) {
+		value := elem.Val()
+		if t, ok := b.inputTime(elem.Attr("type"), value); ok {
+			value = t
+		}
+		if c, ok := numberConstraintsOf(elem); ok {
+			var valid bool
+			if value, valid = c.apply(value); !valid {
+				return
+			}
+			elem.SetVal(value)
+		}
+		ufn(value)
+	})
+}
+
+// inputTime converts the value of a date or time input bound to a time.Time field
+// to the RFC 3339 time that is written to the field, the parts of the time that
+// the input doesn't hold are kept from the field's value. ok is false if it's not
+// such an input, or the value is empty or invalid.
+func (b *ValueBinder) inputTime(typ, value string) (s string, ok bool) {
+	current, isTime := b.value.(time.Time)
+	if _, isTimeInput := inputTimeLayouts[strings.ToLower(typ)]; !isTime || !isTimeInput {
+		return
+	}
+
+	t, err := parseInputTime(typ, value, current)
+	if err != nil {
+		return
+	}
+	return t.Format(time.RFC3339Nano), true
+}
+
+func (b *ValueBinder) BindInstance() DomBinder { return new(ValueBinder) }
+func (b *ValueBinder) TwoWay()                 {}
+
+// numberConstraints are the min, max and step attributes of a number input
+type numberConstraints struct {
+	min, max, step          float64
+	hasMin, hasMax, hasStep bool
+}
+
+// newNumberConstraints parses the constraints of an input of the given type,
+// ok is false if it's not a number or range input
+func newNumberConstraints(typ, min, max, step string) (c numberConstraints, ok bool) {
+	switch strings.ToLower(typ) {
+	case "number", "range":
+	default:
+		return
+	}
+
+	parse := func(s string, v *float64, has *bool) {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err == nil {
+			*v, *has = f, true
+		}
+	}
+	parse(min, &c.min, &c.hasMin)
+	parse(max, &c.max, &c.hasMax)
+	parse(step, &c.step, &c.hasStep)
+	c.hasStep = c.hasStep && c.step > 0
+	return c, true
+}
+
+func numberConstraintsOf(elem jq.JQuery) (numberConstraints, bool) {
+	return newNumberConstraints(elem.Attr("type"), elem.Attr("min"), elem.Attr("max"), elem.Attr("step"))
+}
+
+// apply snaps the number to the step (counted from min, or 0) and clamps it between
+// min and max. It returns false if the value is not a number.
+func (c numberConstraints) apply(value string) (string, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return value, false
+	}
+
+	base := 0.0
+	if c.hasMin {
+		base = c.min
+	}
+	// snap returns the nearest multiple of step from base, or the one below if floor is true.
+	// 1e-9 compensates the floating point noise, so that 0.35/0.1 (3.4999999999999996) rounds up.
+	snap := func(f float64, floor bool) float64 {
+		if !c.hasStep {
+			return f
+		}
+		n := (f-base)/c.step + 1e-9
+		if !floor {
+			n += 0.5
+		}
+		f = base + math.Floor(n)*c.step
+		// drops the noise of the multiplication (0.1*3 = 0.30000000000000004)
+		return math.Floor(f*1e9+0.5) / 1e9
+	}
+
+	f = snap(f, false)
+	if c.hasMax && f > c.max {
+		f = snap(c.max, true)
+	}
+	if c.hasMin && f < c.min {
+		f = c.min
+	}
+
+	return strconv.FormatFloat(f, 'f', -1, 64), true
+}
+
+// CheckedBinder is a 2-way binder that binds the checked state of a checkbox
+// to a boolean model field.
+// It takes no extra dash args.
+//
+// Usage:
+//	bind-checked="BooleanExpression"
+type CheckedBinder struct{ *BaseBinder }
+
+// Update checks or unchecks the element
+func (b *CheckedBinder) Update(d DomBind) {
+	checked, ok := d.Value.(bool)
+	if !ok {
+		d.Panic(fmt.Sprintf("Wrong type %v for the checked binder, must be a bool.", reflect.TypeOf(d.Value)))
+	}
+	d.Elem.SetProp("checked", checked)
+}
+
+// Watch watches for javascript change event on the element
+func (b *CheckedBinder) Watch(elem jq.JQuery, ufn ModelUpdateFn) {
 	elem.On(jq.CHANGE, func(evt jq.Event) {
-		ufn(elem.Val())
+		ufn(strconv.FormatBool(elem.Is(":checked")))
+	})
+}
+func (b *CheckedBinder) BindInstance() DomBinder { return b }
+func (b *CheckedBinder) TwoWay()                 {}
+
+// ModelBinder is a 2-way binder that picks the suitable binder according to
+// the element's type: the checked binder for checkboxes, the value binder
+// for other inputs, textareas and selects, and the text binder for everything else.
+// It takes no extra dash args.
+//
+// Usage:
+//	bind-model="Expression"
+type ModelBinder struct {
+	binder DomBinder
+}
+
+// modelBinderFor returns the binder that bind-model uses for an element
+// with the given tag name and type attribute
+func modelBinderFor(tagname, typ string) DomBinder {
+	switch strings.ToUpper(tagname) {
+	case "INPUT":
+		if strings.ToLower(typ) == "checkbox" {
+			return &CheckedBinder{}
+		}
+		return &ValueBinder{}
+	case "TEXTAREA", "SELECT":
+		return &ValueBinder{}
+	}
+
+	return &TextBinder{}
+}
+
+func (b *ModelBinder) delegate(elem jq.JQuery) DomBinder {
+	if b.binder == nil {
+		b.binder = modelBinderFor(elem.Prop("tagName").(string), elem.Attr("type")).BindInstance()
+	}
+	return b.binder
+}
+
+func (b *ModelBinder) Bind(d DomBind) {
+	b.delegate(d.Elem).Bind(d)
+}
+
+func (b *ModelBinder) Update(d DomBind) {
+	b.delegate(d.Elem).Update(d)
+}
+
+func (b *ModelBinder) Watch(elem jq.JQuery, ufn ModelUpdateFn) {
+	b.delegate(elem).Watch(elem, ufn)
+}
+func (b *ModelBinder) BindInstance() DomBinder { return new(ModelBinder) }
+func (b *ModelBinder) TwoWay()                 {}
+
+// HTML is a string of html markup. When a helper or method returns an HTML,
+// the text binder inserts it as markup instead of escaped text.
+type HTML string
+
+// TextBinder is a 1-way binder that binds an element's text content to
+// the value of a model field. Unlike HtmlBinder, the value is escaped,
+// except for an HTML value that's inserted as markup, like badges or icons
+// built by a helper. With the "bind" dash arg, the bind attributes in the
+// inserted markup are bound too, in the scope of the element.
+//
+// Usage:
+//	bind-text="Expression"
+// Or
+//	bind-text-bind="HTMLExpression"
+type TextBinder struct {
+	BaseBinder
+	rebind bool
+}
+
+func (b *TextBinder) Bind(d DomBind) {
+	for _, arg := range d.Args {
+		if arg != "bind" {
+			d.Panic(`Unknown dash arg "` + arg + `".`)
+		}
+		b.rebind = true
+	}
+}
+
+// textContent returns the content of the text binder for the value,
+// and whether it's markup
+func textContent(d DomBind) (content string, isHTML bool) {
+	if h, ok := d.Value.(HTML); ok {
+		return string(h), true
+	}
+	return d.ValueString(), false
+}
+
+// Update sets the element's text content to a new value
+func (b *TextBinder) Update(d DomBind) {
+	content, isHTML := textContent(d)
+	if !isHTML {
+		d.Elem.SetText(content)
+		return
+	}
+
+	if !b.rebind {
+		d.Elem.SetHtml(content)
+		return
+	}
+
+	d.binding.Teardown(d.Elem.Children("*"))
+	d.Elem.SetHtml(content)
+	d.bind(d.Elem, nil, d.once, false)
+}
+func (b *TextBinder) BindInstance() DomBinder { return new(TextBinder) }
+
+// EditableBinder is a 2-way binder for contenteditable elements, it binds
+// the element's text content, or its html content with the "html" extra dash arg.
+// The edits are written to the model on each input event, converted to the type
+// of the field like for the value binder. The content is only replaced when it
+// differs from the new value, so that the caret doesn't jump while typing.
+//
+// Usage:
+//	bind-editable="Expression"
+//	bind-editable-html="Expression"
+type EditableBinder struct {
+	BaseBinder
+	html bool
+}
+
+// editableMode returns whether the editable binder with the given args binds html content
+func editableMode(args []string) (html bool, err error) {
+	switch {
+	case len(args) == 0:
+		return false, nil
+	case len(args) == 1 && args[0] == "html":
+		return true, nil
+	}
+	return false, fmt.Errorf(`Invalid extra dash args %v for the editable binder, only "html" is allowed.`, args)
+}
+
+func (b *EditableBinder) content(elem jq.JQuery) string {
+	if b.html {
+		return elem.Html()
+	}
+	return elem.Text()
+}
+
+func (b *EditableBinder) Bind(d DomBind) {
+	var err error
+	b.html, err = editableMode(d.Args)
+	if err != nil {
+		d.Panic(err.Error())
+	}
+
+	d.Elem.SetAttr("contenteditable", "true")
+}
+
+// Update sets the element's content to the new value, unless it's already the same
+func (b *EditableBinder) Update(d DomBind) {
+	value := d.ValueString()
+	if b.content(d.Elem) == value {
+		return
+	}
+
+	if b.html {
+		d.Elem.SetHtml(value)
+	} else {
+		d.Elem.SetText(value)
+	}
+}
+
+// Watch watches for javascript input events on the element
+func (b *EditableBinder) Watch(elem jq.JQuery, ufn ModelUpdateFn) {
+	elem.On("input", func(evt jq.Event) {
+		ufn(b.content(elem))
 	})
 }
-func (b *ValueBinder) BindInstance() DomBinder { return b }
+func (b *EditableBinder) BindInstance() DomBinder { return new(EditableBinder) }
+func (b *EditableBinder) TwoWay()                 {}
 
 // HtmlBinder is a 1-way binder that binds an element's html content to
 // the value of a model field.
@@ -62,63 +432,311 @@ type HtmlBinder struct{ BaseBinder }
 
 // Update sets the element's html content to a new value
 func (b *HtmlBinder) Update(d DomBind) {
-	d.Elem.SetHtml(toString(d.Value))
+	d.Elem.SetHtml(d.ValueString())
 }
 func (b *HtmlBinder) BindInstance() DomBinder { return b }
 
+type AttrPolicy int
+
+const (
+	// AttrOmitWhenFalse renders a bool value by removing the attribute when it's false,
+	// and setting it to an empty value when it's true, like html boolean attributes
+	AttrOmitWhenFalse AttrPolicy = iota
+	// AttrBoolify renders a bool value as "true" or "false", like ARIA attributes
+	AttrBoolify
+)
+
+// boolifiedAttrs are the attributes other than ARIA's that take "true" or "false" values
+var boolifiedAttrs = map[string]bool{
+	"contenteditable": true,
+	"draggable":       true,
+	"spellcheck":      true,
+}
+
+// defaultAttrPolicy returns the policy used to render bool values for the attribute,
+// aria-* attributes and a few others are boolified, the others are omitted when false
+func defaultAttrPolicy(attr string) AttrPolicy {
+	attr = strings.ToLower(attr)
+	if strings.HasPrefix(attr, "aria-") || boolifiedAttrs[attr] {
+		return AttrBoolify
+	}
+
+	return AttrOmitWhenFalse
+}
+
+// renderAttr returns the attribute value for a bound value according to the policy,
+// and whether the attribute should be present
+func renderAttr(policy AttrPolicy, value interface{}, format func(interface{}) string) (string, bool) {
+	on, isBool := value.(bool)
+	switch {
+	case !isBool:
+		return format(value), true
+	case policy == AttrBoolify:
+		return strconv.FormatBool(on), true
+	}
+
+	return "", on
+}
+
 // AttrBinder is a 1-way binder that binds a specified element's attribute
 // to a model field value.
-// It takes 1 extra dash arg that is the name of the html attribute to be bound.
+// It takes the name of the html attribute to be bound as extra dash args.
+// Inside <svg>, the case of SVG attributes (like viewBox) is restored, since
+// html attribute names are lowercased, and namespaced attributes (like xlink:href)
+// are set with their namespace.
+//
+// Bool values are rendered according to the attribute's policy: ARIA attributes
+// (like aria-expanded) get "true" or "false", other attributes are removed when
+// false. The policy can be changed with SetPolicy.
 //
 // Usage:
 //	bind-attr-thatAttribute="Expression"
-type AttrBinder struct{ BaseBinder }
+type AttrBinder struct {
+	BaseBinder
+	policies map[string]AttrPolicy
+}
+
+// SetPolicy sets the policy for rendering bool values to the given attribute
+func (b *AttrBinder) SetPolicy(attr string, policy AttrPolicy) {
+	if b.policies == nil {
+		b.policies = make(map[string]AttrPolicy)
+	}
+	b.policies[strings.ToLower(attr)] = policy
+}
+
+func (b *AttrBinder) policy(attr string) AttrPolicy {
+	if policy, ok := b.policies[strings.ToLower(attr)]; ok {
+		return policy
+	}
+	return defaultAttrPolicy(attr)
+}
 
 func (b *AttrBinder) Update(d DomBind) {
-	if len(d.Args) != 1 {
+	if len(d.Args) == 0 {
 		panic(fmt.Sprintf(`Incorrect number of args %v for html attribute binder.
 Usage: bind-attr-thatAttribute="Field".`, len(d.Args)))
 	}
-	d.Elem.SetAttr(d.Args[0], toString(d.Value))
+
+	attr := strings.Join(d.Args, "-")
+	value, present := renderAttr(b.policy(attr), d.Value, func(interface{}) string {
+		return d.ValueString()
+	})
+
+	if d.Elem.Closest("svg").Length > 0 {
+		name, ns := svgAttrName(attr)
+		switch {
+		case !present && ns != "":
+			d.Elem.Get(0).Call("removeAttributeNS", ns, name[strings.Index(name, ":")+1:])
+		case !present:
+			d.Elem.Get(0).Call("removeAttribute", name)
+		case ns != "":
+			d.Elem.Get(0).Call("setAttributeNS", ns, name, value)
+		default:
+			d.Elem.Get(0).Call("setAttribute", name, value)
+		}
+		return
+	}
+
+	if !present {
+		d.Elem.RemoveAttr(attr)
+		return
+	}
+	d.Elem.SetAttr(attr, value)
 }
 func (b *AttrBinder) BindInstance() DomBinder { return b }
 
+// SetAttrPolicy sets the policy of the attr binder for rendering bool values to the given attribute
+func (b *Binding) SetAttrPolicy(attr string, policy AttrPolicy) {
+	b.domBinders["attr"].(*AttrBinder).SetPolicy(attr, policy)
+}
+
+// HiddenBinder is a 1-way binder that sets the html hidden attribute of the element
+// when the bool value is true, and removes it otherwise. Unlike hiding with css,
+// the hidden attribute also hides the element from assistive technologies. It only
+// touches the attribute, so a css display rule for the element still applies over it.
+// It takes no extra dash args.
+//
+// Usage:
+//	bind-hidden="BooleanExpression"
+type HiddenBinder struct{ BaseBinder }
+
+func (b *HiddenBinder) Update(d DomBind) {
+	if _, ok := d.Value.(bool); !ok {
+		d.Panic(fmt.Sprintf("Wrong type %v for the hidden binder, must be a bool.", reflect.TypeOf(d.Value)))
+	}
+
+	if _, present := renderAttr(AttrOmitWhenFalse, d.Value, nil); present {
+		d.Elem.SetAttr("hidden", "")
+	} else {
+		d.Elem.RemoveAttr("hidden")
+	}
+}
+func (b *HiddenBinder) BindInstance() DomBinder { return b }
+
+// LoadingBinder is a 1-way binder that shows the element only while an async
+// source is in the given state, and hides it otherwise.
+// It takes the state as an optional extra dash arg, which is "pending" (the default),
+// "resolved" or "error". The value must be an AsyncStatus or an AsyncSource, like AsyncState.
+//
+// Usage:
+//	bind-loading="AsyncSource"
+//	bind-loading-resolved="AsyncSource"
+//	bind-loading-error="AsyncSource"
+type LoadingBinder struct{ BaseBinder }
+
+func (b *LoadingBinder) Update(d DomBind) {
+	visible, err := loadingVisible(strings.Join(d.Args, "-"), d.Value)
+	if err != nil {
+		d.Panic(err.Error())
+	}
+
+	if visible {
+		d.Elem.Show()
+	} else {
+		d.Elem.Hide()
+	}
+}
+func (b *LoadingBinder) BindInstance() DomBinder { return b }
+
 // EventBinder is a 1-way binder that binds a method of the model to an event
 // that occurs on the element.
 // It takes 1 extra dash arg that is the event name, for example "click",
 // "change",...
+// The handler may be a method or a function-valued field, of type func() or
+// func(jquery.Event) to receive the event. A call expression is evaluated
+// at bind time, so it must return the handler.
 //
 // Usage:
 //	bind-on-thatEventName="HandlerMethod"
+//	bind-on-thatEventName="HandlerField"
+//	bind-on-thatEventName="MethodReturningHandler(arg1, arg2...)"
+// The event name may be followed by modifier dash args: "prevent" prevents the
+// default action, "stop" stops the propagation of the event and "self" only handles
+// the events whose target is the element itself, the other events are left untouched.
+//	bind-on-submit-prevent="Save"
+//	bind-on-click-stop-self="Close"
 type EventBinder struct{ BaseBinder }
 
-func (b *EventBinder) Bind(d DomBind) {
-	fni := d.Value
-	if fni == nil {
-		d.Panic("Event must be bound to a function, not a nil. If you're trying to call a function on this event, please use a method that returns a func().")
+// eventModifiers are the modifier dash args of the event binder
+type eventModifiers struct {
+	prevent bool
+	stop    bool
+	self    bool
+}
+
+// domEvent is the part of an event that the modifiers act on
+type domEvent interface {
+	PreventDefault()
+	StopPropagation()
+}
+
+func parseEventModifiers(args []string) (event string, mods eventModifiers, err error) {
+	if len(args) == 0 {
+		return "", mods, fmt.Errorf("Event bind needs the event name as its dash argument.")
 	}
-	fn, ok := fni.(func())
-	if !ok {
-		panic(fmt.Sprintf("Wrong type %v for EventBinder's handler, must be of type func().",
-			reflect.TypeOf(fni).String()))
+
+	event = args[0]
+	for _, arg := range args[1:] {
+		switch arg {
+		case "prevent":
+			mods.prevent = true
+		case "stop":
+			mods.stop = true
+		case "self":
+			mods.self = true
+		default:
+			return "", mods, fmt.Errorf(`Unknown event modifier "%v".`, arg)
+		}
 	}
-	if len(d.Args) > 1 {
-		panic("Too many dash arguments to event bind.")
+	return
+}
+
+// handle applies the modifiers to the event and calls the handler,
+// fromSelf tells whether the event's target is the element itself
+func (m eventModifiers) handle(evt domEvent, fromSelf bool, handler func()) {
+	if m.self && !fromSelf {
+		return
 	}
-	d.Elem.On(d.Args[0], func(evt jq.Event) {
+
+	if m.prevent {
 		evt.PreventDefault()
-		fn()
+	}
+	if m.stop {
+		evt.StopPropagation()
+	}
+	handler()
+}
+
+var jqEventType = reflect.TypeOf(jq.Event{})
+
+// eventHandler converts a handler bound by the event binder to a jquery event handler
+func eventHandler(fni interface{}) (func(jq.Event), error) {
+	switch fn := fni.(type) {
+	case nil:
+		return nil, fmt.Errorf("Event must be bound to a function, not a nil. If you're trying to call a function on this event, please use a method that returns a func().")
+	case func():
+		if fn == nil {
+			break
+		}
+		return func(jq.Event) { fn() }, nil
+	case func(jq.Event):
+		if fn == nil {
+			break
+		}
+		return fn, nil
+	default:
+		fv := reflect.ValueOf(fni)
+		if fv.Kind() != reflect.Func {
+			return nil, fmt.Errorf("Wrong type %v for EventBinder's handler, must be of type func() or func(jquery.Event).",
+				fv.Type().String())
+		}
+		if fv.IsNil() {
+			break
+		}
+
+		ftype := fv.Type()
+		switch {
+		case ftype.NumIn() == 0:
+			return func(jq.Event) { fv.Call([]reflect.Value{}) }, nil
+		case ftype.NumIn() == 1 && jqEventType.AssignableTo(ftype.In(0)):
+			return func(evt jq.Event) { fv.Call([]reflect.Value{reflect.ValueOf(evt)}) }, nil
+		}
+		return nil, fmt.Errorf("Wrong type %v for EventBinder's handler, must be of type func() or func(jquery.Event).",
+			ftype.String())
+	}
+
+	return nil, fmt.Errorf("The handler function is nil.")
+}
+
+func (b *EventBinder) Bind(d DomBind) {
+	fn, err := eventHandler(d.Value)
+	if err != nil {
+		d.Panic(err.Error())
+	}
+	event, mods, err := parseEventModifiers(d.Args)
+	if err != nil {
+		d.Panic(err.Error())
+	}
+	elem := d.Elem
+	d.Elem.On(event, func(evt jq.Event) {
+		mods.handle(evt, mods.self && elem.Is(evt.Target), func() {
+			fn(evt)
+		})
 	})
 }
 func (b *EventBinder) BindInstance() DomBinder { return b }
 
-type indexFunc func(i int, v reflect.Value) (interface{}, reflect.Value)
+// indexFunc returns the function giving the key and value of the i-th item of a collection
+type indexFunc func(v reflect.Value) func(i int) (interface{}, reflect.Value)
 
 // EachBinder is a 1-way binder that repeats an element according to a map
 // or slice. It outputs a key and a value bound to each item.
-// It takes no extra dash arg. The extra output after "->" are the names that
+// It takes an optional "flip" dash arg. The extra output after "->" are the names that
 // receives the key and value, those names can be used inside the elment's
-// content. Each key and value pair is bound separately to each element.
+// content. Each key and value pair is bound separately to each element, they're
+// also available as $key and $value, with or without the outputs.
+// The entries of a map are repeated in the order of their sorted keys.
+// Inserting to a map is not observed, see PollAttr.
 //
 // Usage:
 //	bind-each="Expression"
@@ -129,12 +747,30 @@ type indexFunc func(i int, v reflect.Value) (interface{}, reflect.Value)
 //		<p>Error type: <% type %></p>
 //		<p>Message: <% msg %></p>
 //	</div>
+// A separator can be inserted between the items (but not after the last one)
+// with SeparatorAttr or a SeparatorTag child, see itemSeparator.
+//
+// With the "flip" dash arg, the items that move when the collection is reordered
+// transition from their old positions, the items are identified by KeyAttr.
+//	bind-each-flip="Expression -> outputKey, outputValue"
+// With the "transition" dash arg, the items that are added and removed get the
+// enter and leave classes, see TransitionAttr. Both can be combined.
+//	bind-each-flip-transition="Expression -> outputKey, outputValue"
 type EachBinder struct {
 	*BaseBinder
 	marker    jq.JQuery
 	prototype jq.JQuery
+	separator jq.JQuery
 	indexFn   indexFunc
-	size      int
+	items     []jq.JQuery
+
+	flip        *flip
+	transitions *transitionGroup
+	keyExpr     string
+	keys        []interface{}
+	keyNodes    []jq.JQuery
+	// keyIndex is the index in items of the nodes of each key
+	keyIndex []int
 }
 
 func (b *EachBinder) BindInstance() DomBinder {
@@ -145,24 +781,101 @@ func getIndexFunc(value interface{}) indexFunc {
 	kind := reflect.TypeOf(value).Kind()
 	switch kind {
 	case reflect.Slice:
-		return func(i int, val reflect.Value) (interface{}, reflect.Value) {
-			return i, val.Index(i)
+		return func(val reflect.Value) func(int) (interface{}, reflect.Value) {
+			return func(i int) (interface{}, reflect.Value) {
+				item := val.Index(i)
+				// struct items are passed by reference so that field writes and
+				// methods with pointer receivers affect the slice element itself
+				if item.Kind() == reflect.Struct {
+					item = item.Addr()
+				}
+				return i, item
+			}
 		}
 	case reflect.Map:
-		return func(i int, val reflect.Value) (interface{}, reflect.Value) {
-			key := val.MapKeys()[i]
-			return key.String(), val.MapIndex(key)
+		return func(val reflect.Value) func(int) (interface{}, reflect.Value) {
+			keys := sortedMapKeys(val)
+			return func(i int) (interface{}, reflect.Value) {
+				return keys[i].Interface(), val.MapIndex(keys[i])
+			}
 		}
 	default:
 		panic(fmt.Sprintf("Wrong kind %v of target for the each binder, must be a slice or map.", kind.String()))
 	}
 }
 
+// mapKeys sorts the keys of a map, by value for the numbers and strings
+// and by their formatting otherwise
+type mapKeys []reflect.Value
+
+func (k mapKeys) Len() int      { return len(k) }
+func (k mapKeys) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k mapKeys) Less(i, j int) bool {
+	a, b := unwrapValue(k[i]), unwrapValue(k[j])
+	switch {
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return a.String() < b.String()
+	case isUintKind(a.Kind()) && isUintKind(b.Kind()):
+		return a.Uint() < b.Uint()
+	case isIntKind(a.Kind()) && isIntKind(b.Kind()):
+		return toInt64(a) < toInt64(b)
+	}
+
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			return fa < fb
+		}
+	}
+	return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+}
+
+func sortedMapKeys(val reflect.Value) []reflect.Value {
+	keys := mapKeys(val.MapKeys())
+	sort.Sort(keys)
+	return keys
+}
+
+// eachItemModel returns the model that an item of the each binder is bound to,
+// with the outputs and the $key and $value symbols
+func eachItemModel(d DomBind, k, v interface{}) map[string]interface{} {
+	if len(d.outputs) != 0 && len(d.outputs) != 2 {
+		panic(fmt.Errorf("Wrong output specification for `%v`: there must be 2 outputs instead of %v.",
+			d.metadata, len(d.outputs)))
+	}
+
+	m := map[string]interface{}{
+		"$key":   k,
+		"$value": v,
+	}
+	if len(d.outputs) == 2 {
+		m[d.outputs[0]] = k
+		m[d.outputs[1]] = v
+	}
+	return m
+}
+
 func (b *EachBinder) Bind(d DomBind) {
-	d.Elem.RemoveAttr(BindPrefix + "each")
+	d.Elem.RemoveAttr(BindPrefix + strings.Join(append([]string{"each"}, d.Args...), "-"))
 	b.indexFn = getIndexFunc(d.Value)
 	b.marker = gJQ("<!-- wade each -->").InsertBefore(d.Elem).First()
 	b.prototype = d.Elem.Clone()
+	b.prototype.RemoveAttr(elemIdAttr)
+	b.separator = itemSeparator(b.prototype)
+	for _, arg := range d.Args {
+		switch arg {
+		case "flip":
+			b.flip = &flip{}
+		case "transition":
+			b.transitions = newTransitionGroup(d.binding.browser, b.prototype.Attr(TransitionAttr))
+			b.prototype.RemoveAttr(TransitionAttr)
+		default:
+			d.Panic(fmt.Sprintf(`Unknown dash arg "%v".`, arg))
+		}
+	}
+	if len(d.Args) > 0 {
+		b.keyExpr = b.prototype.Attr(KeyAttr)
+		b.prototype.RemoveAttr(KeyAttr)
+	}
 	d.RemoveBinding(d.Elem)
 	d.Elem.Remove()
 }
@@ -170,26 +883,244 @@ func (b *EachBinder) Bind(d DomBind) {
 func (b *EachBinder) Update(d DomBind) {
 	val := reflect.ValueOf(d.Value)
 
-	for i := val.Len(); i < b.size; i++ {
-		b.marker.Next().Remove()
+	// the items are identified by their keys for the flip and the transitions
+	keyed := b.flip != nil || b.transitions != nil
+	itemAt := b.indexFn(val)
+	var keys []interface{}
+	if keyed {
+		keys = make([]interface{}, val.Len())
+		for i := range keys {
+			k, v := itemAt(i)
+			keys[i] = itemKey(b.keyExpr, d.scope, val, i, k, v)
+		}
+	}
+
+	if b.flip != nil {
+		b.flip.record(b.keys, b.keyNodes)
+	}
+	// the leaving items are removed at the end of their transition
+	kept := make(map[int]bool)
+	if b.transitions != nil {
+		for i := range b.transitions.leave(b.keys, b.keyNodes, keys) {
+			kept[b.keyIndex[i]] = true
+		}
+	}
+
+	oldKeys := b.keys
+	b.keys, b.keyNodes, b.keyIndex = keys, make([]jq.JQuery, 0, len(keys)), make([]int, 0, len(keys))
+	b.items = d.binding.removeItemsExcept(b.items, kept)
+	prev := b.marker
+	for i := 0; i < val.Len(); i++ {
+		if sep, ok := insertSeparator(b.separator, prev, i); ok {
+			b.items = append(b.items, sep)
+			prev = lastItemNode(sep, prev)
+		}
+
+		k, v := itemAt(i)
+		nx := b.prototype.Clone()
+		prev.After(nx)
+		d.bind(nx, eachItemModel(d, k, v.Interface()), true, true)
+		nodes := d.Unwrap(nx)
+		if keyed {
+			b.keyIndex = append(b.keyIndex, len(b.items))
+			b.keyNodes = append(b.keyNodes, nodes)
+		}
+		b.items = append(b.items, nodes)
+		prev = lastItemNode(nodes, prev)
 	}
 
-	for i := b.size; i < val.Len(); i++ {
-		b.marker.After(b.prototype.Clone())
+	if b.flip != nil {
+		b.flip.play(b.keys, b.keyNodes)
+	}
+	if b.transitions != nil {
+		b.transitions.enter(oldKeys, b.keys, b.keyNodes)
 	}
+}
 
-	b.size = val.Len()
+// removeItems tears down and removes the rendered nodes of the items of a repeating binder
+func (b *Binding) removeItems(items []jq.JQuery) []jq.JQuery {
+	return b.removeItemsExcept(items, nil)
+}
 
+// removeItemsExcept is like removeItems, but the items with the given indexes are
+// left in the document, like the items being removed by a transition. They're torn
+// down all the same.
+func (b *Binding) removeItemsExcept(items []jq.JQuery, kept map[int]bool) []jq.JQuery {
+	for i, nodes := range items {
+		b.teardown(nodes, true)
+		if !kept[i] {
+			nodes.Remove()
+		}
+	}
+	return items[:0]
+}
+
+const (
+	// SeparatorAttr sets a text separator between the items of a repeating binder
+	//
+	// Usage:
+	//	<a bind-each="Crumbs -> _, c" wade-separator=" / " bind-attr-href="c.Url"><% c.Title %></a>
+	SeparatorAttr = "wade-separator"
+
+	// SeparatorTag is a child of the repeated element whose contents are inserted
+	// between the items, as a separator template
+	//
+	// Usage:
+	//	<li bind-list="Tags"><% $item %><separator><li class="divider"></li></separator></li>
+	SeparatorTag = "separator"
+)
+
+// itemSeparator takes the separator out of the prototype of a repeating binder,
+// the returned nodes are cloned for each separator. It's empty if there's none.
+func itemSeparator(prototype jq.JQuery) (sep jq.JQuery) {
+	if tmpl := prototype.Children(SeparatorTag); tmpl.Length > 0 {
+		tmpl.Remove()
+		return tmpl.First().Contents()
+	}
+
+	if prototype.Is("[" + SeparatorAttr + "]") {
+		text := prototype.Attr(SeparatorAttr)
+		prototype.RemoveAttr(SeparatorAttr)
+		return gJQ("<span></span>").SetText(text).Contents()
+	}
+
+	return
+}
+
+// insertSeparator inserts a clone of the separator after prev, before the item
+// with index i, unless it's the first item
+func insertSeparator(sep, prev jq.JQuery, i int) (nodes jq.JQuery, ok bool) {
+	if i == 0 || sep.Length == 0 {
+		return
+	}
+
+	nodes = sep.Clone()
+	prev.After(nodes)
+	return nodes, true
+}
+
+// lastItemNode returns the node after which the next item is inserted
+func lastItemNode(nodes, prev jq.JQuery) jq.JQuery {
+	if nodes.Length == 0 {
+		return prev
+	}
+	return nodes.Last()
+}
+
+// ListBinder is a 1-way binder that repeats an element for each item of a slice
+// of primitive values (strings, numbers,...). It's a simpler version of EachBinder,
+// the item is available as $item inside the element and its index as $index.
+// Separators are supported like for EachBinder.
+// It takes no extra dash arg.
+//
+// Usage:
+//	bind-list="Expression"
+// Example:
+//	<span class="chip" bind-list="Tags"><% $item %></span>
+type ListBinder struct {
+	BaseBinder
+	marker    jq.JQuery
+	prototype jq.JQuery
+	separator jq.JQuery
+	items     []jq.JQuery
+}
+
+// listItems returns the models that the list items are bound to
+func listItems(value interface{}) []map[string]interface{} {
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+	case reflect.Invalid:
+		return []map[string]interface{}{}
+	default:
+		panic(fmt.Sprintf("Wrong kind %v of target for the list binder, must be a slice.", val.Kind()))
+	}
+
+	items := make([]map[string]interface{}, val.Len())
+	for i := range items {
+		items[i] = map[string]interface{}{
+			"$item":  val.Index(i).Interface(),
+			"$index": i,
+		}
+	}
+	return items
+}
+
+func (b *ListBinder) Bind(d DomBind) {
+	d.Elem.RemoveAttr(BindPrefix + "list")
+	b.marker = gJQ("<!-- wade list -->").InsertBefore(d.Elem).First()
+	b.prototype = d.Elem.Clone()
+	b.prototype.RemoveAttr(elemIdAttr)
+	b.separator = itemSeparator(b.prototype)
+	d.RemoveBinding(d.Elem)
+	d.Elem.Remove()
+}
+
+func (b *ListBinder) Update(d DomBind) {
+	b.items = d.binding.removeItems(b.items)
 	prev := b.marker
+	for i, item := range listItems(d.Value) {
+		if sep, ok := insertSeparator(b.separator, prev, i); ok {
+			b.items = append(b.items, sep)
+			prev = lastItemNode(sep, prev)
+		}
 
-	for i := 0; i < b.size; i++ {
-		k, v := b.indexFn(i, val)
 		nx := b.prototype.Clone()
-		prev.Next().ReplaceWith(nx)
-		d.ProduceOutputs(nx, true, true, k, v.Interface())
-		prev = nx
+		prev.After(nx)
+		d.bind(nx, item, true, true)
+		nodes := d.Unwrap(nx)
+		b.items = append(b.items, nodes)
+		prev = lastItemNode(nodes, prev)
 	}
 }
+func (b *ListBinder) BindInstance() DomBinder { return new(ListBinder) }
+
+// RefBinder assigns the element to a model field, for imperative DOM work
+// like drawing on a canvas or measuring. The field must be of type jquery.JQuery,
+// js.Object (the DOM node) or interface{}. It's reset to its zero value when the
+// element is torn down.
+//
+// Usage:
+//	bind-ref="Field"
+type RefBinder struct{ BaseBinder }
+
+// refField returns the model field that the element is assigned to
+func refField(d DomBind) *objEval {
+	if len(d.binds) != 1 {
+		d.Panic("The ref binder must be bound to exactly 1 model field.")
+	}
+
+	oe := d.binds[0].bindObj()
+	if !oe.canSet() {
+		d.Panic("The field of the ref binder cannot be set.")
+	}
+	return oe
+}
+
+func (b *RefBinder) Bind(d DomBind) {
+	oe := refField(d)
+	var ref interface{}
+	switch oe.typ() {
+	case jqueryType, emptyInterfaceType:
+		ref = d.Elem
+	case jsObjectType:
+		ref = d.Elem.Get(0)
+	default:
+		d.Panic(fmt.Sprintf("Wrong type %v of the field for the ref binder, must be jquery.JQuery or js.Object.", oe.typ()))
+	}
+
+	v := reflect.ValueOf(ref)
+	if !v.IsValid() {
+		v = reflect.Zero(oe.typ())
+	}
+	oe.set(v)
+}
+
+func (b *RefBinder) Teardown(d DomBind) {
+	oe := refField(d)
+	oe.set(reflect.Zero(oe.typ()))
+}
+func (b *RefBinder) BindInstance() DomBinder { return b }
 
 // PageBinder is used for <a> elements to set its href to the real page url
 // and save necessary information for the proper page switching when the user
@@ -209,6 +1140,68 @@ func (b *PageBinder) Update(d DomBind) {
 }
 func (b *PageBinder) BindInstance() DomBinder { return b }
 
+// IsBinder renders a custom element whose tag name is the value of an expression,
+// it renders the new custom element whenever the value changes.
+// The element's contents and its attribute binding ("bind" attribute) are used for
+// the rendered custom element, as if it was declared with the tag name.
+// It takes no extra dash args.
+//
+// Usage:
+//	<anytag bind-is="TagNameExpression" bind="Field: Expression">contents</anytag>
+type IsBinder struct {
+	*BaseBinder
+	marker    jq.JQuery
+	prototype jq.JQuery
+	instance  jq.JQuery
+	rendered  jq.JQuery
+}
+
+func (b *IsBinder) Bind(d DomBind) {
+	if _, ok := d.binding.tm.(NamedTagSource); !ok {
+		d.Panic("No custom element manager that looks up the tags by name is available for the is binder.")
+	}
+
+	d.Elem.RemoveAttr(BindPrefix + "is")
+	b.marker = gJQ("<!-- wade is -->").InsertBefore(d.Elem).First()
+	b.prototype = d.Elem.Clone()
+	b.prototype.RemoveAttr(elemIdAttr)
+	d.RemoveBinding(d.Elem)
+	d.Elem.Remove()
+}
+
+func (b *IsBinder) Update(d DomBind) {
+	tagname := toString(d.Value)
+	custag, ok := d.binding.tm.(NamedTagSource).GetCustomTagByName(tagname)
+	if !ok {
+		d.Panic(fmt.Sprintf(`"%v" is not a registered custom element tag.`, tagname))
+	}
+
+	elem := b.prototype.Clone()
+	model := custag.NewModel(elem)
+	if bstr := elem.Attr("bind"); bstr != "" {
+		d.binding.processAttrBind("bind", bstr, elem, &bindScope{d.scope}, false, model)
+	}
+
+	err := custag.PrepareTagContents(elem, model)
+	if err != nil {
+		elemError(elem, err.Error())
+	}
+
+	if b.rendered.Length > 0 {
+		d.binding.Teardown(b.instance)
+		d.binding.Teardown(b.rendered)
+		b.rendered.Remove()
+	}
+
+	// like a custom element, it's bound in place then replaced by its contents
+	b.marker.After(elem)
+	d.binding.bindNested(elem, model, false, d.scope)
+	b.instance = elem
+	b.rendered = elem.Contents()
+	d.binding.replaceElem(elem, b.rendered)
+}
+func (b *IsBinder) BindInstance() DomBinder { return new(IsBinder) }
+
 // IfBinder keeps or remove an element according to a boolean field value.
 //
 // Usage:
@@ -248,3 +1241,25 @@ func (b *UnlessBinder) Update(d DomBind) {
 	b.IfBinder.Update(d)
 }
 func (b *UnlessBinder) BindInstance() DomBinder { return &UnlessBinder{&IfBinder{}} }
+
+// InitBinder calls a method (or helper) once when the element is bound, for
+// imperative setup like drawing a chart. The call is made after the Bind call
+// completes, when the element is in place. It's not called again when the model changes.
+// It takes no extra dash args.
+//
+// Usage:
+//	bind-init="MethodCall()"
+// Example:
+//	<canvas bind-ref="Canvas" bind-init="InitChart()"></canvas>
+type InitBinder struct{ BaseBinder }
+
+func (b *InitBinder) OneShot() {}
+
+func (b *InitBinder) Bind(d DomBind) {
+	d.binding.afterBind(func() {
+		if _, err := d.Eval(d.expr); err != nil {
+			d.Panic(err.Error())
+		}
+	})
+}
+func (b *InitBinder) BindInstance() DomBinder { return b }
@@ -0,0 +1,962 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gopherjs/gopherjs/js"
+	jq "github.com/gopherjs/jquery"
+)
+
+func TestModelBinderFor(t *testing.T) {
+	tests := []struct {
+		tagname string
+		typ     string
+		binder  DomBinder
+	}{
+		{"input", "text", &ValueBinder{}},
+		{"INPUT", "", &ValueBinder{}},
+		{"input", "checkbox", &CheckedBinder{}},
+		{"INPUT", "Checkbox", &CheckedBinder{}},
+		{"select", "", &ValueBinder{}},
+		{"textarea", "", &ValueBinder{}},
+		{"span", "", &TextBinder{}},
+	}
+
+	for _, test := range tests {
+		binder := modelBinderFor(test.tagname, test.typ)
+		if reflect.TypeOf(binder) != reflect.TypeOf(test.binder) {
+			t.Errorf("Expected %v for <%v type=%q>, got %v.",
+				reflect.TypeOf(test.binder), test.tagname, test.typ, reflect.TypeOf(binder))
+		}
+	}
+}
+
+func TestConvertString(t *testing.T) {
+	type State string
+	tests := []struct {
+		str      string
+		expected interface{}
+	}{
+		{"text", "text"},
+		{"true", true},
+		{"false", false},
+		{"42", 42},
+		{"-3", int64(-3)},
+		{"7", uint8(7)},
+		{"1.5", float32(1.5)},
+		{"editing", State("editing")},
+	}
+
+	for _, test := range tests {
+		v, err := convertString(test.str, reflect.TypeOf(test.expected))
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		if v.Interface() != test.expected {
+			t.Errorf("Expected %#v, got %#v.", test.expected, v.Interface())
+		}
+	}
+
+	errtests := []struct {
+		str string
+		typ reflect.Type
+	}{
+		{"yes", reflect.TypeOf(true)},
+		{"1a", reflect.TypeOf(0)},
+		{"256", reflect.TypeOf(uint8(0))},
+		{"a", reflect.TypeOf([]string{})},
+	}
+	for _, et := range errtests {
+		if _, err := convertString(et.str, et.typ); err == nil {
+			t.Errorf("Expected an error converting %q to %v, no error is returned.", et.str, et.typ)
+		}
+	}
+}
+
+type testItem struct {
+	Name string
+	Done bool
+}
+
+func (it *testItem) ToggleDone() {
+	it.Done = !it.Done
+}
+
+func TestEachItemByReference(t *testing.T) {
+	b := NewBindEngine(nil)
+	items := []testItem{{"a", false}, {"b", false}}
+	pitems := []*testItem{&testItem{"c", false}}
+
+	for _, list := range []interface{}{items, pitems} {
+		val := reflect.ValueOf(list)
+		itemAt := getIndexFunc(list)(val)
+		for i := 0; i < val.Len(); i++ {
+			_, item := itemAt(i)
+			model := map[string]interface{}{"entry": item.Interface()}
+			if _, _, _, err := b.evaluate("entry.ToggleDone()", model); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			_, blist, _, err := b.evaluate("entry.Name", model)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			field := blist[0].bindObj().fieldRefl
+			if !field.CanSet() {
+				t.Fatalf("The field of the item is not settable.")
+			}
+			field.SetString("changed")
+		}
+	}
+
+	for _, item := range append([]*testItem{&items[0], &items[1]}, pitems...) {
+		if !item.Done || item.Name != "changed" {
+			t.Errorf("Expected the backing slice element to be mutated, got %+v.", *item)
+		}
+	}
+}
+
+func TestEachMap(t *testing.T) {
+	b := NewBindEngine(nil)
+	scores := map[string]int{"carol": 7, "alice": 9, "bob": 3}
+
+	render := func(d DomBind) []string {
+		val := reflect.ValueOf(scores)
+		itemAt := getIndexFunc(scores)(val)
+		rendered := make([]string, 0)
+		for i := 0; i < val.Len(); i++ {
+			k, v := itemAt(i)
+			s := newModelScope(eachItemModel(d, k, v.Interface()))
+			s.merge(b.scope)
+			_, _, text, err := (&bindScope{s}).evaluate("concat($key, concat(`-`, toString($value)))")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			rendered = append(rendered, text.(string))
+		}
+		return rendered
+	}
+	b.RegisterHelper("toString", func(v interface{}) string { return toString(v) })
+
+	d := DomBind{}
+	if r := render(d); !reflect.DeepEqual(r, []string{"alice-9", "bob-3", "carol-7"}) {
+		t.Errorf("Expected the entries in the order of the keys, got %v.", r)
+	}
+
+	scores["bob"] = 4
+	scores["aaron"] = 1
+	delete(scores, "carol")
+	if r := render(d); !reflect.DeepEqual(r, []string{"aaron-1", "alice-9", "bob-4"}) {
+		t.Errorf("Expected the entries to follow the map, got %v.", r)
+	}
+
+	d.outputs = []string{"name", "score"}
+	if m := eachItemModel(d, "bob", 4); m["name"] != "bob" || m["score"] != 4 || m["$key"] != "bob" {
+		t.Errorf("Expected both the outputs and the symbols, got %v.", m)
+	}
+
+	keys := sortedMapKeys(reflect.ValueOf(map[int]bool{10: true, 2: true, -1: false}))
+	if keys[0].Int() != -1 || keys[1].Int() != 2 || keys[2].Int() != 10 {
+		t.Errorf("Expected the int keys to be sorted by value, got %v.", keys)
+	}
+
+	byId := map[uint]string{7: "c", 1 << 63: "d", 0: "a", 3: "b"}
+	uval := reflect.ValueOf(byId)
+	uitemAt := getIndexFunc(byId)(uval)
+	uitems := make([]string, 0)
+	for i := 0; i < uval.Len(); i++ {
+		k, v := uitemAt(i)
+		uitems = append(uitems, fmt.Sprintf("%v-%v", k, v.Interface()))
+	}
+	if expected := []string{"0-a", "3-b", "7-c", "9223372036854775808-d"}; !reflect.DeepEqual(uitems, expected) {
+		t.Errorf("Expected the entries of the uint map in the order of the keys %v, got %v.", expected, uitems)
+	}
+
+	mixed := sortedMapKeys(reflect.ValueOf(map[interface{}]bool{uint8(5): true, -2: true, int64(3): true}))
+	if mixed[0].Interface() != -2 || mixed[1].Interface() != int64(3) || mixed[2].Interface() != uint8(5) {
+		t.Errorf("Expected the mixed int keys to be sorted by value, got %v.", mixed)
+	}
+}
+
+type testTagged struct {
+	Tags []string
+}
+
+func (m *testTagged) SetTags(csv string) {
+	m.Tags = strings.Split(csv, ",")
+}
+
+func (m *testTagged) SetCount(n int) {}
+
+func TestGetterSetter(t *testing.T) {
+	b := NewBindEngine(nil)
+	b.RegisterHelper("joinCsv", func(list []string) string {
+		return strings.Join(list, ",")
+	})
+	model := &testTagged{[]string{"a", "b"}}
+	s := newModelScope(model)
+	s.merge(b.scope)
+	bs := &bindScope{s}
+
+	_, _, v, err := bs.evaluate("joinCsv(Tags)")
+	if err != nil || v != "a,b" {
+		t.Fatalf(`Expected "a,b", got %v (error: %v).`, v, err)
+	}
+
+	setter, err := bs.setter("SetTags")
+	if err != nil || setter == nil {
+		t.Fatalf("Expected a setter, got error: %v", err)
+	}
+	setter("x,y,z")
+	if !reflect.DeepEqual(model.Tags, []string{"x", "y", "z"}) {
+		t.Errorf("Expected the setter to set Tags, got %v.", model.Tags)
+	}
+
+	_, _, v, _ = bs.evaluate("joinCsv(Tags)")
+	if v != "x,y,z" {
+		t.Errorf(`Expected "x,y,z", got %v.`, v)
+	}
+
+	for _, name := range []string{"Tags", "SetTgas"} {
+		if setter, err := bs.setter(name); setter != nil || err == nil {
+			t.Errorf("Expected an error for the setter %v.", name)
+		}
+	}
+
+	if _, err := bs.setter("SetCount"); err == nil {
+		t.Errorf("Expected an error for a setter not accepting a string.")
+	}
+}
+
+func TestSvgAttrName(t *testing.T) {
+	tests := []struct {
+		attr, name, ns string
+	}{
+		{"cx", "cx", ""},
+		{"r", "r", ""},
+		{"stroke-width", "stroke-width", ""},
+		{"viewbox", "viewBox", ""},
+		{"viewBox", "viewBox", ""},
+		{"preserveaspectratio", "preserveAspectRatio", ""},
+		{"xlink:href", "xlink:href", XLinkNamespace},
+		{"xml:lang", "xml:lang", XMLNamespace},
+	}
+
+	for _, test := range tests {
+		name, ns := svgAttrName(test.attr)
+		if name != test.name || ns != test.ns {
+			t.Errorf("Expected (%v, %q) for %v, got (%v, %q).", test.name, test.ns, test.attr, name, ns)
+		}
+	}
+}
+
+type testAccessors struct {
+	name  string
+	count int
+}
+
+func (m *testAccessors) NameGet() string {
+	return m.name
+}
+
+func (m *testAccessors) NameSet(name string) {
+	m.name = strings.TrimSpace(name)
+}
+
+func (m *testAccessors) CountGet() int {
+	return m.count
+}
+
+func TestAccessorMethods(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testAccessors{name: "wade", count: 2}
+
+	_, blist, v, err := b.evaluate("toUpper(Name)", model)
+	if err != nil || v != "WADE" {
+		t.Fatalf("Expected the getter to be used, got %v, %v.", v, err)
+	}
+
+	oe := blist[0].bindObj()
+	if !oe.canSet() || oe.field != "name" {
+		t.Fatalf("Expected a settable accessor field watching %q, got %+v.", "name", oe)
+	}
+	v2, err := convertString(" gopher ", oe.typ())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	oe.set(v2)
+	if model.name != "gopher" {
+		t.Errorf("Expected the setter to be called, got %q.", model.name)
+	}
+
+	_, blist, v, err = b.evaluate("Count", model)
+	if err != nil || v != 2 {
+		t.Fatalf("Expected the getter to be used, got %v, %v.", v, err)
+	}
+	if blist[0].bindObj().canSet() {
+		t.Errorf("Expected a field without setter to be read-only.")
+	}
+}
+
+type testButton struct {
+	Clicks  int
+	OnClick func()
+	OnKey   func(jq.Event)
+	OnNone  func()
+}
+
+func (m *testButton) Reset() {
+	m.Clicks = 0
+}
+
+func TestEventHandlerField(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testButton{}
+	model.OnClick = func() { model.Clicks++ }
+	model.OnKey = func(evt jq.Event) { model.Clicks += 10 }
+
+	for _, bstr := range []string{"OnClick", "OnKey"} {
+		v, err := b.Eval(model, bstr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		fn, err := eventHandler(v)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", bstr, err)
+		}
+		fn(jq.Event{})
+	}
+	if model.Clicks != 11 {
+		t.Errorf("Expected the handler fields to be called, got %v clicks.", model.Clicks)
+	}
+
+	v, _ := b.Eval(model, "Reset")
+	fn, err := eventHandler(v)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fn(jq.Event{})
+	if model.Clicks != 0 {
+		t.Errorf("Expected the method to be called.")
+	}
+
+	for _, bstr := range []string{"OnNone", "Clicks"} {
+		v, _ := b.Eval(model, bstr)
+		if _, err := eventHandler(v); err == nil {
+			t.Errorf("Expected an error for %v.", bstr)
+		}
+	}
+}
+
+type fakeEvent struct {
+	prevented bool
+	stopped   bool
+}
+
+func (e *fakeEvent) PreventDefault()  { e.prevented = true }
+func (e *fakeEvent) StopPropagation() { e.stopped = true }
+
+func TestEventModifiers(t *testing.T) {
+	event, mods, err := parseEventModifiers([]string{"click", "prevent", "stop"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if event != "click" || !mods.prevent || !mods.stop || mods.self {
+		t.Fatalf("Wrong modifiers parsed: %v %+v.", event, mods)
+	}
+
+	handled := 0
+	evt := &fakeEvent{}
+	mods.handle(evt, false, func() { handled++ })
+	if !evt.prevented || !evt.stopped || handled != 1 {
+		t.Errorf("Expected the event to be prevented, stopped and handled.")
+	}
+
+	_, mods, _ = parseEventModifiers([]string{"click"})
+	evt = &fakeEvent{}
+	mods.handle(evt, false, func() { handled++ })
+	if evt.prevented || evt.stopped || handled != 2 {
+		t.Errorf("Expected the event to keep its default action and propagation.")
+	}
+
+	_, mods, _ = parseEventModifiers([]string{"click", "self"})
+	evt = &fakeEvent{}
+	mods.handle(evt, false, func() { handled++ })
+	if evt.prevented || evt.stopped || handled != 2 {
+		t.Errorf("Expected the event from a child to be ignored by the self modifier.")
+	}
+	mods.handle(evt, true, func() { handled++ })
+	if evt.prevented || handled != 3 {
+		t.Errorf("Expected the event from the element itself to be handled.")
+	}
+
+	for _, args := range [][]string{{}, {"click", "once"}} {
+		if _, _, err := parseEventModifiers(args); err == nil {
+			t.Errorf("Expected an error for %v.", args)
+		}
+	}
+}
+
+type testChips struct {
+	Tags []string
+}
+
+func TestListItems(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testChips{[]string{"go", "web"}}
+
+	render := func() []string {
+		v, err := b.Eval(model, "Tags")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		texts := make([]string, 0)
+		for _, item := range listItems(v) {
+			text, err := b.Eval(item, "concat(toUpper($item), `-`)")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			index, _ := b.Eval(item, "$index")
+			texts = append(texts, fmt.Sprintf("%v%v", text, index))
+		}
+		return texts
+	}
+
+	if texts := render(); !reflect.DeepEqual(texts, []string{"GO-0", "WEB-1"}) {
+		t.Errorf("Unexpected list rendering %v.", texts)
+	}
+
+	model.Tags = append(model.Tags, "ui")
+	if texts := render(); !reflect.DeepEqual(texts, []string{"GO-0", "WEB-1", "UI-2"}) {
+		t.Errorf("Unexpected list rendering after a change %v.", texts)
+	}
+
+	model.Tags = nil
+	if texts := render(); len(texts) != 0 {
+		t.Errorf("Expected an empty list, got %v.", texts)
+	}
+}
+
+type testContact struct {
+	Name    string
+	Address testAddress
+}
+
+type testDirectory struct {
+	Owner    testContact
+	Manager  *testContact
+	Contacts map[string]testContact
+	Settings map[string]string
+}
+
+func TestSeparators(t *testing.T) {
+	sep := jq.JQuery{Length: 1}
+	count := func(n int, sep jq.JQuery) int {
+		seps := 0
+		for i := 0; i < n; i++ {
+			if _, ok := insertSeparator(sep, jq.JQuery{}, i); ok {
+				seps++
+			}
+		}
+		return seps
+	}
+
+	for _, n := range []int{3, 4, 2, 1, 0} {
+		expected := n - 1
+		if n == 0 {
+			expected = 0
+		}
+		if seps := count(n, sep); seps != expected {
+			t.Errorf("Expected %v separators for %v items, got %v.", expected, n, seps)
+		}
+	}
+
+	if seps := count(3, jq.JQuery{}); seps != 0 {
+		t.Errorf("Expected no separators without a separator, got %v.", seps)
+	}
+}
+
+func TestNestedTwoWay(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testDirectory{
+		Manager:  &testContact{},
+		Contacts: map[string]testContact{"ann": {Name: "Ann"}},
+		Settings: map[string]string{"theme": "light"},
+	}
+
+	write := func(bstr, value string) {
+		_, blist, _, err := b.evaluate(bstr, model)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		oe := blist[0].bindObj()
+		if !oe.canSet() {
+			t.Fatalf("%v: expected the nested field to be settable.", bstr)
+		}
+		v, err := convertString(value, oe.typ())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		oe.set(v)
+	}
+
+	write("Owner.Address.City", "Hanoi")
+	write("Manager.Name", "Bob")
+	write("Contacts.ann.Address.City", "Hue")
+	write("Settings.theme", "dark")
+
+	if model.Owner.Address.City != "Hanoi" || model.Manager.Name != "Bob" {
+		t.Errorf("Expected the nested struct fields to be mutated, got %+v, %+v.", model.Owner, *model.Manager)
+	}
+	if c := model.Contacts["ann"]; c.Address.City != "Hue" || c.Name != "Ann" {
+		t.Errorf("Expected the struct in the map to be mutated, got %+v.", c)
+	}
+	if model.Settings["theme"] != "dark" {
+		t.Errorf("Expected the map entry to be set, got %v.", model.Settings["theme"])
+	}
+
+	// the field is bound once and written to several times, like by a 2-way binder
+	_, blist, _, err := b.evaluate("Contacts.ann.Name", model)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	name := blist[0].bindObj()
+	name.set(reflect.ValueOf("Anna"))
+	ann := model.Contacts["ann"]
+	ann.Address.City = "Da Nang"
+	model.Contacts["ann"] = ann
+	name.set(reflect.ValueOf("Annie"))
+	if c := model.Contacts["ann"]; c.Name != "Annie" || c.Address.City != "Da Nang" {
+		t.Errorf("Expected the write to keep the changes made to the map entry, got %+v.", c)
+	}
+}
+
+type testCanvas struct {
+	Canvas jq.JQuery
+	Node   js.Object
+	Width  int
+}
+
+func TestRefBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testCanvas{}
+	elem := jq.JQuery{Selector: "#canvas", Length: 1}
+
+	bindRef := func(bstr string) DomBind {
+		_, blist, v, err := b.evaluate(bstr, model)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		d := DomBind{Elem: elem, Value: v, binds: blist, binding: b}
+		(&RefBinder{}).Bind(d)
+		return d
+	}
+
+	d := bindRef("Canvas")
+	if model.Canvas.Selector != "#canvas" || model.Canvas.Length != 1 {
+		t.Errorf("Expected the field to receive the element, got %+v.", model.Canvas)
+	}
+	bindRef("Node")
+
+	b.registry.add("1", func() {
+		(&RefBinder{}).Teardown(d)
+	})
+	b.registry.teardown([]string{"1"}, "")
+	if model.Canvas.Length != 0 {
+		t.Errorf("Expected the field to be reset after teardown, got %+v.", model.Canvas)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected a panic for a field of a wrong type.")
+		}
+	}()
+	bindRef("Width")
+}
+
+func TestAttrPolicy(t *testing.T) {
+	b := NewBindEngine(nil)
+	binder := b.domBinders["attr"].(*AttrBinder)
+	tests := []struct {
+		attr    string
+		value   interface{}
+		str     string
+		present bool
+	}{
+		{"aria-expanded", true, "true", true},
+		{"aria-expanded", false, "false", true},
+		{"draggable", false, "false", true},
+		{"disabled", true, "", true},
+		{"disabled", false, "", false},
+		{"data-active", false, "", false},
+		{"title", "hello", "hello", true},
+		{"aria-label", "Close", "Close", true},
+	}
+
+	for _, test := range tests {
+		str, present := renderAttr(binder.policy(test.attr), test.value, toString)
+		if str != test.str || present != test.present {
+			t.Errorf("%v=%v: expected (%q, %v), got (%q, %v).", test.attr, test.value, test.str, test.present, str, present)
+		}
+	}
+
+	b.SetAttrPolicy("data-active", AttrBoolify)
+	if str, present := renderAttr(binder.policy("data-active"), false, toString); str != "false" || !present {
+		t.Errorf("Expected the configured policy to be used, got (%q, %v).", str, present)
+	}
+}
+
+func TestHiddenBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	model := &struct{ Visible bool }{true}
+	elem := gJQ(`<div><p bind-hidden="!Visible"></p></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	for _, visible := range []bool{true, false, true} {
+		model.Visible = visible
+		w.change()
+		p := elem.Find("p")
+		if p.Is("[hidden]") == visible {
+			t.Errorf("Visible=%v: expected the hidden attribute presence to be %v, got %v.", visible, !visible, elem.Html())
+		}
+		if !visible && p.Attr("hidden") != "" {
+			t.Errorf("Expected the hidden attribute to have no value, got %q.", p.Attr("hidden"))
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a non-bool value.")
+		}
+	}()
+	b.domBinders["hidden"].Update(DomBind{Elem: jq.JQuery{}, Value: "yes"})
+}
+
+func TestEditableMode(t *testing.T) {
+	tests := []struct {
+		args []string
+		html bool
+		ok   bool
+	}{
+		{[]string{}, false, true},
+		{[]string{"html"}, true, true},
+		{[]string{"text"}, false, false},
+		{[]string{"html", "x"}, false, false},
+	}
+	for _, test := range tests {
+		html, err := editableMode(test.args)
+		if (err == nil) != test.ok || html != test.html {
+			t.Errorf("%v: expected %v (ok: %v), got %v (error: %v).", test.args, test.html, test.ok, html, err)
+		}
+	}
+
+	// an edit goes through the same conversion as the value binder
+	model := &struct{ Count int }{}
+	oe, _ := evaluateObjField("Count", reflect.ValueOf(model))
+	v, err := convertString("42", oe.typ())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	oe.set(v)
+	if model.Count != 42 {
+		t.Errorf("Expected the edit to be written to the model, got %v.", model.Count)
+	}
+}
+
+type testChart struct {
+	inits int
+}
+
+func (c *testChart) InitChart() bool {
+	c.inits++
+	return true
+}
+
+func TestInitBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testChart{}
+	s := newModelScope(model)
+	s.merge(b.scope)
+	bs := &bindScope{s}
+
+	initsDuringBind := -1
+	b.runTasks(jq.JQuery{}, []func(){
+		func() {
+			b.processDomBind("bind-init", "InitChart()", jq.JQuery{}, bs, false)
+		},
+		func() {
+			initsDuringBind = model.inits
+		},
+	}, nil)
+	if initsDuringBind != 0 {
+		t.Errorf("Expected the init call to wait for the binding to complete, got %v calls.", initsDuringBind)
+	}
+	if model.inits != 1 {
+		t.Errorf("Expected the init expression to run exactly once, got %v.", model.inits)
+	}
+
+	b.processDomBind("bind-init", "InitChart()", jq.JQuery{}, bs, false)
+	if model.inits != 2 {
+		t.Errorf("Expected the init expression to run once for the new binding, got %v.", model.inits)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for an invalid init expression.")
+		}
+	}()
+	b.processDomBind("bind-init", "InitChart(", jq.JQuery{}, bs, false)
+}
+
+// testRatioBinder is a plugin binder reading the total from the expression given as dash arg
+type testRatioBinder struct {
+	BaseBinder
+	ratio float64
+	err   error
+}
+
+func (b *testRatioBinder) Update(d DomBind) {
+	var total interface{}
+	total, b.err = d.Eval(d.Args[0])
+	if b.err == nil {
+		b.ratio = float64(d.Value.(int)) / float64(total.(int))
+	}
+}
+func (b *testRatioBinder) BindInstance() DomBinder { return b }
+
+type testQuota struct {
+	Used, Limit int
+}
+
+func TestDomBindEval(t *testing.T) {
+	b := NewBindEngine(nil)
+	rb := &testRatioBinder{}
+	b.domBinders["ratio"] = rb
+	model := &testQuota{Used: 3, Limit: 4}
+	s := newModelScope(model)
+	s.merge(b.scope)
+	bs := &bindScope{s}
+
+	b.processDomBind("bind-ratio-Limit", "Used", jq.JQuery{}, bs, true)
+	if rb.err != nil || rb.ratio != 0.75 {
+		t.Errorf("Expected the binder to evaluate the secondary expression, got %v (%v).", rb.ratio, rb.err)
+	}
+
+	d := DomBind{binding: b, scope: s}
+	if v, err := d.Eval("len(toUpper(`ab`)) + Limit"); err != nil || v != 6 {
+		t.Errorf("Expected the helpers and the model to be in scope, got %v (%v).", v, err)
+	}
+	for _, expr := range []string{"Nothing", "Limit +", "div(`a`, 1, 0)"} {
+		if _, err := d.Eval(expr); err == nil {
+			t.Errorf("Expected an error for %v.", expr)
+		}
+	}
+}
+
+func TestNumberConstraints(t *testing.T) {
+	c, ok := newNumberConstraints("number", "1", "10", "2")
+	if !ok {
+		t.Fatalf("Expected a number input to be constrained.")
+	}
+	model := &struct{ Quantity int }{5}
+	oe, _ := evaluateObjField("Quantity", reflect.ValueOf(model))
+	write := func(input string) {
+		value, ok := c.apply(input)
+		if !ok {
+			return
+		}
+		v, err := convertString(value, oe.typ())
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", input, err)
+		}
+		oe.set(v)
+	}
+
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"7", 7},
+		{"50", 9},
+		{"-3", 1},
+		{"4", 5},
+		{"abc", 5},
+		{"", 5},
+		{" 3 ", 3},
+	}
+	for _, test := range tests {
+		write(test.input)
+		if model.Quantity != test.expected {
+			t.Errorf("%q: expected %v to be written, got %v.", test.input, test.expected, model.Quantity)
+		}
+	}
+
+	c, _ = newNumberConstraints("range", "", "1", "0.1")
+	for input, expected := range map[string]string{"0.34": "0.3", "0.35": "0.4", "2": "1", "-0.04": "0"} {
+		if v, _ := c.apply(input); v != expected {
+			t.Errorf("%v: expected %v, got %v.", input, expected, v)
+		}
+	}
+
+	if _, ok := newNumberConstraints("text", "1", "10", ""); ok {
+		t.Errorf("Expected a text input not to be constrained.")
+	}
+}
+
+func TestHTMLValues(t *testing.T) {
+	b := NewBindEngine(nil)
+	b.RegisterHelper("badge", func(n int) HTML {
+		return HTML(fmt.Sprintf(`<span class="badge">%v</span>`, n))
+	})
+	b.RegisterHelper("label", func(n int) string {
+		return fmt.Sprintf(`<b>%v</b>`, n)
+	})
+
+	v, err := b.Eval(nil, "badge(3)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	content, isHTML := textContent(DomBind{Value: v, binding: b})
+	if !isHTML || content != `<span class="badge">3</span>` {
+		t.Errorf("Expected the HTML to be inserted as markup, got %q (html: %v).", content, isHTML)
+	}
+
+	v, _ = b.Eval(nil, "label(3)")
+	content, isHTML = textContent(DomBind{Value: v, binding: b})
+	if isHTML || content != `<b>3</b>` {
+		t.Errorf("Expected the plain string to be inserted as text, got %q (html: %v).", content, isHTML)
+	}
+
+	binder := b.domBinders["text"].BindInstance().(*TextBinder)
+	binder.Bind(DomBind{Args: []string{"bind"}})
+	if !binder.rebind {
+		t.Errorf("Expected the bind dash arg to enable rebinding.")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for an unknown dash arg.")
+		}
+	}()
+	binder.Bind(DomBind{Args: []string{"foo"}})
+}
+
+// testTag is a custom tag whose contents are the given html
+type testTag string
+
+type testTagModel struct {
+	Title string
+}
+
+func (tag testTag) NewModel(jq.JQuery) interface{} {
+	return &testTagModel{}
+}
+
+func (tag testTag) PrepareTagContents(elem jq.JQuery, model interface{}) error {
+	elem.SetHtml(string(tag))
+	return nil
+}
+
+// testTags is a custom element manager with the given tags
+type testTags map[string]testTag
+
+func (tm testTags) GetCustomTag(elem jq.JQuery) (CustomTag, bool) {
+	return tm.GetCustomTagByName(elem.Prop("tagName").(string))
+}
+
+func (tm testTags) GetCustomTagByName(tagname string) (CustomTag, bool) {
+	tag, ok := tm[strings.ToLower(tagname)]
+	return tag, ok
+}
+
+type testWidget struct {
+	Kind, Title string
+}
+
+func TestIsBinder(t *testing.T) {
+	tags := testTags{
+		"x-card": `<h2 bind-text="Title"></h2>`,
+		"x-row":  `<span bind-text="Title"></span>`,
+	}
+	b := NewBindEngine(tags)
+	w := newFakeWatcher()
+	b.fields = w
+	model := &testWidget{"x-card", "Hello"}
+	elem := gJQ(`<div><div bind-is="Kind" bind="Title: Title"></div></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	if elem.Find("h2").Text() != "Hello" || elem.Find("div").Length != 0 {
+		t.Fatalf("Expected the card to be rendered, got %v.", elem.Html())
+	}
+
+	model.Kind = "x-row"
+	w.change()
+	if elem.Find("h2").Length != 0 || elem.Find("span").Text() != "Hello" {
+		t.Fatalf("Expected the card to be swapped for the row, got %v.", elem.Html())
+	}
+
+	model.Kind, model.Title = "x-card", "Bye"
+	w.change()
+	if elem.Find("span").Length != 0 || elem.Find("h2").Text() != "Bye" {
+		t.Errorf("Expected the row to be swapped back for the card, got %v.", elem.Html())
+	}
+
+	// the tags can only be looked up by name through a NamedTagSource
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a manager that doesn't look up the tags by name.")
+		}
+	}()
+	b = NewBindEngine(testTemplates{})
+	b.fields = newFakeWatcher()
+	other := gJQ(`<div><div bind-is="Kind"></div></div>`).AppendTo(gJQ("body"))
+	defer other.Remove()
+	b.Bind(other, model, false, false)
+}
+
+type testMember struct {
+	Name    string
+	Admin   bool
+	Country string
+}
+
+func TestModelBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	model := &testMember{"Ann", false, "fr"}
+	elem := gJQ(`<div>
+		<input type="text" bind-model="Name">
+		<input type="checkbox" bind-model="Admin">
+		<select bind-model="Country"><option value="fr">France</option><option value="vn">Vietnam</option></select>
+		<span bind-model="Name"></span>
+	</div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	text, checkbox, sel := elem.Find("input[type=text]"), elem.Find("input[type=checkbox]"), elem.Find("select")
+	if text.Val() != "Ann" || checkbox.Is(":checked") || sel.Val() != "fr" || elem.Find("span").Text() != "Ann" {
+		t.Fatalf("Expected the elements to show the model, got %v.", elem.Html())
+	}
+
+	// the model is written through the binders
+	text.SetVal("Bob").Trigger(jq.CHANGE)
+	checkbox.SetProp("checked", true).Trigger(jq.CHANGE)
+	sel.SetVal("vn").Trigger(jq.CHANGE)
+	if *model != (testMember{"Bob", true, "vn"}) {
+		t.Errorf("Expected the model to be updated from the elements, got %+v.", *model)
+	}
+
+	model.Name, model.Admin, model.Country = "Cid", false, "fr"
+	w.change()
+	if text.Val() != "Cid" || checkbox.Is(":checked") || sel.Val() != "fr" || elem.Find("span").Text() != "Cid" {
+		t.Errorf("Expected the elements to be updated from the model, got %v.", elem.Html())
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"reflect"
 	"strings"
 
-	"github.com/gopherjs/gopherjs/js"
 	jq "github.com/gopherjs/jquery"
 )
 
@@ -22,6 +21,10 @@ func toString(value interface{}) string {
 	if value == nil {
 		return ""
 	}
+	// a nil pointer is displayed as empty, like nil
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return ""
+	}
 	return fmt.Sprintf("%v", value)
 }
 
@@ -29,11 +32,29 @@ type CustomElemManager interface {
 	GetCustomTag(jq.JQuery) (CustomTag, bool)
 }
 
+// NamedTagSource is implemented by the CustomElemManagers that can look up
+// a custom tag by its name, it's required by the is binder.
+type NamedTagSource interface {
+	GetCustomTagByName(string) (CustomTag, bool)
+}
+
 type CustomTag interface {
 	NewModel(jq.JQuery) interface{}
 	PrepareTagContents(jq.JQuery, interface{}) error
 }
 
+// PendingTagSource is implemented by the CustomElemManagers whose custom tags may be
+// loaded asynchronously. While the tag of an element is loading, the element gets the
+// TagLoadingClass as a placeholder, and it's bound once the tag is loaded.
+type PendingTagSource interface {
+	// PendingCustomTag checks whether the element's tag is a custom tag being loaded,
+	// the returned channel is closed when the loading is finished
+	PendingCustomTag(jq.JQuery) (loaded <-chan struct{}, pending bool)
+}
+
+// TagLoadingClass is the class of the elements whose custom tag is loading
+const TagLoadingClass = "wade-tag-loading"
+
 type scopeSymbol interface {
 	value() (reflect.Value, error)
 	call([]reflect.Value) (reflect.Value, error)
@@ -43,16 +64,138 @@ type symbolTable interface {
 	lookup(symbol string) (scopeSymbol, bool)
 }
 
+// Precedence decides which of a model field and a helper a name refers to,
+// when both exist
+type Precedence int
+
+const (
+	// ModelFirst makes the model fields (and the outputs of binders like bind-each)
+	// shadow the helpers, it's the default
+	ModelFirst Precedence = iota
+	// HelperFirst makes the helpers shadow the model fields
+	HelperFirst
+)
+
 type scope struct {
 	symTables []symbolTable
+	// lookupOrder holds the symTables in the order they are looked up in, it's
+	// built when tables are added and when the scope is merged, see orderedTables
+	lookupOrder []symbolTable
+	// precedence points to the setting of the Binding that the scope is made from,
+	// it's nil for a scope that has not been merged with the basic scope
+	precedence *Precedence
+	// parent is the scope that the scope is nested in without inheriting its symbols,
+	// like the scope a custom tag is used in, for $parent and $root
+	parent *scope
 }
 
-func newScope() *scope {
-	return &scope{make([]symbolTable, 0)}
+func newScope(tables ...symbolTable) *scope {
+	s := &scope{symTables: make([]symbolTable, 0, len(tables))}
+	s.addTables(tables...)
+	return s
 }
 
-func (s *scope) lookup(symbol string) (sym scopeSymbol, err error) {
+// addTables appends symbol tables to the scope
+func (s *scope) addTables(tables ...symbolTable) {
+	s.symTables = append(s.symTables, tables...)
+	s.lookupOrder = s.orderedTables()
+}
+
+// orderedTables returns the symbol tables in the order they are looked up in.
+// The model tables keep their order, the innermost model first, and the helper
+// tables come after them, or before them if the precedence is HelperFirst.
+func (s *scope) orderedTables() []symbolTable {
+	models := make([]symbolTable, 0, len(s.symTables))
+	helpers := make([]symbolTable, 0, 1)
 	for _, st := range s.symTables {
+		if _, ok := st.(mapSymbolTable); ok {
+			helpers = append(helpers, st)
+		} else {
+			models = append(models, st)
+		}
+	}
+
+	if s.precedence != nil && *s.precedence == HelperFirst {
+		return append(helpers, models...)
+	}
+	return append(models, helpers...)
+}
+
+const (
+	// ParentSymbol refers to the model enclosing the innermost one, like the model
+	// of the page inside a bind-each, it can be chained ($parent.$parent.Title)
+	ParentSymbol = "$parent"
+	// RootSymbol refers to the outermost model, usually the page model
+	RootSymbol = "$root"
+)
+
+// ancestors returns the scopes in which each of the models of the scope is the innermost
+// one, from the innermost model to the outermost, going on with the models of the parent
+// scope, like the scope a custom tag is used in
+func (s *scope) ancestors() []*scope {
+	levels := make([]*scope, 0)
+	for cur := s; cur != nil; cur = cur.parent {
+		for i, st := range cur.symTables {
+			if _, isModel := st.(modelSymbolTable); !isModel {
+				continue
+			}
+
+			as := &scope{precedence: cur.precedence, parent: cur.parent}
+			for j, t := range cur.symTables {
+				if _, isModel := t.(modelSymbolTable); !isModel || j >= i {
+					as.addTables(t)
+				}
+			}
+			levels = append(levels, as)
+		}
+	}
+
+	return levels
+}
+
+// lookupAncestor resolves the symbols starting with $parent or $root
+func (s *scope) lookupAncestor(symbol string) (sym scopeSymbol, found bool, err error) {
+	name, rest := symbol, ""
+	if i := strings.Index(symbol, "."); i != -1 {
+		name, rest = symbol[:i], symbol[i+1:]
+	}
+
+	depth := 1
+	switch strings.TrimSuffix(name, "?") {
+	case ParentSymbol:
+	case RootSymbol:
+		depth = -1
+	default:
+		return
+	}
+
+	found = true
+	levels := s.ancestors()
+	if depth < 0 {
+		depth = len(levels) - 1
+	}
+	if depth < 0 || depth >= len(levels) {
+		err = fmt.Errorf(`Cannot evaluate "%v", there's no model for "%v" in the scope`, symbol, name)
+		return
+	}
+	as := levels[depth]
+
+	if rest == "" {
+		model := as.symTables[0].(modelSymbolTable).model
+		sym = valueSymbol{name, model}
+		return
+	}
+
+	sym, err = as.lookup(rest)
+	return
+}
+
+func (s *scope) lookup(symbol string) (sym scopeSymbol, err error) {
+	if sym, found, err := s.lookupAncestor(symbol); found {
+		return sym, err
+	}
+
+	for _, st := range s.lookupOrder {
 		var ok bool
 		sym, ok = st.lookup(symbol)
 		if ok {
@@ -65,9 +208,13 @@ func (s *scope) lookup(symbol string) (sym scopeSymbol, err error) {
 }
 
 func (s *scope) merge(target *scope) {
-	for _, st := range target.symTables {
-		s.symTables = append(s.symTables, st)
+	if s.precedence == nil {
+		s.precedence = target.precedence
+	}
+	if s.parent == nil {
+		s.parent = target.parent
 	}
+	s.addTables(target.symTables...)
 }
 
 type mapSymbolTable struct {
@@ -76,6 +223,19 @@ type mapSymbolTable struct {
 
 func (st mapSymbolTable) lookup(symbol string) (sym scopeSymbol, ok bool) {
 	sym, ok = st.m[symbol]
+	if ok {
+		return
+	}
+
+	// the fields of a constant (Const.Field)
+	if i := strings.Index(symbol, "."); i != -1 {
+		if cs, isConst := st.m[symbol[:i]].(valueSymbol); isConst {
+			var eval *objEval
+			if eval, ok = evaluateObjField(symbol[i+1:], cs.v); ok {
+				sym = valueSymbol{symbol, eval.fieldRefl}
+			}
+		}
+	}
 	return
 }
 
@@ -94,8 +254,8 @@ func newFuncSymbol(name string, fn interface{}) funcSymbol {
 		panic(fmt.Sprintf(`Can't create funcSymbol "%v" from a non-function.`, name))
 	}
 
-	if fnType.NumOut() > 1 {
-		panic(fmt.Sprintf(`"%v": funcSymbol cannot have more than 1 return value.`, name))
+	if fnType.NumOut() > 1 && !returnsError(fnType) {
+		panic(fmt.Sprintf(`"%v": funcSymbol cannot have more than 1 return value, except (value, error).`, name))
 	}
 
 	return funcSymbol{name, reflect.ValueOf(fn)}
@@ -152,6 +312,48 @@ func (mf modelFieldSymbol) call(args []reflect.Value) (v reflect.Value, err erro
 	return
 }
 
+// indexedAccessor returns the bindable for a call to an accessor method taking
+// arguments, like Get(key), paired with a setter taking the same arguments followed
+// by the value, like Set(key, value). Writes of a 2-way binding to the call
+// go through the setter.
+//
+// Usage:
+//	bind-value="Get(`name`)"
+func (mf modelFieldSymbol) indexedAccessor(args []reflect.Value, v reflect.Value) (bindable, bool) {
+	name := mf.eval.field
+	if len(args) == 0 || !strings.HasSuffix(name, AccessorGetSuffix) || !v.IsValid() {
+		return nil, false
+	}
+
+	model := mf.eval.modelRefl
+	if model.Kind() != reflect.Ptr && model.CanAddr() {
+		model = model.Addr()
+	}
+	setter := model.MethodByName(strings.TrimSuffix(name, AccessorGetSuffix) + AccessorSetSuffix)
+	if !setter.IsValid() {
+		return nil, false
+	}
+
+	stype := setter.Type()
+	if stype.NumIn() != len(args)+1 || !v.Type().AssignableTo(stype.In(len(args))) {
+		return nil, false
+	}
+	for i, arg := range args {
+		if !arg.IsValid() || !arg.Type().AssignableTo(stype.In(i)) {
+			return nil, false
+		}
+	}
+
+	return modelFieldSymbol{mf.name, &objEval{
+		fieldRefl:  v,
+		modelRefl:  mf.eval.modelRefl,
+		root:       mf.eval.root,
+		field:      mf.eval.field,
+		setter:     setter,
+		setterArgs: args,
+	}}, true
+}
+
 func (st modelSymbolTable) lookup(symbol string) (sym scopeSymbol, ok bool) {
 	if st.model.Kind() == reflect.Ptr && st.model.IsNil() {
 		ok = false
@@ -162,17 +364,46 @@ func (st modelSymbolTable) lookup(symbol string) (sym scopeSymbol, ok bool) {
 	eval, ok = evaluateObjField(symbol, st.model)
 	if ok {
 		sym = modelFieldSymbol{symbol, eval}
+	} else if path, isNil := nilFieldOf(symbol, st.model); isNil {
+		sym = errorSymbol{fmt.Errorf(`Cannot evaluate "%v", "%v" is nil`, symbol, path)}
+		ok = true
 	}
 
 	return
 }
 
+// valueSymbol is a symbol standing for a value, like a model of the scope
+type valueSymbol struct {
+	name string
+	v    reflect.Value
+}
+
+func (vs valueSymbol) value() (reflect.Value, error) {
+	return vs.v, nil
+}
+
+func (vs valueSymbol) call([]reflect.Value) (reflect.Value, error) {
+	return reflect.Value{}, fmt.Errorf(`Cannot call "%v", it's not a function.`, vs.name)
+}
+
+// errorSymbol is a symbol that's found but cannot be evaluated
+type errorSymbol struct {
+	err error
+}
+
+func (es errorSymbol) value() (reflect.Value, error) {
+	return reflect.Value{}, es.err
+}
+
+func (es errorSymbol) call([]reflect.Value) (reflect.Value, error) {
+	return reflect.Value{}, es.err
+}
+
 func newModelScope(model interface{}) *scope {
-	stl := []symbolTable{}
-	if model != nil {
-		stl = append(stl, modelSymbolTable{reflect.ValueOf(model)})
+	if model == nil {
+		return newScope()
 	}
-	return &scope{stl}
+	return newScope(modelSymbolTable{reflect.ValueOf(model)})
 }
 
 type Binding struct {
@@ -180,8 +411,43 @@ type Binding struct {
 	domBinders map[string]DomBinder
 	helpers    mapSymbolTable
 
+	typeFormatters map[reflect.Type]TypeFormatter
+	typeParsers    map[reflect.Type]TypeParser
+	registry       *bindRegistry
+	viewport       ViewportObserver
+	resize         SizeObserver
+	query          *queryWriter
+	markdown       MarkdownRenderer
+	clipboard      Clipboard
+	title          TitleSetter
+	idle           *idleQueue
+	browser        browser
+
 	scope     *scope
 	pageModel interface{}
+
+	// HelperPrecedence decides whether a name that's both a model field and
+	// a helper refers to the field (ModelFirst, the default) or the helper.
+	// It's taken into account when binding, changing it doesn't affect
+	// the elements that are already bound.
+	HelperPrecedence Precedence
+
+	// MaxWatchers is the maximum number of active watchers of model fields, a warning
+	// is printed and the further fields are not watched when it's reached. A field
+	// counts for one watcher, plus one for each value nested in it that is watched.
+	// 0 means no maximum.
+	MaxWatchers int
+	// MaxWatchDepth limits how many levels of the values nested in the watched fields
+	// are watched for changes, it's the level argument of watch.js. 0 means no limit.
+	MaxWatchDepth int
+	watchers      watchBudget
+	fields        fieldWatcher
+	cycles        cycleCache
+
+	// CollectStats enables the collection of the statistics of the updates,
+	// returned by Stats. It's off by default to avoid the overhead.
+	CollectStats bool
+	stats        *statsCollector
 }
 
 func NewBindEngine(tm CustomElemManager) *Binding {
@@ -189,14 +455,33 @@ func NewBindEngine(tm CustomElemManager) *Binding {
 		tm:         tm,
 		domBinders: defaultBinders(),
 		helpers:    helpersSymbolTable(defaultHelpers()),
+
+		typeFormatters: defaultTypeFormatters(),
+		typeParsers:    make(map[reflect.Type]TypeParser),
+		registry:       newBindRegistry(),
+		viewport:       intersectionObserver{},
+		resize:         resizeObserver{},
+		idle:           newIdleQueue(idleCallbackScheduler{}),
+		clipboard:      browserClipboard{},
+		title:          documentTitle{},
+		browser:        jsBrowser{},
+		stats:          newStatsCollector(),
+		fields:         watchJS{},
+		cycles:         make(cycleCache),
+	}
+	b.query = newQueryWriter(browserLocation{}, b.browser)
+	for name, fn := range b.engineHelpers() {
+		b.RegisterHelper(name, fn)
 	}
 
-	b.scope = &scope{[]symbolTable{b.helpers}}
+	b.scope = &scope{precedence: &b.HelperPrecedence}
+	b.scope.addTables(b.helpers)
 	return b
 }
 
 // RegisterHelper registers a function as a global helper with the given name.
-//
+// It returns one value, or a value and an error: a non-nil error fails the
+// evaluation of the bind string, like an invalid expression.
 func (b *Binding) RegisterHelper(name string, fn interface{}) {
 	typ := reflect.TypeOf(fn)
 	if typ.Kind() != reflect.Func {
@@ -216,10 +501,98 @@ func (b *Binding) RegisterHelper(name string, fn interface{}) {
 	return
 }
 
+// RegisterConstant registers a value that can be referred to by name in all bind
+// expressions, like an enum or a set of states. A struct or map constant gives access
+// to its fields. Constants cannot be bound 2-way.
+//
+// Usage:
+//	b.RegisterConstant("States", struct{ Editing, Done string }{"editing", "done"})
+// then
+//	bind-class-editing="State == States.Editing"
+func (b *Binding) RegisterConstant(name string, value interface{}) {
+	if _, exist := b.helpers.lookup(name); exist {
+		panic(fmt.Sprintf("Helper or constant with name %v already exists.", name))
+	}
+
+	b.helpers.m[name] = valueSymbol{name, reflect.ValueOf(value)}
+}
+
 type objEval struct {
 	fieldRefl reflect.Value
 	modelRefl reflect.Value
 	field     string
+	setter    reflect.Value
+
+	// root is the model that the field is evaluated from, it's not valid
+	// for the fields given by accessor methods
+	root reflect.Value
+
+	// setterArgs are passed to the setter before the value, for
+	// accessors taking arguments like Get(key) and Set(key, value)
+	setterArgs []reflect.Value
+
+	// writeBacks write the copies of the values taken from maps
+	// along the path back to the maps, after the field is set
+	writeBacks []func()
+	// refresh evaluates the field again, to take new copies of the map values
+	// before setting it, it's nil if there are no copies
+	refresh func() (*objEval, bool)
+
+	// readOnly is set for the pseudo-properties like "length", whose modelRefl
+	// and field are those of the collection so that the collection is watched
+	readOnly bool
+}
+
+// typ returns the type of the values that can be set to the field
+func (oe *objEval) typ() reflect.Type {
+	if oe.setter.IsValid() {
+		return oe.setter.Type().In(len(oe.setterArgs))
+	}
+
+	return oe.fieldRefl.Type()
+}
+
+// watched returns the value of the field that is watched, the collection
+// for the pseudo-properties
+func (oe *objEval) watched() reflect.Value {
+	if oe.readOnly {
+		if v, ok := getReflectField(oe.modelRefl, oe.field); ok {
+			return v
+		}
+	}
+	return oe.fieldRefl
+}
+
+// canSet checks whether the field can be set, directly or through its setter
+func (oe *objEval) canSet() bool {
+	return !oe.readOnly && (oe.setter.IsValid() || oe.fieldRefl.CanSet())
+}
+
+// set sets the value to the field, calling the setter instead if the field
+// is accessed through accessor methods
+func (oe *objEval) set(v reflect.Value) {
+	if oe.readOnly {
+		panic(fmt.Sprintf(`Cannot set the read-only property of "%v".`, oe.field))
+	}
+	if oe.setter.IsValid() {
+		args := append(append([]reflect.Value{}, oe.setterArgs...), v)
+		oe.setter.Call(args)
+		oe.fieldRefl = v
+		return
+	}
+
+	if oe.refresh != nil {
+		// the map values may have changed since the copies were taken,
+		// writing the old copies back would undo the changes
+		if fresh, ok := oe.refresh(); ok {
+			oe.fieldRefl, oe.writeBacks = fresh.fieldRefl, fresh.writeBacks
+		}
+	}
+
+	oe.fieldRefl.Set(v)
+	for _, wb := range oe.writeBacks {
+		wb()
+	}
 }
 
 type bindable interface {
@@ -235,6 +608,15 @@ func (b *bindScope) evaluateRec(e *expr) (v reflect.Value, blist []bindable, err
 	err = nil
 	blist = make([]bindable, 0)
 
+	switch e.typ {
+	case OpExpr:
+		return b.evaluateOp(e)
+	case MapExpr:
+		return b.evaluateMap(e)
+	case IndexExpr:
+		return b.evaluateIndex(e)
+	}
+
 	litVal, isLiteral, er := parseExpr(e.name)
 	if er != nil {
 		err = er
@@ -245,6 +627,10 @@ func (b *bindScope) evaluateRec(e *expr) (v reflect.Value, blist []bindable, err
 		return
 	}
 
+	if b.isFilterCall(e) {
+		return b.evaluateFilter(e)
+	}
+
 	args := make([]reflect.Value, len(e.args))
 	for i, e := range e.args {
 		var cblist []bindable
@@ -272,9 +658,100 @@ func (b *bindScope) evaluateRec(e *expr) (v reflect.Value, blist []bindable, err
 		return
 	}
 
+	if mf, ok := sym.(modelFieldSymbol); ok && e.typ == CallExpr {
+		if acc, ok := mf.indexedAccessor(args, v); ok {
+			blist = append(blist, acc)
+			return
+		}
+	}
+
 	if mf, ok := sym.(bindable); ok {
 		blist = append(blist, mf)
 	}
+	if ds, ok := sym.(dependentSymbol); ok {
+		blist = append(blist, ds.dependencies()...)
+	}
+	return
+}
+
+// evaluateMap evaluates a map literal to a map[string]interface{}
+func (b *bindScope) evaluateMap(e *expr) (v reflect.Value, blist []bindable, err error) {
+	m := make(map[string]interface{})
+	blist = make([]bindable, 0)
+	for i, arg := range e.args {
+		var (
+			av     reflect.Value
+			cblist []bindable
+		)
+		av, cblist, err = b.evaluateRec(arg)
+		if err != nil {
+			return
+		}
+
+		blist = append(blist, cblist...)
+		m[e.keys[i]] = nil
+		if av.IsValid() && av.CanInterface() {
+			m[e.keys[i]] = av.Interface()
+		}
+	}
+
+	v = reflect.ValueOf(m)
+	return
+}
+
+// evaluateIndex evaluates an index expression, like Groups[`cpu`][0].Value. The field path
+// after the brackets is watched like the fields of the model.
+func (b *bindScope) evaluateIndex(e *expr) (v reflect.Value, blist []bindable, err error) {
+	target, blist, err := b.evaluateRec(e.args[0])
+	if err != nil {
+		return
+	}
+	index, iblist, err := b.evaluateRec(e.args[1])
+	if err != nil {
+		return
+	}
+	blist = append(blist, iblist...)
+
+	v, err = indexValue(target, index)
+	if err != nil || e.name == "" {
+		return
+	}
+
+	if isLengthField(e.name) {
+		if n, ok := collectionLen(v); ok {
+			v = reflect.ValueOf(n)
+			return
+		}
+	}
+
+	eval, ok := evaluateObjField(e.name, v)
+	if !ok {
+		if path, isNil := nilFieldOf(e.name, v); isNil {
+			err = fmt.Errorf(`Cannot evaluate "%v" of the indexed value, "%v" is nil`, e.name, path)
+		} else {
+			err = fmt.Errorf(`Unable to find "%v" in the indexed value`, e.name)
+		}
+		return
+	}
+
+	blist = append(blist, modelFieldSymbol{e.name, eval})
+	v = eval.fieldRefl
+	return
+}
+
+// Eval evaluates the bind string against the model and the registered helpers and
+// returns the resulting value, without touching the DOM.
+// It's useful for testing and debugging models.
+func (b *Binding) Eval(model interface{}, bstr string) (value interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	s := newModelScope(model)
+	s.merge(b.scope)
+	_, _, value, err = (&bindScope{s}).evaluate(bstr)
 	return
 }
 
@@ -284,13 +761,13 @@ func bindStringPanic(mess, bindstring string) {
 
 // evaluateBindstring evaluates the bind string, returns the needed information for binding
 func (b *bindScope) evaluate(bstr string) (root *expr, blist []bindable, value interface{}, err error) {
-	root, err = parse(bstr)
+	root, err = parseCached(bstr)
 	if err != nil {
 		return
 	}
 
 	var v reflect.Value
-	v, blist, err = b.evaluateRec(root)
+	v, blist, err = b.evaluateLocked(root)
 	if err != nil {
 		return
 	}
@@ -309,33 +786,107 @@ func (b *bindScope) evaluateBindString(bstr string) (root *expr, blist []bindabl
 	return
 }
 
+// setter returns an update function that calls the function with the given name
+// in the scope, so that a 2-way binding may use a setter instead of setting the field
+func (b *bindScope) setter(name string) (ufn ModelUpdateFn, err error) {
+	sym, err := b.scope.lookup(name)
+	if err != nil {
+		err = fmt.Errorf(`Cannot find the setter "%v": %v`, name, err.Error())
+		return
+	}
+
+	fn, err := sym.value()
+	if err != nil {
+		return
+	}
+	if fn.Kind() != reflect.Func {
+		err = fmt.Errorf(`The setter "%v" must be a method or a helper, not a value of type %v`, name, fn.Type())
+		return
+	}
+
+	ftype := fn.Type()
+	if ftype.NumIn() != 1 || ftype.In(0).Kind() != reflect.String {
+		err = fmt.Errorf(`The setter "%v" must accept exactly 1 string argument`, name)
+		return
+	}
+
+	ufn = func(newVal string) {
+		_, err := sym.call([]reflect.Value{reflect.ValueOf(newVal).Convert(ftype.In(0))})
+		if err != nil {
+			panic(err.Error())
+		}
+	}
+	return
+}
+
 func (b *bindScope) clone() *bindScope {
 	scope := newScope()
 	scope.merge(b.scope)
 	return &bindScope{scope}
 }
 
-func (b *Binding) watchModel(binds []bindable, root *expr, bs *bindScope, callback func(interface{})) {
+func (b *Binding) watchModel(elem jq.JQuery, binds []bindable, root *expr, bs *bindScope, callback func(interface{})) {
+	b.watchFields(elem, binds, func() {
+		newResult, _, _ := bs.evaluateLocked(root)
+		callback(newResult.Interface())
+	})
+	b.pollExpr(elem, root, bs, callback)
+}
+
+// watchFields calls reevaluate when one of the model fields of the binds changes
+func (b *Binding) watchFields(elem jq.JQuery, binds []bindable, reevaluate func()) {
+	if delay, ok := debounceDelay(elem); ok && len(binds) > 0 {
+		d := newDebouncer(b.browser, delay, reevaluate)
+		reevaluate = d.trigger
+		b.addTeardown(elem, d.cancel)
+	}
+
 	for _, bi := range binds {
 		//use watchjs to watch for changes to the model
 		(func(bi bindable) {
 			bo := bi.bindObj()
-			obj := js.InternalObject(bo.modelRefl.Interface()).Get("$val")
-			//workaround for gopherjs's protection disallowing js access to maps
-			//setDummyHopFn(obj, "")
-			js.Global.Call("watch",
-				obj,
-				bo.field,
-				func(prop string, action string,
-					_ js.Object,
-					_2 js.Object) {
-					newResult, _, _ := bs.evaluateRec(root)
-					callback(newResult.Interface())
-				})
+			targets, release := b.watchTargets(bo)
+			n := b.watcherCount(targets)
+			if !b.watchers.take(b.MaxWatchers, n, bo.field) {
+				release()
+				return
+			}
+
+			active := true
+			handler := func() {
+				if active {
+					reevaluate()
+				}
+			}
+			unwatches := make([]func(), len(targets))
+			for i, t := range targets {
+				unwatches[i] = b.fields.watch(t.obj, t.field, b.watchLevel(t), handler)
+			}
+			b.addTeardown(elem, func() {
+				active = false
+				for _, unwatch := range unwatches {
+					unwatch()
+				}
+				b.watchers.release(n)
+				release()
+			})
 		})(bi)
 	}
 }
 
+// pollExpr polls the expression if polling is enabled for the element
+func (b *Binding) pollExpr(elem jq.JQuery, root *expr, bs *bindScope, callback func(interface{})) {
+	if interval, ok := pollInterval(elem); ok {
+		b.startPolling(elem, interval, newPoller(func() interface{} {
+			newResult, _, _ := bs.evaluateLocked(root)
+			if !newResult.IsValid() || !newResult.CanInterface() {
+				return nil
+			}
+			return newResult.Interface()
+		}, callback))
+	}
+}
+
 func (b *Binding) processDomBind(astr, bstr string, elem jq.JQuery, bs *bindScope, once bool) {
 	parts := strings.Split(astr, "-")
 	if len(parts) <= 1 {
@@ -343,6 +894,7 @@ func (b *Binding) processDomBind(astr, bstr string, elem jq.JQuery, bs *bindScop
 	}
 
 	if binder, ok := b.domBinders[parts[1]]; ok {
+		name := parts[1]
 		binder = binder.BindInstance()
 		args := make([]string, 0)
 		if len(parts) >= 2 {
@@ -368,16 +920,48 @@ func (b *Binding) processDomBind(astr, bstr string, elem jq.JQuery, bs *bindScop
 				}
 			}
 		}
-		roote, binds, v := bs.evaluateBindString(bexpr)
+		var (
+			roote *expr
+			binds []bindable
+			v     interface{}
+		)
+		if _, ok := binder.(OneShotBinder); ok {
+			// evaluated by the binder itself
+			if _, err := parseCached(bexpr); err != nil {
+				bindStringPanic(err.Error(), bexpr)
+			}
+			once = true
+		} else {
+			roote, binds, v = bs.evaluateBindString(bexpr)
+		}
 
-		if len(binds) == 1 {
-			fmodel := binds[0].bindObj().fieldRefl
-			binder.Watch(elem, func(newVal string) {
-				if !fmodel.CanSet() {
+		var setter ModelUpdateFn
+		if _, ok := binder.(TwoWayBinder); ok && len(outputs) != 0 {
+			if len(outputs) != 1 {
+				bindStringPanic("a 2-way binder takes a single setter after ->", bstr)
+			}
+			var err error
+			setter, err = bs.setter(outputs[0])
+			if err != nil {
+				bindStringPanic(err.Error(), bstr)
+			}
+		}
+
+		if setter != nil {
+			binder.Watch(elem, b.gateWrites(elem, bs, setter))
+		} else if len(binds) == 1 {
+			fmodel := binds[0].bindObj()
+			binder.Watch(elem, b.gateWrites(elem, bs, func(newVal string) {
+				if !fmodel.canSet() {
 					panic("Cannot set field.")
 				}
-				fmodel.Set(reflect.ValueOf(newVal))
-			})
+				v, err := b.parseValue(newVal, fmodel.typ())
+				if err != nil {
+					println(fmt.Sprintf(`%v, while processing bind string "%v".`, err.Error(), bstr))
+					return
+				}
+				fmodel.set(v)
+			}))
 		}
 
 		metadata := fmt.Sprintf(`%v = "%v"`, astr, bstr)
@@ -389,18 +973,29 @@ func (b *Binding) processDomBind(astr, bstr string, elem jq.JQuery, bs *bindScop
 			outputs:  outputs,
 			binding:  b,
 			scope:    bs.scope,
+			binds:    binds,
+			once:     once,
+			expr:     bexpr,
 			metadata: metadata,
 		}
+		info := b.addBindInfo(elem, astr, bstr, v)
 		(func(args, outputs []string) {
 			binder.Bind(domBind)
-			binder.Update(domBind)
+			b.runUpdate(name, binder, domBind)
+			if tb, ok := binder.(TeardownBinder); ok {
+				b.addTeardown(elem, func() {
+					tb.Teardown(domBind)
+				})
+			}
 			if !once {
-				b.watchModel(binds, roote, bs, func(newResult interface{}) {
+				update := b.updateFunc(binder, func() {
+					domBind.Elem = b.liveElem(elem)
+					b.runUpdate(name, binder, domBind)
+				})
+				b.watchModel(elem, binds, roote, bs, func(newResult interface{}) {
+					info.Value = newResult
 					domBind.Value = newResult
-					binder.Update(domBind)
-					elem.Find("wrapper").Each(func(_ int, e jq.JQuery) {
-						e.Children("").First().Unwrap()
-					})
+					update()
 				})
 			}
 		})(args, outputs)
@@ -409,18 +1004,81 @@ func (b *Binding) processDomBind(astr, bstr string, elem jq.JQuery, bs *bindScop
 	}
 }
 
+// attrBind is a field bind of an attribute bind string, like "Field: Expression"
+type attrBind struct {
+	bstr  string
+	root  *expr
+	binds []bindable
+	field *objEval
+	info  *BindInfo
+}
+
+// assign sets the value to the custom tag's field
+func (ab *attrBind) assign(value interface{}) {
+	av, err := attrValue(value, ab.field.typ())
+	if err != nil {
+		bindStringPanic(err.Error(), ab.bstr)
+	}
+	ab.field.set(av)
+}
+
+// attrWatch is a model field watched for the field binds that depend on it
+type attrWatch struct {
+	bind  bindable
+	attrs []*attrBind
+}
+
+type attrWatchKey struct {
+	addr  uintptr
+	field string
+}
+
+// groupAttrWatches groups the field binds by the model fields they depend on, so that
+// a model field used by several of them is watched once. The order of the fields and
+// of the field binds is kept.
+func groupAttrWatches(attrs []*attrBind) []*attrWatch {
+	watches := make([]*attrWatch, 0)
+	byKey := make(map[attrWatchKey]*attrWatch)
+	for _, ab := range attrs {
+		for _, bi := range ab.binds {
+			bo := bi.bindObj()
+			key := attrWatchKey{field: bo.field}
+			switch {
+			case bo.modelRefl.Kind() == reflect.Ptr:
+				key.addr = bo.modelRefl.Pointer()
+			case bo.modelRefl.CanAddr():
+				key.addr = bo.modelRefl.UnsafeAddr()
+			}
+
+			w, ok := byKey[key]
+			if !ok || key.addr == 0 {
+				w = &attrWatch{bind: bi}
+				watches = append(watches, w)
+				byKey[key] = w
+			}
+			if len(w.attrs) == 0 || w.attrs[len(w.attrs)-1] != ab {
+				w.attrs = append(w.attrs, ab)
+			}
+		}
+	}
+
+	return watches
+}
+
+// processAttrBind binds the fields of a custom tag's model to expressions, like
+// "Field1: Expression1; Field2: Expression2". The field binds are evaluated in
+// turn, then the model fields that they depend on are watched, each once.
 func (b *Binding) processAttrBind(astr, bstr string, elem jq.JQuery, bs *bindScope, once bool, tModel interface{}) {
-	fbinds := strings.Split(bstr, ";")
+	attrs := make([]*attrBind, 0)
+	fbinds := splitTopLevel(bstr, ';', -1)
 	for i, fb := range fbinds {
 		if i == len(fbinds)-1 && fb == "" {
 			continue
 		}
-		fv := strings.Split(fb, ":")
-		if len(fv) != 2 {
-			bindStringPanic(`There should be one ":" in each attribute bind`, bstr)
+		field, valuestr, ok := splitFieldBind(fb)
+		if !ok {
+			bindStringPanic(`There should be a ":" between the field and the expression in each attribute bind`, bstr)
 		}
-		field := strings.TrimSpace(fv[0])
-		valuestr := strings.TrimSpace(fv[1])
 		for _, c := range field {
 			if !isValidExprChar(c) {
 				bindStringPanic(fmt.Sprintf("invalid character %q", c), field)
@@ -433,24 +1091,68 @@ func (b *Binding) processAttrBind(astr, bstr string, elem jq.JQuery, bs *bindSco
 		if !ok {
 			bindStringPanic(fmt.Sprintf(`No such field "%v" to bind to`, field), bstr)
 		}
-		isCompat := func(src reflect.Type, dst reflect.Type) {
-			if !src.AssignableTo(dst) {
-				bindStringPanic(fmt.Sprintf(`Unassignable, incompatible types "%v" and "%v" of the model field and the value`,
-					src.String(), dst.String()), bstr)
+
+		if oe.fieldRefl.Type() == emitterType {
+			// the handler of an event of the custom tag, it's set once
+			if !oe.fieldRefl.CanAddr() {
+				bindStringPanic(fmt.Sprintf(`The event "%v" is not addressable`, field), bstr)
 			}
+			if err := oe.fieldRefl.Addr().Interface().(*Emitter).setHandler(reflect.ValueOf(v)); err != nil {
+				bindStringPanic(err.Error(), bstr)
+			}
+			continue
 		}
-		isCompat(reflect.TypeOf(v), oe.fieldRefl.Type())
-		oe.fieldRefl.Set(reflect.ValueOf(v))
-		if !once {
-			b.watchModel(binds, roote, bs, func(newResult interface{}) {
-				nr := reflect.ValueOf(newResult)
-				isCompat(nr.Type(), oe.fieldRefl.Type())
-				oe.fieldRefl.Set(nr)
+
+		ab := &attrBind{bstr: bstr, root: roote, binds: binds, field: oe}
+		ab.assign(v)
+		ab.info = b.addBindInfo(elem, astr, strings.TrimSpace(fb), v)
+		attrs = append(attrs, ab)
+	}
+
+	if once {
+		return
+	}
+
+	for _, w := range groupAttrWatches(attrs) {
+		(func(w *attrWatch) {
+			b.watchFields(elem, []bindable{w.bind}, func() {
+				for _, ab := range w.attrs {
+					newResult, _, _ := bs.evaluateLocked(ab.root)
+					ab.info.Value = newResult.Interface()
+					ab.assign(ab.info.Value)
+				}
 			})
-		}
+		})(w)
+	}
+	for _, ab := range attrs {
+		(func(ab *attrBind) {
+			b.pollExpr(elem, ab.root, bs, func(newResult interface{}) {
+				ab.info.Value = newResult
+				ab.assign(newResult)
+			})
+		})(ab)
 	}
 }
 
+// attrValue converts the value of the expression of an attribute bind to the type of
+// the custom tag's field. The result of an arithmetic expression, like "BaseSize * 2",
+// is converted to the numeric type of the field; nil is converted to the zero value.
+func attrValue(value interface{}, typ reflect.Type) (v reflect.Value, err error) {
+	v = reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		return reflect.Zero(typ), nil
+	case v.Type().AssignableTo(typ):
+		return
+	case (isIntKind(v.Kind()) || isFloatKind(v.Kind())) && (isIntKind(typ.Kind()) || isFloatKind(typ.Kind())):
+		return v.Convert(typ), nil
+	}
+
+	err = fmt.Errorf(`Unassignable, incompatible types "%v" and "%v" of the model field and the value`,
+		v.Type().String(), typ.String())
+	return
+}
+
 func preventBinding(elem jq.JQuery, bindattr string) {
 	elem.SetAttr(strings.Join([]string{ReservedBindPrefix, bindattr}, "-"), "t")
 }
@@ -474,6 +1176,75 @@ func bindingPrevented(elem jq.JQuery, bindattr string) bool {
 		elem.Attr(strings.Join([]string{ReservedBindPrefix, bindattr}, "-")) == "t"
 }
 
+// boundMarkAttr marks an element whose binds and descendants have all been bound,
+// it's set like the marks of the binds, see wrapBindCall
+const boundMark = "bound"
+
+var boundMarkAttr = strings.Join([]string{ReservedBindPrefix, boundMark}, "-")
+
+// elemBound checks whether the element has been fully bound in a previous pass
+func elemBound(elem jq.JQuery) bool {
+	return elem.Attr(boundMarkAttr) == "t"
+}
+
+// scopedMarkAttr marks the elements bound with a model of their own,
+// in a scope nested in the scope of the elements around them
+var scopedMarkAttr = strings.Join([]string{ReservedBindPrefix, "scoped"}, "-")
+
+// fragmentMarkAttr marks the root of a fragment, which is bound before
+// it's inserted into the document, see BindFragment
+var fragmentMarkAttr = strings.Join([]string{ReservedBindPrefix, "fragment"}, "-")
+
+// elemExists checks whether the element is still in the document,
+// or in a fragment that hasn't been inserted yet
+func elemExists(elem jq.JQuery) bool {
+	return jqExists(elem) || elem.Closest("["+fragmentMarkAttr+"]").Length > 0
+}
+
+// markScoped marks the elements bound in a nested scope: the children of
+// relem, and relem itself if it's bound too
+func markScoped(relem jq.JQuery, bindrelem bool) {
+	if bindrelem {
+		relem.SetAttr(scopedMarkAttr, "t")
+	}
+	relem.Children("*").SetAttr(scopedMarkAttr, "t")
+}
+
+// clearBindingMarks removes the marks that prevent the element and its descendants
+// from being bound again, except those of elements whose binding has been removed
+// and the element ids. If keepScoped is true, the marks of the elements bound in
+// a nested scope inside relem are kept too.
+func clearBindingMarks(relem jq.JQuery, keepScoped bool) {
+	clear := func(elem jq.JQuery) {
+		htmla := elem.Get(0).Get("attributes")
+		marks := make([]string, 0)
+		for i := 0; i < htmla.Length(); i++ {
+			name := htmla.Index(i).Get("name").Str()
+			if strings.HasPrefix(name, ReservedBindPrefix+"-") &&
+				name != ReservedBindPrefix+"-all" && name != elemIdAttr && name != scopedMarkAttr && name != fragmentMarkAttr {
+				marks = append(marks, name)
+			}
+		}
+
+		for _, name := range marks {
+			elem.RemoveAttr(name)
+		}
+	}
+
+	descendants := relem.Find("*")
+	if keepScoped {
+		scoped := relem.Find("[" + scopedMarkAttr + "]")
+		descendants = descendants.Not(scoped.Add(scoped.Find("*")))
+	}
+
+	relem.Filter("*").Each(func(_ int, elem jq.JQuery) {
+		clear(elem)
+	})
+	descendants.Each(func(_ int, elem jq.JQuery) {
+		clear(elem)
+	})
+}
+
 func wrapBindCall(elem jq.JQuery, bindattr, bindstr string, fn func(jq.JQuery, string, string)) func() {
 	return func() {
 		if !bindingPrevented(elem, bindattr) {
@@ -483,85 +1254,130 @@ func wrapBindCall(elem jq.JQuery, bindattr, bindstr string, fn func(jq.JQuery, s
 	}
 }
 
-// bind parses the bind string, make a list of binds (this doesn't actually bind the elements)
+// bind parses the bind string, make a list of binds (this doesn't actually bind the elements).
+// If bindrelem is true, relem itself is processed exactly like its descendants, otherwise
+// only its descendants are.
 func (b *Binding) bindPrepare(relem jq.JQuery, bs *bindScope, once bool, bindrelem bool) (bindTasks []func(), customElemTasks []func()) {
 	if relem.Length == 0 {
 		panic("Incorrect element for bind.")
 	}
 
-	bindTasks = make([]func(), 0)
-	customElemTasks = make([]func(), 0)
-
-	elems := make([]jq.JQuery, 0)
 	if bindrelem {
-		elems = append(elems, relem)
+		return b.prepareElem(relem, bs, once)
 	}
 
+	bindTasks = make([]func(), 0)
+	customElemTasks = make([]func(), 0)
+
 	relem.Children("*").Each(func(i int, elem jq.JQuery) {
-		elems = append(elems, elem)
+		bt, cet := b.prepareElem(elem, bs, once)
+		bindTasks = append(bindTasks, bt...)
+		customElemTasks = append(customElemTasks, cet...)
 	})
 
-	for idx, elem := range elems {
-		custag, isCustom := b.tm.GetCustomTag(elem)
+	return
+}
 
-		ebs := bs.clone()
+// prepareElem makes the list of binds for an element and its descendants.
+// Elements that have been fully bound in a previous pass are skipped, see ForceRebind.
+func (b *Binding) prepareElem(elem jq.JQuery, bs *bindScope, once bool) (bindTasks []func(), customElemTasks []func()) {
+	bindTasks = make([]func(), 0)
+	customElemTasks = make([]func(), 0)
 
-		htmla := elem.Get(0).Get("attributes")
-		attrs := make(map[string]string)
-		for i := 0; i < htmla.Length(); i++ {
-			attr := htmla.Index(i)
-			attrs[attr.Get("name").Str()] = attr.Get("value").Str()
-		}
+	if elemBound(elem) {
+		return
+	}
+
+	if elem.Is(IncludeTag) {
+		return b.prepareInclude(elem, bs, once)
+	}
 
-		var customTagModel interface{} = nil
-		if isCustom {
-			customTagModel = custag.NewModel(elem)
+	if pts, ok := b.tm.(PendingTagSource); ok {
+		if loaded, pending := pts.PendingCustomTag(elem); pending {
+			b.bindWhenLoaded(elem, bs, once, loaded)
+			return
 		}
+	}
 
-		for name, bstr := range attrs {
-			if name == "bind" { //attribute binding
-				if !isCustom {
-					panic(fmt.Sprintf(`Processing bind string %v="%v": Element %v hasn't been registered as a custom element.`, name, bstr, elem.Prop("tagName")))
-				}
-				(func(customTagModel interface{}) {
-					bindTasks = append(bindTasks,
-						wrapBindCall(elem, name, bstr, func(elem jq.JQuery, astr, bstr string) {
-							b.processAttrBind(astr, bstr, elem, ebs, once, customTagModel)
-						}))
-				})(customTagModel)
-			} else if strings.HasPrefix(name, BindPrefix) && //dom binding
-				jqExists(elem) { //element still exists
-				if isCustom {
-					panic(fmt.Sprintf(`Processing bind string %v = "%v": Dom binding is not allowed for custom element tags (they should not actually be rendered
-			, so there's no point; but of course inside the custom element's contents it's allowed normally).
-			If you want to bind the attributes of a custom element, use attribute binding instead.`, name, bstr))
-				}
+	var custag CustomTag
+	isCustom := false
+	if b.tm != nil {
+		custag, isCustom = b.tm.GetCustomTag(elem)
+	}
+
+	ebs := bs.clone()
+
+	// the binds are processed in the order of the attributes, so that a repeating
+	// binder like bind-each takes the element before the binds of its items
+	htmla := elem.Get(0).Get("attributes")
+	attrs := make(map[string]string)
+	names := make([]string, htmla.Length())
+	for i := range names {
+		attr := htmla.Index(i)
+		names[i] = attr.Get("name").Str()
+		attrs[names[i]] = attr.Get("value").Str()
+	}
+
+	var customTagModel interface{} = nil
+	if isCustom {
+		customTagModel = custag.NewModel(elem)
+	}
+
+	_, isDynamic := attrs[BindPrefix+"is"]
+	isLazy := false
+	for name := range attrs {
+		isLazy = isLazy || name == BindPrefix+"lazy" || strings.HasPrefix(name, BindPrefix+"lazy-")
+	}
+
+	for _, name := range names {
+		bstr := attrs[name]
+		if name == "bind" { //attribute binding
+			if isDynamic {
+				continue //performed by the is binder for each rendered custom element
+			}
+			if !isCustom {
+				panic(fmt.Sprintf(`Processing bind string %v="%v": Element %v hasn't been registered as a custom element.`, name, bstr, elem.Prop("tagName")))
+			}
+			(func(customTagModel interface{}) {
 				bindTasks = append(bindTasks,
 					wrapBindCall(elem, name, bstr, func(elem jq.JQuery, astr, bstr string) {
-						b.processDomBind(astr, bstr, elem, ebs, once)
+						b.processAttrBind(astr, bstr, elem, ebs, once, customTagModel)
 					}))
+			})(customTagModel)
+		} else if strings.HasPrefix(name, BindPrefix) && //dom binding
+			elemExists(elem) { //element still exists
+			if isCustom {
+				panic(fmt.Sprintf(`Processing bind string %v = "%v": Dom binding is not allowed for custom element tags (they should not actually be rendered
+			, so there's no point; but of course inside the custom element's contents it's allowed normally).
+			If you want to bind the attributes of a custom element, use attribute binding instead.`, name, bstr))
 			}
+			bindTasks = append(bindTasks,
+				wrapBindCall(elem, name, bstr, func(elem jq.JQuery, astr, bstr string) {
+					b.processDomBind(astr, bstr, elem, ebs, once)
+				}))
 		}
+	}
 
-		if !bindrelem || idx > 0 {
-			if isCustom {
-				(func(elem jq.JQuery, customTagModel interface{}) {
-					customElemTasks = append(customElemTasks, func() {
-						err := custag.PrepareTagContents(elem, customTagModel)
-						if err != nil {
-							elemError(elem, err.Error())
-						}
-
-						b.Bind(elem, customTagModel, once, false)
-						elem.ReplaceWith(elem.Contents())
-					})
-				})(elem, customTagModel)
-			} else {
-				bt, cet := b.bindPrepare(elem, bs, once, false)
-				bindTasks = append(bindTasks, bt...)
-				customElemTasks = append(customElemTasks, cet...)
+	if isCustom {
+		customElemTasks = append(customElemTasks, func() {
+			err := custag.PrepareTagContents(elem, customTagModel)
+			if err != nil {
+				elemError(elem, err.Error())
 			}
-		}
+
+			b.bindNested(elem, customTagModel, once, ebs.scope)
+			b.replaceElem(elem, elem.Contents())
+		})
+	} else if !isDynamic && !isLazy { //the contents of a dynamic tag are bound by the is binder, the lazy binder binds its own
+
+		bt, cet := b.bindPrepare(elem, bs, once, false)
+		bindTasks = append(bindTasks, bt...)
+		customElemTasks = append(customElemTasks, cet...)
+	}
+
+	if !isCustom {
+		// the element is marked as bound, unless its binding has been removed
+		bindTasks = append(bindTasks, wrapBindCall(elem, boundMark, "", func(jq.JQuery, string, string) {}))
 	}
 
 	return
@@ -574,12 +1390,32 @@ func (b *Binding) Bind(relem jq.JQuery, model interface{}, once bool, bindrelem
 	b.bindWithScope(relem, once, bindrelem, s)
 }
 
+// bindNested binds a model to the contents of an element, in a scope nested in
+// the parent scope, whose models are reachable through $parent and $root
+func (b *Binding) bindNested(relem jq.JQuery, model interface{}, once bool, parent *scope) {
+	markScoped(relem, false)
+	s := newModelScope(model)
+	s.merge(b.scope)
+	s.parent = parent
+	b.bindWithScope(relem, once, false, s)
+}
+
+// ForceRebind is like Bind, but the elements that have already been bound
+// are bound again instead of being skipped. The elements bound with a model
+// of their own, like the items of the each binder or the contents of the
+// custom elements, are left to their binder.
+// The watchers of the previous binding are not removed.
+func (b *Binding) ForceRebind(relem jq.JQuery, model interface{}, once bool, bindrelem bool) {
+	clearBindingMarks(relem, true)
+	b.Bind(relem, model, once, bindrelem)
+}
+
 // BindMergeScope merges the given scope to the basic scope and performs binding
 func (b *Binding) BindModels(relem jq.JQuery, models []interface{}, once bool, bindrelem bool) {
 	s := newScope()
 	for _, model := range models {
 		if model != nil {
-			s.symTables = append(s.symTables, modelSymbolTable{reflect.ValueOf(model)})
+			s.addTables(modelSymbolTable{reflect.ValueOf(model)})
 		}
 	}
 	s.merge(b.scope)
@@ -590,6 +1426,10 @@ func (b *Binding) BindModels(relem jq.JQuery, models []interface{}, once bool, b
 func (b *Binding) bindWithScope(relem jq.JQuery, once bool, bindrelem bool, s *scope) {
 	// we have to do 2 steps like this to avoid missing out binding when things are removed
 	btasks, customElemTasks := b.bindPrepare(relem, &bindScope{s}, once, bindrelem)
+	b.runTasks(relem, btasks, customElemTasks)
+}
+
+func runBindTasks(btasks, customElemTasks []func()) {
 	for _, fn := range btasks {
 		fn()
 	}
@@ -598,3 +1438,48 @@ func (b *Binding) bindWithScope(relem jq.JQuery, once bool, bindrelem bool, s *s
 		fn()
 	}
 }
+
+// BindWhen is like Bind, but the binding is only performed when the ready channel
+// fires (receives a value or is closed), for example after the images are loaded.
+// The binds are prepared immediately. If the element has been removed from the
+// document before the signal, the binding is cancelled.
+func (b *Binding) BindWhen(relem jq.JQuery, model interface{}, ready <-chan struct{}) {
+	s := newModelScope(model)
+	s.merge(b.scope)
+	btasks, customElemTasks := b.bindPrepare(relem, &bindScope{s}, false, false)
+
+	attached := jqExists(relem)
+	whenReady(ready, func() bool {
+		return !attached || jqExists(relem)
+	}, func() {
+		b.runTasks(relem, btasks, customElemTasks)
+	})
+}
+
+// bindWhenLoaded binds the element of a custom tag that's loading
+// in the given scope, once the tag is loaded
+func (b *Binding) bindWhenLoaded(elem jq.JQuery, bs *bindScope, once bool, loaded <-chan struct{}) {
+	elem.AddClass(TagLoadingClass)
+	attached := jqExists(elem)
+	whenReady(loaded, func() bool {
+		return !attached || jqExists(elem)
+	}, func() {
+		elem.RemoveClass(TagLoadingClass)
+		b.bindWithScope(elem, once, true, bs.scope)
+	})
+}
+
+// whenReady waits for the ready signal in a goroutine and then calls fn if alive()
+// is still true. The returned channel is closed after that.
+func whenReady(ready <-chan struct{}, alive func() bool, fn func()) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-ready
+		if alive() {
+			fn()
+		}
+	}()
+
+	return done
+}
@@ -0,0 +1,562 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+func TestWhenReady(t *testing.T) {
+	ready := make(chan struct{})
+	bound := make(chan bool, 1)
+	done := whenReady(ready, func() bool { return true }, func() {
+		bound <- true
+	})
+
+	select {
+	case <-bound:
+		t.Fatalf("The binding should be withheld until the ready signal.")
+	default:
+	}
+
+	close(ready)
+	<-done
+	select {
+	case <-bound:
+	default:
+		t.Errorf("The binding should be performed after the ready signal.")
+	}
+
+	ready = make(chan struct{})
+	done = whenReady(ready, func() bool { return false }, func() {
+		bound <- true
+	})
+	ready <- struct{}{}
+	<-done
+	select {
+	case <-bound:
+		t.Errorf("The binding should be cancelled when the element has been removed.")
+	default:
+	}
+}
+
+type testCart struct {
+	Customer string
+	Price    int
+	Quantity int
+}
+
+func TestEval(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testCart{"wade", 15, 3}
+	tests := map[string]interface{}{
+		"Customer":                   "wade",
+		"toUpper(Customer)":          "WADE",
+		"Price * Quantity + 5":       50,
+		"Quantity > 2 && Price < 20": true,
+		"concat(Customer, `-shop`)":  "wade-shop",
+	}
+	for bstr, expected := range tests {
+		v, err := b.Eval(model, bstr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", bstr, err)
+		} else if v != expected {
+			t.Errorf("%v: expected %v, got %v.", bstr, expected, v)
+		}
+	}
+
+	for _, bstr := range []string{"Nothing", "Price +", "toUpper(Price)"} {
+		if _, err := b.Eval(model, bstr); err == nil {
+			t.Errorf("Expected an error for %v.", bstr)
+		}
+	}
+
+	e1, _ := parseCached("Price * Quantity + 5")
+	e2, _ := parseCached("Price * Quantity + 5")
+	if e1 == nil || e1 != e2 {
+		t.Errorf("Expected the parsed expression to be cached.")
+	}
+
+	for i := 0; i < ExprCacheSize+10; i++ {
+		parseCached(fmt.Sprintf("Price * %v", i))
+		if i%100 == 0 {
+			parseCached("Price * Quantity + 5")
+		}
+	}
+	if n := exprCache.len(); n != ExprCacheSize {
+		t.Errorf("Expected the cache to be bounded to %v expressions, got %v.", ExprCacheSize, n)
+	}
+	if e3, _ := parseCached("Price * Quantity + 5"); e3 != e1 {
+		t.Errorf("Expected a recently used expression to stay in the cache.")
+	}
+	if _, ok := exprCache.get("Price * 0"); ok {
+		t.Errorf("Expected the least recently used expression to be dropped.")
+	}
+}
+
+type testShadowing struct {
+	Name string
+}
+
+func (m *testShadowing) Format(s string) string {
+	return "model " + s
+}
+
+func TestHelperPrecedence(t *testing.T) {
+	b := NewBindEngine(nil)
+	b.RegisterHelper("Format", func(s string) string {
+		return "helper " + s
+	})
+	model := &testShadowing{"wade"}
+	item := map[string]interface{}{"x": "item"}
+
+	// the scope of an item of bind-each, nested in the model's scope
+	eval := func(bstr string) interface{} {
+		s := newModelScope(item)
+		s.merge(newModelScope(model))
+		s.merge(b.scope)
+		_, _, v, err := (&bindScope{s}).evaluate(bstr)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", bstr, err)
+		}
+		return v
+	}
+
+	if v, _ := b.Eval(model, "Format(Name)"); v != "model wade" {
+		t.Errorf("Expected the model method to win by default, got %v.", v)
+	}
+	if v := eval("Format(x)"); v != "model item" {
+		t.Errorf("Expected the model method to win in a nested scope, got %v.", v)
+	}
+	if errs := b.Check(`<p bind-text="toUpper(Format(Name))"></p>`, model); len(errs) != 0 {
+		t.Errorf("Unexpected errors %v.", errs)
+	}
+
+	bound := newModelScope(model)
+	bound.merge(b.scope)
+	if len(bound.lookupOrder) != 2 || bound.lookupOrder[0] != bound.symTables[0] {
+		t.Fatalf("Expected the lookup order to be built when merging, got %v.", bound.lookupOrder)
+	}
+
+	b.HelperPrecedence = HelperFirst
+	if _, _, v, _ := (&bindScope{bound}).evaluate("Format(Name)"); v != "model wade" {
+		t.Errorf("Expected a scope made before the change to keep its order, got %v.", v)
+	}
+	if v, _ := b.Eval(model, "Format(Name)"); v != "helper wade" {
+		t.Errorf("Expected the helper to win, got %v.", v)
+	}
+	if v := eval("Format(x)"); v != "helper item" {
+		t.Errorf("Expected the helper to win in a nested scope, got %v.", v)
+	}
+	if v, _ := b.Eval(model, "toUpper(Name)"); v != "WADE" {
+		t.Errorf("Expected the model fields to be found after the helpers, got %v.", v)
+	}
+}
+
+type testSettings struct {
+	values map[string]string
+}
+
+func (s *testSettings) Get(key string) string {
+	return s.values[key]
+}
+
+func (s *testSettings) Set(key string, value string) {
+	s.values[key] = value
+}
+
+func (s *testSettings) LimitGet(key string) int {
+	return len(s.values[key])
+}
+
+func TestIndexedAccessors(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testSettings{map[string]string{"name": "wade"}}
+	bs := &bindScope{newModelScope(model)}
+	bs.scope.merge(b.scope)
+
+	_, blist, v, err := bs.evaluate("Get(`name`)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v != "wade" {
+		t.Errorf("Expected wade, got %v.", v)
+	}
+	if len(blist) != 1 || !blist[0].bindObj().canSet() {
+		t.Fatalf("Expected a settable bindable, got %v.", blist)
+	}
+
+	// what the 2-way binding does with the new value of the element
+	oe := blist[0].bindObj()
+	nv, err := convertString("gopher", oe.typ())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	oe.set(nv)
+	if model.values["name"] != "gopher" {
+		t.Errorf("Expected the value to be set through Set, got %v.", model.values["name"])
+	}
+
+	// no paired setter
+	_, blist, v, err = bs.evaluate("LimitGet(`name`)")
+	if err != nil || v != 6 {
+		t.Fatalf("Unexpected result %v, %v.", v, err)
+	}
+	if len(blist) != 1 || blist[0].bindObj().canSet() {
+		t.Errorf("Expected the call without a setter not to be settable.")
+	}
+}
+
+type testPage struct {
+	Title string
+	User  string
+}
+
+type testComment struct {
+	Title string
+}
+
+func TestAncestorScopes(t *testing.T) {
+	b := NewBindEngine(nil)
+	page := &testPage{"Page", "wade"}
+	comment := &testComment{"Comment"}
+	item := map[string]interface{}{"$item": "reply", "$index": 0}
+
+	// a bind-list item inside a custom tag used in the page
+	pageScope := newModelScope(page)
+	pageScope.merge(b.scope)
+	tagScope := newModelScope(comment)
+	tagScope.merge(b.scope)
+	tagScope.parent = pageScope
+	s := newModelScope(item)
+	s.merge(tagScope)
+	bs := (&bindScope{s}).clone()
+
+	tests := map[string]interface{}{
+		"Title":                 "Comment",
+		"$parent.Title":         "Comment",
+		"$parent.$parent.Title": "Page",
+		"$root.User":            "wade",
+		"toUpper($root.Title)":  "PAGE",
+		"$item":                 "reply",
+	}
+	for bstr, expected := range tests {
+		_, blist, v, err := bs.evaluate(bstr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", bstr, err)
+		} else if v != expected {
+			t.Errorf("%v: expected %v, got %v.", bstr, expected, v)
+		}
+		if bstr == "$root.User" && (len(blist) != 1 || blist[0].bindObj().field != "User") {
+			t.Errorf("Expected the root field to be watched, got %v.", blist)
+		}
+	}
+
+	if _, _, v, _ := bs.evaluate("$root"); v != page {
+		t.Errorf("Expected $root to be the page model, got %v.", v)
+	}
+	if _, err := b.Eval(page, "$parent.Title"); err == nil {
+		t.Errorf("Expected an error for $parent in the outermost scope.")
+	}
+	if errs := b.Check(`<p bind-text="$root.User"></p><p bind-text="$parent.Anything"></p>`, page); len(errs) != 0 {
+		t.Errorf("Unexpected errors %v.", errs)
+	}
+	if errs := b.Check(`<p bind-text="$root.Nothing"></p>`, page); len(errs) != 1 {
+		t.Errorf("Expected an error for an unknown root field, got %v.", errs)
+	}
+}
+
+type testEditor struct {
+	State string
+}
+
+func TestConstants(t *testing.T) {
+	b := NewBindEngine(nil)
+	b.RegisterConstant("States", struct{ Editing, Done string }{"editing", "done"})
+	b.RegisterConstant("MaxLength", 3)
+	model := &testEditor{"editing"}
+
+	tests := map[string]interface{}{
+		"State == States.Editing": true,
+		"State == States.Done":    false,
+		"States.Done":             "done",
+		"MaxLength + 1":           4,
+	}
+	for bstr, expected := range tests {
+		v, err := b.Eval(model, bstr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", bstr, err)
+		} else if v != expected {
+			t.Errorf("%v: expected %v, got %v.", bstr, expected, v)
+		}
+	}
+
+	bs := &bindScope{newModelScope(model)}
+	bs.scope.merge(b.scope)
+	if _, blist, _, _ := bs.evaluate("States.Editing"); len(blist) != 0 {
+		t.Errorf("Expected a constant not to be bindable, got %v.", blist)
+	}
+	if _, err := b.Eval(model, "States.Nothing"); err == nil {
+		t.Errorf("Expected an error for an unknown field of a constant.")
+	}
+	if errs := b.Check(`<p bind-class-editing="State == States.Editing"></p>`, model); len(errs) != 0 {
+		t.Errorf("Unexpected errors %v.", errs)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a constant shadowing a helper.")
+		}
+	}()
+	b.RegisterConstant("toUpper", "x")
+}
+
+type testGallery struct {
+	BaseSize int
+	Label    string
+	Caption  *string
+}
+
+type testThumbnail struct {
+	Size    int64
+	Width   float64
+	Title   string
+	Caption *string
+	Classes map[string]interface{}
+}
+
+func TestAttrBindExpressions(t *testing.T) {
+	b := NewBindEngine(nil)
+	parent := &testGallery{BaseSize: 10, Label: "photos"}
+	s := newModelScope(parent)
+	s.merge(b.scope)
+	bstr := "Size: BaseSize * 2; Width: BaseSize / 4; Title: concat(toUpper(Label), `-x`); Caption: Caption"
+
+	child := &testThumbnail{}
+	b.processAttrBind("bind", bstr, jq.JQuery{}, &bindScope{s}, true, child)
+	if child.Size != 20 || child.Width != 2 || child.Title != "PHOTOS-x" || child.Caption != nil {
+		t.Errorf("Unexpected child model %+v.", child)
+	}
+
+	// what the watchers do when the parent changes
+	parent.BaseSize = 15
+	b.processAttrBind("bind", bstr, jq.JQuery{}, &bindScope{s}, true, child)
+	if child.Size != 30 || child.Width != 3 {
+		t.Errorf("Expected the child fields to be updated, got %+v.", child)
+	}
+
+	if v, err := attrValue(7, reflect.TypeOf(float32(0))); err != nil || v.Interface() != float32(7) {
+		t.Errorf("Expected the number to be converted, got %v, %v.", v, err)
+	}
+	if _, err := attrValue("7", reflect.TypeOf(0)); err == nil {
+		t.Errorf("Expected an error for a string bound to a number field.")
+	}
+
+	// the colons of map literals don't separate the field and the expression,
+	// and the parentheses of string literals are not counted
+	b.processAttrBind("bind", "Classes: {large: BaseSize > 12, label: concat(Label, ` (x`)}; Title: Label",
+		jq.JQuery{}, &bindScope{s}, true, child)
+	if expected := map[string]interface{}{"large": true, "label": "photos (x"}; !reflect.DeepEqual(child.Classes, expected) {
+		t.Errorf("Expected the map literal %v, got %v.", expected, child.Classes)
+	}
+	if child.Title != "photos" {
+		t.Errorf("Expected the field bind after the map literal to be processed, got %+v.", child)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a field bind without a colon.")
+		}
+	}()
+	b.processAttrBind("bind", "Title Label", jq.JQuery{}, &bindScope{s}, true, child)
+}
+
+func TestAttrBindBatch(t *testing.T) {
+	b := NewBindEngine(nil)
+	parent := &testGallery{BaseSize: 12, Label: "photos"}
+	s := newModelScope(parent)
+	s.merge(b.scope)
+	fields := []string{"Size: BaseSize * 2", "Width: BaseSize / 4", "Title: concat(Label, `-x`)", "Caption: Caption"}
+
+	batched := &testThumbnail{}
+	b.processAttrBind("bind", strings.Join(fields, "; "), jq.JQuery{}, &bindScope{s}, true, batched)
+	single := &testThumbnail{}
+	for _, field := range fields {
+		b.processAttrBind("bind", field, jq.JQuery{}, &bindScope{s}, true, single)
+	}
+	if !reflect.DeepEqual(batched, single) {
+		t.Errorf("Expected the same result as binding the fields one by one, got %+v and %+v.", batched, single)
+	}
+
+	attrs := make([]*attrBind, len(fields))
+	for i, field := range fields {
+		root, binds, _, err := (&bindScope{s}).evaluate(strings.Split(field, ":")[1])
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		attrs[i] = &attrBind{bstr: field, root: root, binds: binds}
+	}
+	watches := groupAttrWatches(attrs)
+	watched := make([]string, 0)
+	for _, w := range watches {
+		watched = append(watched, w.bind.bindObj().field)
+	}
+	if !reflect.DeepEqual(watched, []string{"BaseSize", "Label", "Caption"}) {
+		t.Fatalf("Expected each model field to be watched once, got %v.", watched)
+	}
+	if len(watches[0].attrs) != 2 || watches[0].attrs[0] != attrs[0] || watches[0].attrs[1] != attrs[1] {
+		t.Errorf("Expected BaseSize to update both the fields depending on it.")
+	}
+}
+
+func BenchmarkAttrBind(bm *testing.B) {
+	b := NewBindEngine(nil)
+	parent := &testGallery{BaseSize: 12, Label: "photos"}
+	s := newModelScope(parent)
+	s.merge(b.scope)
+	bstr := "Size: BaseSize * 2; Width: BaseSize / 4; Title: concat(toUpper(Label), `-x`); Caption: Caption"
+	child := &testThumbnail{}
+
+	bm.ResetTimer()
+	for i := 0; i < bm.N; i++ {
+		b.processAttrBind("bind", bstr, jq.JQuery{}, &bindScope{s}, true, child)
+	}
+}
+
+type testState string
+
+const (
+	testStateOpen   testState = "open"
+	testStateClosed testState = "closed"
+)
+
+type testLevel int
+
+type testFlag bool
+
+type testTicket struct {
+	State  testState
+	Level  testLevel
+	Urgent testFlag
+}
+
+func (t *testTicket) Is(state testState) bool {
+	return t.State == state
+}
+
+func TestNamedTypes(t *testing.T) {
+	b := NewBindEngine(nil)
+	b.RegisterConstant("Closed", testStateClosed)
+	model := &testTicket{testStateOpen, 2, true}
+
+	tests := map[string]interface{}{
+		"State":                   testStateOpen,
+		"State == 'open'":         true,
+		"State != `open`":         false,
+		"State == Closed":         false,
+		"Level == 2":              true,
+		"Level > 1 && Urgent":     true,
+		"Urgent == true":          true,
+		"Level + 1":               3,
+		"toUpper(State)":          "OPEN",
+		"concat(State, `-state`)": "open-state",
+		"Is('open')":              true,
+		"Is(Closed)":              false,
+	}
+	for bstr, expected := range tests {
+		v, err := b.Eval(model, bstr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", bstr, err)
+		} else if v != expected {
+			t.Errorf("%v: expected %v, got %v.", bstr, expected, v)
+		}
+	}
+
+	if s := toString(model.State); s != "open" {
+		t.Errorf("Expected the named string to be rendered as it is, got %v.", s)
+	}
+	if s := (DomBind{Value: model.Level, binding: b}).ValueString(); s != "2" {
+		t.Errorf("Expected the named int to be rendered as it is, got %v.", s)
+	}
+
+	html := `<p bind-text="toUpper(State)" bind-class-open="Is('open')"></p>`
+	if errs := b.Check(html, model); len(errs) != 0 {
+		t.Errorf("Unexpected errors %v.", errs)
+	}
+	if errs := b.Check(`<p bind-text="toUpper(Level)"></p>`, model); len(errs) != 1 {
+		t.Errorf("Expected an error for a named int passed as a string, got %v.", errs)
+	}
+}
+
+type testBadge struct {
+	Label string
+	Tags  []string
+}
+
+func TestBindTwice(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	elem := gJQ(`<div><p bind-text="Label"></p><ul><li bind-each="Tags -> _, tag"><span bind-text="tag"></span></li></ul></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+	prototype := elem.Find("li")
+
+	b.Bind(elem, &testBadge{"new", []string{"a", "b"}}, false, false)
+	if elem.Find("p").Text() != "new" || len(w.targets) != 2 {
+		t.Fatalf("Expected the element to be bound, got %v with %v watchers.", elem.Html(), len(w.targets))
+	}
+	if elemBound(prototype) || prototype.Find("span").Attr(boundMarkAttr) != "" {
+		t.Errorf("Expected the elements whose binding is removed not to be marked as bound.")
+	}
+
+	// the elements bound in the first pass are skipped
+	b.Bind(elem, &testBadge{"other", []string{"c"}}, false, false)
+	if elem.Find("p").Text() != "new" || elem.Find("span").Text() != "ab" || len(w.targets) != 2 {
+		t.Errorf("Expected the bound elements to be skipped, got %v with %v watchers.", elem.Html(), len(w.targets))
+	}
+
+	// the elements added since are bound by the second pass
+	elem.Append(`<em bind-text="Label"></em>`)
+	b.Bind(elem, &testBadge{"other", nil}, false, false)
+	if elem.Find("p").Text() != "new" || elem.Find("em").Text() != "other" || len(w.targets) != 3 {
+		t.Errorf("Expected only the new element to be bound, got %v with %v watchers.", elem.Html(), len(w.targets))
+	}
+
+	b.ForceRebind(elem, &testBadge{"forced", []string{"c"}}, false, false)
+	if elem.Find("p").Text() != "forced" || elem.Find("em").Text() != "forced" || len(w.targets) != 5 {
+		t.Errorf("Expected the elements to be bound again, got %v with %v watchers.", elem.Html(), len(w.targets))
+	}
+	if elem.Find("span").Text() != "ab" {
+		t.Errorf("Expected the items to be left to the each binder, got %v.", elem.Html())
+	}
+}
+
+func TestBindRootElem(t *testing.T) {
+	b := NewBindEngine(nil)
+	b.fields = newFakeWatcher()
+	html := `<p bind-text="Label" bind-attr-title="Label"></p>`
+
+	elem := gJQ(html).AppendTo(gJQ("body"))
+	defer elem.Remove()
+	b.Bind(elem, &testBadge{Label: "new"}, false, true)
+	if elem.Text() != "new" || elem.Attr("title") != "new" || !elemBound(elem) {
+		t.Errorf("Expected the dom binders of the root element to be applied, got %v.", elem.Prop("outerHTML"))
+	}
+
+	other := gJQ(html).AppendTo(gJQ("body"))
+	defer other.Remove()
+	b.Bind(other, &testBadge{Label: "new"}, false, false)
+	if other.Text() != "" || other.Attr("title") != "" {
+		t.Errorf("Expected the root element not to be bound, got %v.", other.Prop("outerHTML"))
+	}
+
+	// elements outside of the document are not bound
+	detached := gJQ(html)
+	b.Bind(detached, &testBadge{Label: "new"}, false, true)
+	if detached.Text() != "" || detached.Attr("title") != "" {
+		t.Errorf("Expected the detached element not to be bound, got %v.", detached.Prop("outerHTML"))
+	}
+}
@@ -0,0 +1,44 @@
+package bind
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gopherjs/gopherjs/js"
+	jq "github.com/gopherjs/jquery"
+)
+
+// browser gives the binders what the elements don't hold: the time, the
+// delayed calls and the css transitions. The binders work with the elements
+// directly, only these are replaced by a fake in the tests.
+type browser interface {
+	now() time.Time
+	// after calls fn once the delay has passed, unless stop is called before
+	after(d time.Duration, fn func()) (stop func())
+	// transitioning checks whether the element has a css transition or animation
+	transitioning(elem jq.JQuery) bool
+}
+
+// jsBrowser is the default browser
+type jsBrowser struct{}
+
+func (jsBrowser) now() time.Time {
+	return time.Now()
+}
+
+func (jsBrowser) after(d time.Duration, fn func()) func() {
+	timer := time.AfterFunc(d, fn)
+	return func() { timer.Stop() }
+}
+
+func (jsBrowser) transitioning(elem jq.JQuery) bool {
+	style := js.Global.Call("getComputedStyle", elem.Get(0))
+	for _, prop := range []string{"transitionDuration", "animationDuration"} {
+		for _, d := range strings.Split(style.Get(prop).Str(), ",") {
+			if d = strings.TrimSpace(d); d != "" && d != "0s" && d != "0ms" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package bind
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lruCache is a cache holding at most size entries, the least recently used
+// entry is dropped to make room for a new one. It's safe for concurrent use.
+type lruCache struct {
+	sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+func newLruCache(size int) *lruCache {
+	return &lruCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the value cached for the key, which becomes the most recently used
+func (c *lruCache) get(key string) (value interface{}, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return
+	}
+
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEntry).value, true
+}
+
+// add caches the value for the key, dropping the least recently used entry if the cache is full
+func (c *lruCache) add(key string, value interface{}) {
+	c.Lock()
+	defer c.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruEntry).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key, value})
+	if c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.entries, last.Value.(*lruEntry).key)
+	}
+}
+
+// len returns the number of cached entries
+func (c *lruCache) len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.order.Len()
+}
@@ -0,0 +1,511 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlTagRegexp  = regexp.MustCompile(`<(/?)([a-zA-Z][\w-]*)((?:[^>"']|"[^"]*"|'[^']*')*?)(/?)>`)
+	htmlAttrRegexp = regexp.MustCompile(`([^\s=/]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+)))?`)
+
+	voidElements = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true,
+		"hr": true, "img": true, "input": true, "link": true, "meta": true,
+		"param": true, "source": true, "track": true, "wbr": true,
+	}
+)
+
+// typeInfo is the static type information of an expression,
+// a nil typ means the type is only known at runtime
+type typeInfo struct {
+	typ reflect.Type
+	// method indicates that typ is the type of a method, with the receiver
+	// as the first parameter
+	method bool
+}
+
+// typeScope is used for checking bind strings statically, it resolves
+// symbols to their types instead of their values
+type typeScope struct {
+	dynamic    map[string]bool
+	model      reflect.Type
+	helpers    mapSymbolTable
+	precedence Precedence
+
+	// item is the type of the items in the predicate of the filter helper,
+	// inItem is false outside of it
+	item   reflect.Type
+	inItem bool
+
+	// used records the model fields and the helpers that are referenced, if not nil
+	used map[string]bool
+}
+
+func (s *typeScope) use(name string) {
+	if s.used != nil {
+		s.used[strings.TrimSuffix(name, "?")] = true
+	}
+}
+
+func (s *typeScope) lookup(symbol string) (ti typeInfo, err error) {
+	if s.precedence == HelperFirst {
+		if ti, ok := s.lookupHelper(symbol); ok {
+			s.use(symbol)
+			return ti, nil
+		}
+	}
+
+	flist := strings.Split(symbol, ".")
+	switch strings.TrimSuffix(flist[0], "?") {
+	case ParentSymbol:
+		// depends on where the template is used
+		return
+	case DirtySymbol:
+		return typeInfo{typ: reflect.TypeOf(false)}, nil
+	case RootSymbol:
+		if len(flist) == 1 {
+			return
+		}
+		flist = flist[1:]
+	}
+	if s.dynamic[strings.TrimSuffix(flist[0], "?")] {
+		return
+	}
+
+	if s.inItem {
+		if flist[0] == ItemSymbol {
+			if ti, ok := typeOfField(s.item, flist[1:]); ok || s.item == nil {
+				return ti, nil
+			}
+			err = fmt.Errorf(`Unable to find "%v" in the items`, symbol)
+			return
+		}
+		if s.item == nil {
+			// the fields of the items are only known at runtime
+			return
+		}
+		if ti, ok := typeOfField(s.item, flist); ok {
+			return ti, nil
+		}
+	}
+
+	if s.model != nil {
+		var ok bool
+		if ti, ok = typeOfField(s.model, flist); ok {
+			s.use(flist[0])
+			return
+		}
+	}
+
+	if ti, ok := s.lookupHelper(symbol); ok {
+		s.use(symbol)
+		return ti, nil
+	}
+
+	err = fmt.Errorf(`Unable to find symbol "%v" in the scope`, symbol)
+	return
+}
+
+func (s *typeScope) lookupHelper(symbol string) (ti typeInfo, ok bool) {
+	if sym, found := s.helpers.lookup(symbol); found {
+		switch hs := sym.(type) {
+		case funcSymbol:
+			return typeInfo{typ: hs.fn.Type()}, true
+		case valueSymbol:
+			if hs.v.IsValid() {
+				return typeInfo{typ: hs.v.Type()}, true
+			}
+			return typeInfo{}, true
+		}
+	}
+	return
+}
+
+// typeOfField is the static version of evaluateObjField, it resolves the type of a
+// field (obj.field1.field2) of the given type
+func typeOfField(typ reflect.Type, flist []string) (ti typeInfo, ok bool) {
+	ti.typ = typ
+	for i, field := range flist {
+		field = strings.TrimSuffix(field, "?")
+		t := ti.typ
+		if t == nil {
+			return ti, true
+		}
+		if ti.method {
+			return ti, false
+		}
+
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if i > 0 && i == len(flist)-1 && isLengthField(field) {
+			switch t.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+				// a map key named like the pseudo-property is not known statically
+				ti.typ = reflect.TypeOf(0)
+				continue
+			}
+		}
+
+		switch t.Kind() {
+		case reflect.Struct:
+			if sf, found := t.FieldByName(field); found {
+				ti.typ = sf.Type
+				continue
+			}
+			if m, found := reflect.PtrTo(t).MethodByName(field); found {
+				ti = typeInfo{m.Type, true}
+				continue
+			}
+			if m, found := reflect.PtrTo(t).MethodByName(field + AccessorGetSuffix); found &&
+				m.Type.NumIn() == 1 && m.Type.NumOut() == 1 {
+				ti.typ = m.Type.Out(0)
+				continue
+			}
+		case reflect.Map:
+			ti.typ = t.Elem()
+			continue
+		case reflect.Interface:
+			ti.typ = nil
+			continue
+		}
+
+		return ti, false
+	}
+
+	return ti, true
+}
+
+// check statically checks the parsed expression, it returns the type of the result
+func (s *typeScope) check(e *expr) (ti typeInfo, err error) {
+	if e.typ == MapExpr {
+		for _, arg := range e.args {
+			if _, err = s.check(arg); err != nil {
+				return
+			}
+		}
+		ti.typ = reflect.TypeOf(map[string]interface{}{})
+		return
+	}
+
+	if e.typ == IndexExpr {
+		return s.checkIndex(e)
+	}
+
+	if e.typ != OpExpr {
+		litVal, isLiteral, er := parseExpr(e.name)
+		if er != nil {
+			err = er
+			return
+		}
+		if isLiteral {
+			ti.typ = reflect.TypeOf(litVal)
+			return
+		}
+	}
+
+	if s.isFilterCall(e) {
+		return s.checkFilter(e)
+	}
+
+	args := make([]typeInfo, len(e.args))
+	for i, arg := range e.args {
+		args[i], err = s.check(arg)
+		if err != nil {
+			return
+		}
+	}
+
+	if e.typ == OpExpr {
+		types := make([]reflect.Type, len(args))
+		for i, arg := range args {
+			if arg.typ == nil || arg.method {
+				return
+			}
+			types[i] = arg.typ
+		}
+		ti.typ, err = operatorType(e.name, types)
+		return
+	}
+
+	ti, err = s.lookup(e.name)
+	if err != nil || e.typ != CallExpr || ti.typ == nil {
+		return
+	}
+
+	return checkCall(e.name, ti, args)
+}
+
+// isFilterCall is the static version of bindScope.isFilterCall
+func (s *typeScope) isFilterCall(e *expr) bool {
+	if e.typ != CallExpr || len(e.args) != 2 {
+		return false
+	}
+	if s.precedence != HelperFirst && s.model != nil {
+		if _, ok := typeOfField(s.model, []string{e.name}); ok {
+			return false
+		}
+	}
+
+	sym, ok := s.helpers.lookup(e.name)
+	if !ok {
+		return false
+	}
+	fs, ok := sym.(funcSymbol)
+	return ok && fs.fn.Pointer() == filterHelperPtr
+}
+
+// checkFilter checks a call of the filter helper, the predicate is checked
+// with the fields of the items in scope
+func (s *typeScope) checkFilter(e *expr) (ti typeInfo, err error) {
+	slice, err := s.check(e.args[0])
+	if err != nil {
+		return
+	}
+
+	is := *s
+	is.inItem, is.item = true, nil
+	if slice.typ != nil && !slice.method {
+		switch slice.typ.Kind() {
+		case reflect.Slice, reflect.Array:
+			is.item = slice.typ.Elem()
+			ti.typ = reflect.SliceOf(is.item)
+		default:
+			err = fmt.Errorf(`filter helper: unsupported type %v, expected a slice.`, slice.typ)
+			return
+		}
+	}
+
+	_, err = is.check(e.args[1])
+	return
+}
+
+// checkIndex checks an index expression, it returns the type of the element or of
+// the field following the brackets
+func (s *typeScope) checkIndex(e *expr) (ti typeInfo, err error) {
+	target, err := s.check(e.args[0])
+	if err != nil {
+		return
+	}
+	if _, err = s.check(e.args[1]); err != nil {
+		return
+	}
+
+	t := target.typ
+	if t == nil || target.method {
+		return
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		ti.typ = t.Elem()
+	case reflect.String:
+		ti.typ = reflect.TypeOf(byte(0))
+	case reflect.Interface:
+		return
+	default:
+		err = fmt.Errorf(`Cannot index a value of type "%v"`, t)
+		return
+	}
+
+	if isLengthField(e.name) {
+		switch ti.typ.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+			ti.typ = reflect.TypeOf(0)
+			return
+		}
+	}
+
+	if e.name != "" {
+		elem := ti.typ
+		var ok bool
+		if ti, ok = typeOfField(elem, strings.Split(e.name, ".")); !ok {
+			err = fmt.Errorf(`Unable to find "%v" in the indexed value of type "%v"`, e.name, elem)
+		}
+	}
+	return
+}
+
+// checkCall checks the number and the types of the arguments of a function call,
+// it returns the type of the call's result
+func checkCall(name string, fn typeInfo, args []typeInfo) (ti typeInfo, err error) {
+	ftype := fn.typ
+	if ftype.Kind() != reflect.Func {
+		err = fmt.Errorf(`Cannot call "%v", it's not a function`, name)
+		return
+	}
+
+	params := make([]reflect.Type, 0)
+	for i := 0; i < ftype.NumIn(); i++ {
+		if !fn.method || i > 0 {
+			params = append(params, ftype.In(i))
+		}
+	}
+
+	nin := len(params)
+	if ftype.IsVariadic() {
+		if len(args) < nin-1 {
+			err = fmt.Errorf(`"%v": Invalid number of arguments, expected at least %v, got %v`, name, nin-1, len(args))
+			return
+		}
+	} else if len(args) != nin {
+		err = fmt.Errorf(`"%v": Invalid number of arguments, expected %v, got %v`, name, nin, len(args))
+		return
+	}
+
+	for i, arg := range args {
+		var param reflect.Type
+		if ftype.IsVariadic() && i >= nin-1 {
+			param = params[nin-1].Elem()
+		} else {
+			param = params[i]
+		}
+
+		if arg.typ != nil && !arg.method && !argConvertible(arg.typ, param) {
+			err = fmt.Errorf(`"%v": Argument %v of type "%v" is incompatible with parameter type "%v"`,
+				name, i+1, arg.typ, param)
+			return
+		}
+	}
+
+	if ftype.NumOut() > 0 {
+		ti.typ = ftype.Out(0)
+	}
+	return
+}
+
+// checkBindString statically checks a bind string of the given dom binder
+func (b *Binding) checkBindString(binder, bstr string, ts *typeScope) (outputs []string, err error) {
+	if _, ok := b.domBinders[binder]; !ok {
+		err = fmt.Errorf(`Dom binder "%v" does not exist`, binder)
+		return
+	}
+
+	parts := strings.Split(bstr, "->")
+	if len(parts) > 1 {
+		for _, output := range strings.Split(parts[1], ",") {
+			outputs = append(outputs, strings.TrimSpace(output))
+		}
+	}
+	if binder == "list" {
+		outputs = append(outputs, "$item", "$index")
+	}
+
+	root, err := parseCached(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return
+	}
+
+	_, err = ts.check(root)
+	return
+}
+
+// Check statically validates the bindings of a template against a model, without a DOM.
+// It parses all bind strings and resolves their symbols against the model's type to
+// report unknown symbols, invalid numbers of arguments and incompatible types of
+// function calls.
+//
+// The outputs of a binder (for example the key and the value of bind-each) are
+// only known at runtime, so they are not checked. Likewise, the contents of custom
+// element tags (including the ones rendered by bind-is) are bound to the custom
+// element's model, so they are skipped.
+func (b *Binding) Check(templateHTML string, model interface{}) []error {
+	return b.checkTemplate(templateHTML, model, nil)
+}
+
+// checkTemplate checks the bindings of the template, recording the referenced
+// symbols in used if it's not nil
+func (b *Binding) checkTemplate(templateHTML string, model interface{}, used map[string]bool) []error {
+	errs := make([]error, 0)
+
+	var mtype reflect.Type
+	if model != nil {
+		mtype = reflect.TypeOf(model)
+	}
+
+	type element struct {
+		name    string
+		outputs []string
+		custom  bool
+	}
+	stack := make([]element, 0)
+
+	for _, m := range htmlTagRegexp.FindAllStringSubmatch(templateHTML, -1) {
+		closing, name, attrstr, selfClosing := m[1] == "/", strings.ToLower(m[2]), m[3], m[4] == "/"
+		if closing {
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+			continue
+		}
+
+		skipped := false
+		ts := &typeScope{
+			dynamic:    make(map[string]bool),
+			model:      mtype,
+			helpers:    b.helpers,
+			precedence: b.HelperPrecedence,
+			used:       used,
+		}
+		for _, elem := range stack {
+			skipped = skipped || elem.custom
+			for _, output := range elem.outputs {
+				ts.dynamic[output] = true
+			}
+		}
+
+		elem := element{name: name}
+		for _, am := range htmlAttrRegexp.FindAllStringSubmatch(attrstr, -1) {
+			aname, bstr := strings.ToLower(am[1]), am[2]+am[3]+am[4]
+			switch {
+			case aname == "bind":
+				elem.custom = true
+				if skipped {
+					continue
+				}
+				for _, fb := range splitTopLevel(bstr, ';', -1) {
+					_, expr, ok := splitFieldBind(fb)
+					if !ok {
+						continue
+					}
+					root, err := parseCached(expr)
+					if err == nil {
+						_, err = ts.check(root)
+					}
+					if err != nil {
+						errs = append(errs, fmt.Errorf(`%v="%v": %v`, aname, bstr, err.Error()))
+					}
+				}
+			case strings.HasPrefix(aname, BindPrefix):
+				if aname == BindPrefix+"is" {
+					elem.custom = true
+				}
+				if skipped {
+					continue
+				}
+				binder := strings.Split(aname, "-")[1]
+				outputs, err := b.checkBindString(binder, bstr, ts)
+				if err != nil {
+					errs = append(errs, fmt.Errorf(`%v="%v": %v`, aname, bstr, err.Error()))
+				}
+				elem.outputs = append(elem.outputs, outputs...)
+			}
+		}
+
+		if !selfClosing && !voidElements[name] {
+			stack = append(stack, elem)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,100 @@
+package bind
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testCheckModel struct {
+	Name    string
+	Age     int
+	Entries []*testItem
+	Profile *testProfile
+}
+
+func (m *testCheckModel) Greet(greeting string) string {
+	return greeting + m.Name
+}
+
+func TestCheck(t *testing.T) {
+	b := NewBindEngine(nil)
+	valid := `
+<div>
+	<input type="text" bind-value="Name">
+	<span bind-text="toUpper(Name)"></span>
+	<p bind-html="Greet(Name)"></p>
+	<span bind-text="Profile?.Address?.City"></span>
+	<span bind-text="Entries.length"></span>
+	<span bind-class="{active: Age > 1, adult: Age >= 18 && Name != Profile?.Address?.City}"></span>
+	<ul>
+		<li bind-each="Entries -> i, entry"><span bind-text="entry.Name"></span></li>
+		<li bind-list="Entries"><span bind-text="$item.Name"></span></li>
+		<li bind-each="filter(Entries, Name != $item.Name && Age > 1) -> _, entry"></li>
+	</ul>
+	<errorlist bind="Errors: Name"><span bind-text="Errors"></span></errorlist>
+	<div bind-is="Name" bind="Errors: Name"><span bind-text="Errors"></span></div>
+</div>`
+	if errs := b.Check(valid, &testCheckModel{}); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v.", errs)
+	}
+
+	invalid := `
+<div>
+	<span bind-text="Nmae"></span>
+	<span bind-text="concat(Name)"></span>
+	<span bind-html="toUpper(Age)"></span>
+	<span bind-text="Greet(Name, Name)"></span>
+	<span bind-foo="Name"></span>
+	<span bind-text="entry.Name"></span>
+	<span bind-text="len(filter(Entries, Nmae))"></span>
+</div>`
+	errs := b.Check(invalid, &testCheckModel{})
+	expected := []string{
+		`bind-text="Nmae"`,
+		`bind-text="concat(Name)"`,
+		`bind-html="toUpper(Age)"`,
+		`bind-text="Greet(Name, Name)"`,
+		`bind-foo="Name"`,
+		`bind-text="entry.Name"`,
+		`bind-text="len(filter(Entries, Nmae))"`,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %v errors, got %v: %v.", len(expected), len(errs), errs)
+	}
+	for i, err := range errs {
+		if !strings.HasPrefix(err.Error(), expected[i]) {
+			t.Errorf("Expected an error for %v, got %v.", expected[i], err)
+		}
+	}
+}
+
+type testUnusedModel struct {
+	Title   string
+	Draft   string
+	Count   int
+	Visible bool
+}
+
+func (m *testUnusedModel) Reset()               {}
+func (m *testUnusedModel) CountGet() int        { return m.Count }
+func (m *testUnusedModel) CountSet(count int)   {}
+func (m *testUnusedModel) Summary(n int) string { return "" }
+
+func TestUnusedSymbols(t *testing.T) {
+	b := NewBindEngine(nil)
+	b.RegisterHelper("shout", strings.ToUpper)
+	b.RegisterHelper("whisper", strings.ToLower)
+
+	tmpl := `
+<div bind-if="Visible">
+	<span bind-text="shout(Title)"></span>
+	<button bind-on-click="Reset()"></button>
+	<p><% Count %></p>
+</div>`
+	unused := b.UnusedSymbols(&testUnusedModel{}, tmpl)
+	expected := []string{"Draft", "Summary", "whisper"}
+	if !reflect.DeepEqual(unused, expected) {
+		t.Errorf("Expected the unused symbols %v, got %v.", expected, unused)
+	}
+}
@@ -0,0 +1,63 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ClassBinder is a 1-way binder that adds or removes a class of the element
+// according to a boolean value.
+// It takes the class name as extra dash args. Without a class name, the value
+// must be a map from class names to boolean values, like a map literal.
+//
+// Usage:
+//	bind-class-className="BooleanExpression"
+// Or
+//	bind-class="{className: BooleanExpression, `class-name`: BooleanExpression}"
+type ClassBinder struct{ BaseBinder }
+
+func (b *ClassBinder) Update(d DomBind) {
+	var classes map[string]bool
+	if len(d.Args) == 0 {
+		var err error
+		classes, err = classMap(d.Value)
+		if err != nil {
+			d.Panic(err.Error() + ` Usage: bind-class-className="BooleanExpression" or bind-class="{className: BooleanExpression}".`)
+		}
+	} else {
+		on, ok := d.Value.(bool)
+		if !ok {
+			d.Panic(fmt.Sprintf("Wrong type %v for the class binder, must be a bool.", reflect.TypeOf(d.Value)))
+		}
+		classes = map[string]bool{strings.Join(d.Args, "-"): on}
+	}
+
+	for class, on := range classes {
+		if on {
+			d.Elem.AddClass(class)
+		} else {
+			d.Elem.RemoveClass(class)
+		}
+	}
+}
+
+// classMap converts the value of a class binder without class name to
+// the map of class names to their states
+func classMap(value interface{}) (classes map[string]bool, err error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("Wrong type %v for the class binder without class name, must be a map of class names.", reflect.TypeOf(value))
+	}
+
+	classes = make(map[string]bool)
+	for _, key := range v.MapKeys() {
+		on, ok := v.MapIndex(key).Interface().(bool)
+		if !ok {
+			return nil, fmt.Errorf(`Wrong value %v for the class "%v", must be a bool.`, v.MapIndex(key).Interface(), key.String())
+		}
+		classes[key.String()] = on
+	}
+	return
+}
+func (b *ClassBinder) BindInstance() DomBinder { return b }
@@ -0,0 +1,28 @@
+package bind
+
+import "testing"
+
+type testTask struct {
+	Done, Urgent bool
+}
+
+func TestClassBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	model := &testTask{Done: true}
+	elem := gJQ(`<div><p class="item" bind-class-done="Done"></p><span bind-class="{done: Done, urgent: Urgent}"></span></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	p, span := elem.Find("p"), elem.Find("span")
+	if !p.HasClass("done") || !p.HasClass("item") || !span.HasClass("done") || span.HasClass("urgent") {
+		t.Fatalf("Expected the classes to be set, got %v.", elem.Html())
+	}
+
+	model.Done, model.Urgent = false, true
+	w.change()
+	if p.HasClass("done") || !p.HasClass("item") || span.HasClass("done") || !span.HasClass("urgent") {
+		t.Errorf("Expected the classes to be updated, got %v.", elem.Html())
+	}
+}
@@ -0,0 +1,62 @@
+package bind
+
+import (
+	"errors"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Clipboard writes text to the system clipboard, it's used by the copy helper
+type Clipboard interface {
+	WriteText(text string) error
+}
+
+// browserClipboard is the default Clipboard. It uses the asynchronous Clipboard API
+// if it's available, whose failure cannot be reported, otherwise the "copy" command
+// on a temporary textarea holding the text, for the older browsers.
+type browserClipboard struct{}
+
+func (c browserClipboard) WriteText(text string) error {
+	if cb := js.Global.Get("navigator").Get("clipboard"); !cb.IsUndefined() && !cb.IsNull() {
+		cb.Call("writeText", text)
+		return nil
+	}
+
+	ta := gJQ("<textarea readonly></textarea>").SetVal(text)
+	ta.SetCss("position", "fixed").SetCss("opacity", "0")
+	gJQ("body").Append(ta)
+	defer ta.Remove()
+
+	ta.Get(0).Call("select")
+	if !js.Global.Get("document").Call("execCommand", "copy").Bool() {
+		return errors.New("The browser refused to copy to the clipboard.")
+	}
+	return nil
+}
+
+// SetClipboard sets the clipboard used by the copy helper
+func (b *Binding) SetClipboard(c Clipboard) {
+	b.clipboard = c
+}
+
+// CopyText writes the text to the clipboard
+func (b *Binding) CopyText(text string) error {
+	return b.clipboard.WriteText(text)
+}
+
+// engineHelpers are the default helpers that depend on the Binding. The copy helper
+// writes the text to the clipboard, it returns whether it succeeded.
+//
+// Usage:
+//	bind-on-click="copy(Token)"
+func (b *Binding) engineHelpers() map[string]interface{} {
+	return map[string]interface{}{
+		"copy": func(text string) bool {
+			if err := b.CopyText(text); err != nil {
+				println(err.Error())
+				return false
+			}
+			return true
+		},
+	}
+}
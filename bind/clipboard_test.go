@@ -0,0 +1,49 @@
+package bind
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeClipboard struct {
+	text   string
+	denied bool
+}
+
+func (c *fakeClipboard) WriteText(text string) error {
+	if c.denied {
+		return errors.New("denied")
+	}
+	c.text = text
+	return nil
+}
+
+type testApiKey struct {
+	Token string
+}
+
+func TestCopyHelper(t *testing.T) {
+	b := NewBindEngine(nil)
+	clipboard := &fakeClipboard{}
+	b.SetClipboard(clipboard)
+	model := &testApiKey{Token: "s3cr3t"}
+
+	if v, err := b.Eval(model, "copy(Token)"); err != nil || v != true {
+		t.Errorf("Expected the copy to succeed, got %v (%v).", v, err)
+	}
+	if clipboard.text != "s3cr3t" {
+		t.Errorf("Expected the token to be written to the clipboard, got %q.", clipboard.text)
+	}
+
+	clipboard.denied = true
+	if v, err := b.Eval(model, "copy(Token)"); err != nil || v != false {
+		t.Errorf("Expected the failure to be returned, got %v (%v).", v, err)
+	}
+	if err := b.CopyText("x"); err == nil {
+		t.Errorf("Expected the error of the clipboard.")
+	}
+
+	if unused := b.UnusedSymbols(model, `<span bind-text="Token"></span>`); len(unused) != 0 {
+		t.Errorf("Expected the copy helper not to be reported as unused, got %v.", unused)
+	}
+}
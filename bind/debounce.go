@@ -0,0 +1,69 @@
+package bind
+
+import (
+	"strconv"
+	"time"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+const (
+	// DebounceAttr delays the reevaluation of the binds of an element after a change
+	// of the model, until no change has happened for the delay. A burst of changes then
+	// causes a single reevaluation, which is useful for expensive expressions like
+	// filtered or sorted views that depend on a rapidly changing field.
+	// Its value is the delay in milliseconds, DefaultDebounceDelay is used if it's empty.
+	//
+	// Usage:
+	//	<li wade-debounce="300" bind-each="filter(Results, Score >= MinScore) -> _, result"></li>
+	DebounceAttr = "wade-debounce"
+
+	DefaultDebounceDelay = 200 * time.Millisecond
+)
+
+// debounceDelay returns the debounce delay of the element, and whether
+// debouncing is enabled for it
+func debounceDelay(elem jq.JQuery) (time.Duration, bool) {
+	if !elem.Is("[" + DebounceAttr + "]") {
+		return 0, false
+	}
+
+	ms, err := strconv.Atoi(elem.Attr(DebounceAttr))
+	if err != nil || ms <= 0 {
+		return DefaultDebounceDelay, true
+	}
+
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// debouncer calls fn once the delay has passed since the last trigger
+type debouncer struct {
+	browser browser
+	delay   time.Duration
+	fn      func()
+	stop    func()
+}
+
+func newDebouncer(br browser, delay time.Duration, fn func()) *debouncer {
+	return &debouncer{
+		browser: br,
+		delay:   delay,
+		fn:      fn,
+	}
+}
+
+func (d *debouncer) trigger() {
+	d.cancel()
+	d.stop = d.browser.after(d.delay, func() {
+		d.stop = nil
+		d.fn()
+	})
+}
+
+// cancel drops the pending call
+func (d *debouncer) cancel() {
+	if d.stop != nil {
+		d.stop()
+		d.stop = nil
+	}
+}
@@ -0,0 +1,173 @@
+package bind
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+// fakeBrowser is a browser whose time only passes when advance is called,
+// the elements matching the animated selector have a css transition.
+// The delayed calls may be added from other goroutines, like the readers of channels.
+type fakeBrowser struct {
+	time     time.Time
+	timers   []*fakeTimer
+	animated string
+
+	mu    sync.Mutex
+	added *sync.Cond
+}
+
+type fakeTimer struct {
+	at      time.Time
+	fn      func()
+	stopped bool
+}
+
+func newFakeBrowser() *fakeBrowser {
+	f := &fakeBrowser{time: time.Unix(0, 0)}
+	f.added = sync.NewCond(&f.mu)
+	return f
+}
+
+func (f *fakeBrowser) now() time.Time {
+	return f.time
+}
+
+func (f *fakeBrowser) after(d time.Duration, fn func()) func() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	timer := &fakeTimer{at: f.time.Add(d), fn: fn}
+	f.timers = append(f.timers, timer)
+	f.added.Broadcast()
+	return func() {
+		f.mu.Lock()
+		timer.stopped = true
+		f.mu.Unlock()
+	}
+}
+
+func (f *fakeBrowser) transitioning(elem jq.JQuery) bool {
+	return f.animated != "" && elem.Is(f.animated)
+}
+
+// advance lets the time pass, the calls whose delay has passed are made in order
+func (f *fakeBrowser) advance(d time.Duration) {
+	f.mu.Lock()
+	f.time = f.time.Add(d)
+	f.mu.Unlock()
+	for {
+		f.mu.Lock()
+		var due *fakeTimer
+		for i, timer := range f.timers {
+			if !timer.at.After(f.time) {
+				due = timer
+				f.timers = append(f.timers[:i], f.timers[i+1:]...)
+				break
+			}
+		}
+		stopped := due != nil && due.stopped
+		f.mu.Unlock()
+
+		if due == nil {
+			return
+		}
+		if !stopped {
+			due.fn()
+		}
+	}
+}
+
+// pending returns the number of calls waiting for their delay
+func (f *fakeBrowser) pending() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.countPending()
+}
+
+func (f *fakeBrowser) countPending() (n int) {
+	for _, timer := range f.timers {
+		if !timer.stopped {
+			n++
+		}
+	}
+	return
+}
+
+// wait blocks until n calls are waiting for their delay, they may be added by other goroutines
+func (f *fakeBrowser) wait(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for f.countPending() < n {
+		f.added.Wait()
+	}
+}
+
+func TestDebouncer(t *testing.T) {
+	br := newFakeBrowser()
+	recomputed := 0
+	d := newDebouncer(br, 300*time.Millisecond, func() { recomputed++ })
+
+	for i := 0; i < 5; i++ {
+		d.trigger()
+		br.advance(100 * time.Millisecond)
+	}
+	if recomputed != 0 || br.pending() != 1 {
+		t.Fatalf("Expected the recompute to be delayed and the previous calls dropped, got %v recomputes, %v pending.",
+			recomputed, br.pending())
+	}
+	br.advance(200 * time.Millisecond)
+	if recomputed != 1 {
+		t.Errorf("Expected a single recompute after the burst, got %v.", recomputed)
+	}
+
+	d.trigger()
+	d.cancel()
+	if br.pending() != 0 || d.stop != nil {
+		t.Errorf("Expected the pending recompute to be cancelled.")
+	}
+	d.cancel()
+	br.advance(time.Second)
+	if recomputed != 1 {
+		t.Errorf("Expected cancelling twice to be harmless, got %v recomputes.", recomputed)
+	}
+}
+
+func TestDebounceAttr(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	br := newFakeBrowser()
+	b.browser = br
+	model := &testBadge{Label: "a"}
+	elem := gJQ(`<div><p wade-debounce="300" bind-text="Label"></p><em wade-debounce bind-text="Label"></em></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	for _, label := range []string{"b", "c", "d"} {
+		model.Label = label
+		w.change()
+	}
+	if elem.Find("p").Text() != "a" || elem.Find("em").Text() != "a" {
+		t.Fatalf("Expected the updates to be delayed, got %v.", elem.Html())
+	}
+
+	br.advance(DefaultDebounceDelay)
+	if elem.Find("p").Text() != "a" || elem.Find("em").Text() != "d" {
+		t.Errorf("Expected the default delay to be used without a value, got %v.", elem.Html())
+	}
+	br.advance(300*time.Millisecond - DefaultDebounceDelay)
+	if elem.Find("p").Text() != "d" {
+		t.Errorf("Expected a single update with the last value after the delay, got %v.", elem.Html())
+	}
+
+	model.Label = "e"
+	w.change()
+	b.Teardown(elem)
+	br.advance(time.Second)
+	if elem.Find("p").Text() != "d" {
+		t.Errorf("Expected the pending update to be cancelled by the teardown, got %v.", elem.Html())
+	}
+}
@@ -0,0 +1,153 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+// DirtySymbol is true in the scope of a model bound with BindTracked, when the model
+// has changed since it was bound or since its DirtyTracker was last marked clean.
+// The bind strings using it are reevaluated when a field of the model changes.
+//
+// Usage:
+//	<button bind-attr-disabled="!$dirty">Save</button>
+const DirtySymbol = "$dirty"
+
+// DirtyTracker tells whether a model has changed since a snapshot of it was taken
+type DirtyTracker struct {
+	model   reflect.Value
+	initial interface{}
+}
+
+// TrackDirty takes a snapshot of the model, which must be a pointer to a struct
+func TrackDirty(model interface{}) *DirtyTracker {
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		panic("The model to track must be a pointer to a struct.")
+	}
+
+	t := &DirtyTracker{model: v}
+	t.MarkClean()
+	return t
+}
+
+// Dirty compares the model with the snapshot. The changes made in place to the slices,
+// maps and nested structs of the model are detected, not the ones to the values
+// that it points to.
+func (t *DirtyTracker) Dirty() bool {
+	return !reflect.DeepEqual(t.initial, t.model.Elem().Interface())
+}
+
+// MarkClean takes a new snapshot of the model, after it's saved for example,
+// the model is then not dirty until it changes again
+func (t *DirtyTracker) MarkClean() {
+	t.initial = copyStruct(t.model.Elem()).Interface()
+}
+
+// Revert restores the model to the snapshot, the fields are set one by one so that
+// the changes are observed. The snapshot is kept, a copy of it is restored.
+func (t *DirtyTracker) Revert() {
+	setChangedFields(t.model.Elem(), copyStruct(reflect.ValueOf(t.initial)))
+}
+
+// setChangedFields sets the exported fields of the struct dst that differ from
+// those of src, one by one so that the changes are observed
+func setChangedFields(dst, src reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		if dst.Type().Field(i).PkgPath != "" {
+			continue
+		}
+		if f := dst.Field(i); !reflect.DeepEqual(f.Interface(), src.Field(i).Interface()) {
+			f.Set(src.Field(i))
+		}
+	}
+}
+
+// copyStruct copies the struct, with copies of the slices, arrays and maps of its
+// exported fields, like copyValue
+func copyStruct(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).PkgPath != "" {
+			continue
+		}
+		if f := v.Field(i); f.Kind() == reflect.Struct {
+			c.Field(i).Set(copyStruct(f))
+		} else {
+			c.Field(i).Set(copyValue(f))
+		}
+	}
+	return c
+}
+
+// dirtySymbolTable provides DirtySymbol in the scope of a tracked model
+type dirtySymbolTable struct {
+	tracker *DirtyTracker
+}
+
+func (st dirtySymbolTable) lookup(symbol string) (scopeSymbol, bool) {
+	if symbol != DirtySymbol {
+		return nil, false
+	}
+	return dirtySymbol{st.tracker}, true
+}
+
+type dirtySymbol struct {
+	tracker *DirtyTracker
+}
+
+func (ds dirtySymbol) value() (reflect.Value, error) {
+	return reflect.ValueOf(ds.tracker.Dirty()), nil
+}
+
+func (ds dirtySymbol) call([]reflect.Value) (reflect.Value, error) {
+	return reflect.Value{}, fmt.Errorf(`Cannot call "%v", it's not a function.`, DirtySymbol)
+}
+
+// dependencies returns the fields of the model, whose changes change the value
+func (ds dirtySymbol) dependencies() []bindable {
+	deps := make([]bindable, 0)
+	st := ds.tracker.model.Elem().Type()
+	for i := 0; i < st.NumField(); i++ {
+		if f := st.Field(i); f.PkgPath == "" {
+			if eval, ok := evaluateObjField(f.Name, ds.tracker.model); ok {
+				deps = append(deps, modelFieldSymbol{f.Name, eval})
+			}
+		}
+	}
+	return deps
+}
+
+// dependentSymbol is a symbol whose value depends on model fields that are
+// not referred to in the bind string, like DirtySymbol
+type dependentSymbol interface {
+	dependencies() []bindable
+}
+
+// BindTracked is like Bind, but DirtySymbol is available in the bind strings, telling
+// whether the model has changed. It returns the tracker of the model, whose MarkClean
+// clears the dirtiness. The model can be restored with Binding.Reset.
+// The model must be a pointer to a struct.
+func (b *Binding) BindTracked(relem jq.JQuery, model interface{}, once bool, bindrelem bool) *DirtyTracker {
+	tracker := TrackDirty(model)
+	s := newModelScope(model)
+	s.addTables(dirtySymbolTable{tracker})
+	s.merge(b.scope)
+	b.bindWithScope(relem, once, bindrelem, s)
+	b.registry.entry(b.registry.elemId(relem)).tracker = tracker
+	return tracker
+}
+
+// Reset restores the model bound to the element with BindTracked to its state at bind
+// time, or when its tracker was last marked clean, like for the cancel button of a form.
+// The elements are updated like for any change of the model.
+func (b *Binding) Reset(relem jq.JQuery) {
+	entry, ok := b.registry.entries[relem.Attr(elemIdAttr)]
+	if !ok || entry.tracker == nil {
+		panic("Cannot reset, the element has not been bound with BindTracked.")
+	}
+	entry.tracker.Revert()
+}
@@ -0,0 +1,78 @@
+package bind
+
+import (
+	"testing"
+)
+
+type testProfileForm struct {
+	Name   string
+	Emails []string
+}
+
+func TestDirtyTracker(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testProfileForm{Name: "Ann", Emails: []string{"ann@example.com"}}
+	tracker := TrackDirty(model)
+	s := newModelScope(model)
+	s.addTables(dirtySymbolTable{tracker})
+	s.merge(b.scope)
+	bs := &bindScope{s}
+
+	_, binds, v, err := bs.evaluate("!$dirty")
+	if err != nil || v != true {
+		t.Fatalf("Expected the model not to be dirty, got %v (%v).", v, err)
+	}
+	if len(binds) != 2 || binds[0].bindObj().field != "Name" || binds[1].bindObj().field != "Emails" {
+		t.Errorf("Expected the fields of the model to be watched, got %v binds.", len(binds))
+	}
+
+	model.Name = "Anne"
+	if _, _, v, _ := bs.evaluate("$dirty"); v != true {
+		t.Errorf("Expected the model to be dirty after a field changed.")
+	}
+	model.Name = "Ann"
+	if tracker.Dirty() {
+		t.Errorf("Expected the model not to be dirty once the change is undone.")
+	}
+
+	model.Emails[0] = "ann@example.org"
+	if !tracker.Dirty() {
+		t.Errorf("Expected an in-place change of a slice to make the model dirty.")
+	}
+	tracker.MarkClean()
+	if _, _, v, _ := bs.evaluate("$dirty"); v != false {
+		t.Errorf("Expected the dirtiness to be cleared by MarkClean.")
+	}
+
+	if errs := b.Check(`<button bind-attr-disabled="!$dirty"></button>`, model); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v.", errs)
+	}
+}
+
+func TestDirtyRevert(t *testing.T) {
+	model := &testProfileForm{Name: "Ann", Emails: []string{"ann@example.com"}}
+	tracker := TrackDirty(model)
+
+	model.Name = "Bob"
+	model.Emails[0] = "bob@example.com"
+	model.Emails = append(model.Emails, "bob@example.org")
+	tracker.Revert()
+	if model.Name != "Ann" || len(model.Emails) != 1 || model.Emails[0] != "ann@example.com" || tracker.Dirty() {
+		t.Fatalf("Expected the original values to be restored, got %+v.", model)
+	}
+
+	// the restored slice is a copy, changing it doesn't change the snapshot
+	model.Emails[0] = "ann@example.org"
+	tracker.Revert()
+	if model.Emails[0] != "ann@example.com" {
+		t.Errorf("Expected the snapshot not to be aliased by the model, got %v.", model.Emails)
+	}
+
+	model.Name = "Anne"
+	tracker.MarkClean()
+	model.Name = "Annie"
+	tracker.Revert()
+	if model.Name != "Anne" {
+		t.Errorf("Expected the model to be restored to the last snapshot, got %v.", model.Name)
+	}
+}
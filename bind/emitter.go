@@ -0,0 +1,71 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var emitterType = reflect.TypeOf(Emitter{})
+
+// Emitter is an event that a custom tag's model exposes to its parent.
+// The parent binds a handler to it with attribute binding, and the custom tag
+// calls Emit to run the handler:
+//
+//	type TodoEntry struct {
+//		Id        int
+//		OnDestroy bind.Emitter
+//	}
+//
+//	<todoentry bind="Id: entry.Id; OnDestroy: RemoveEntry"></todoentry>
+//
+// The handler is a func() or a function that takes the payload, e.g. func(id int).
+type Emitter struct {
+	handler reflect.Value
+}
+
+func (e *Emitter) setHandler(handler reflect.Value) error {
+	if !handler.IsValid() {
+		return fmt.Errorf(`The handler of an event must be a function, not a nil`)
+	}
+	if handler.Kind() != reflect.Func {
+		return fmt.Errorf(`The handler of an event must be a function, not a "%v"`, handler.Type())
+	}
+	if handler.Type().NumIn() > 1 {
+		return fmt.Errorf(`The handler of an event must take at most 1 argument (the payload)`)
+	}
+
+	e.handler = handler
+	return nil
+}
+
+// Bound checks whether the parent has bound a handler to the event
+func (e *Emitter) Bound() bool {
+	return e.handler.IsValid() && !e.handler.IsNil()
+}
+
+// Emit runs the handler bound by the parent with the given payload,
+// it does nothing if there's no handler.
+func (e *Emitter) Emit(payload interface{}) {
+	if !e.Bound() {
+		return
+	}
+
+	htype := e.handler.Type()
+	if htype.NumIn() == 0 {
+		e.handler.Call([]reflect.Value{})
+		return
+	}
+
+	p := reflect.ValueOf(payload)
+	if !p.IsValid() {
+		p = reflect.Zero(htype.In(0))
+	}
+	if !p.Type().AssignableTo(htype.In(0)) {
+		if !p.Type().ConvertibleTo(htype.In(0)) {
+			panic(fmt.Sprintf(`Cannot emit a payload of type "%v" to a handler accepting "%v".`, p.Type(), htype.In(0)))
+		}
+		p = p.Convert(htype.In(0))
+	}
+
+	e.handler.Call([]reflect.Value{p})
+}
@@ -0,0 +1,61 @@
+package bind
+
+import (
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+type testEntry struct {
+	Id        int
+	OnDestroy Emitter
+	OnChange  Emitter
+}
+
+type testEntryList struct {
+	Removed []int
+	Changes int
+}
+
+func (m *testEntryList) RemoveEntry(id int) {
+	m.Removed = append(m.Removed, id)
+}
+
+func (m *testEntryList) Changed() {
+	m.Changes++
+}
+
+func TestEmitter(t *testing.T) {
+	b := NewBindEngine(nil)
+	parent := &testEntryList{}
+	s := newModelScope(parent)
+	s.merge(b.scope)
+
+	child := &testEntry{}
+	if child.OnDestroy.Bound() {
+		t.Fatalf("Expected the event not to be bound yet.")
+	}
+	child.OnDestroy.Emit(1) // no handler, nothing happens
+
+	b.processAttrBind("bind", "Id: 7; OnDestroy: RemoveEntry; OnChange: Changed", jq.JQuery{}, &bindScope{s}, true, child)
+	if child.Id != 7 || !child.OnDestroy.Bound() {
+		t.Fatalf("Expected the attributes to be bound.")
+	}
+
+	child.OnDestroy.Emit(child.Id)
+	child.OnChange.Emit(nil)
+	child.OnChange.Emit("ignored")
+	if len(parent.Removed) != 1 || parent.Removed[0] != 7 {
+		t.Errorf("Expected the parent's handler to receive the payload, got %v.", parent.Removed)
+	}
+	if parent.Changes != 2 {
+		t.Errorf("Expected the handler without argument to be called twice, got %v.", parent.Changes)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected a panic for a handler that's not a function.")
+		}
+	}()
+	b.processAttrBind("bind", "OnDestroy: Changes", jq.JQuery{}, &bindScope{s}, true, &testEntry{})
+}
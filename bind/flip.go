@@ -0,0 +1,99 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+const (
+	// KeyAttr is an expression evaluated against each item of bind-each-flip and
+	// bind-each-transition, giving the key that identifies the item across updates
+	//
+	// Usage:
+	//	<li bind-each-flip="Entries -> _, entry" wade-key="Id"><% entry.Title %></li>
+	KeyAttr = "wade-key"
+
+	// FlipDuration is the duration of the transitions of the moved items
+	FlipDuration = 300 * time.Millisecond
+)
+
+type flipPosition struct {
+	left, top int
+}
+
+// flipPositionOf returns the position of the first node of an item
+func flipPositionOf(nodes jq.JQuery) flipPosition {
+	o := nodes.First().Offset()
+	return flipPosition{o.Left, o.Top}
+}
+
+// flipAnimate moves the nodes by (dx, dy) then transitions them back to their place,
+// using css transforms and transitions
+func flipAnimate(nodes jq.JQuery, dx, dy int) {
+	elems := nodes.Filter("*")
+	elems.SetCss("transition", "none")
+	elems.SetCss("transform", fmt.Sprintf("translate(%vpx, %vpx)", dx, dy))
+	// forces a reflow, so that the transition starts from the old position
+	elems.Height()
+	elems.SetCss("transition", fmt.Sprintf("transform %vms", int64(FlipDuration/time.Millisecond)))
+	elems.SetCss("transform", "")
+}
+
+// flip performs FLIP (First, Last, Invert, Play) transitions of the items of a
+// repeating binder: the positions of the keyed items are recorded before the
+// update, then the items that moved are animated from the old positions.
+type flip struct {
+	before map[interface{}]flipPosition
+}
+
+// record saves the positions of the items before they're updated
+func (f *flip) record(keys []interface{}, items []jq.JQuery) {
+	f.before = make(map[interface{}]flipPosition)
+	for i, key := range keys {
+		f.before[key] = flipPositionOf(items[i])
+	}
+}
+
+// play animates the items that were there before the update and have moved
+func (f *flip) play(keys []interface{}, items []jq.JQuery) {
+	for i, key := range keys {
+		old, ok := f.before[key]
+		if !ok {
+			continue
+		}
+
+		pos := flipPositionOf(items[i])
+		if dx, dy := old.left-pos.left, old.top-pos.top; dx != 0 || dy != 0 {
+			flipAnimate(items[i], dx, dy)
+		}
+	}
+	f.before = nil
+}
+
+// itemKey returns the key of an item of bind-each-flip or bind-each-transition:
+// the value of the key expression evaluated against the item if there's one, otherwise
+// the item itself if it's comparable (like a pointer or a string) or the map key.
+func itemKey(keyExpr string, s *scope, val reflect.Value, i int, k interface{}, v reflect.Value) interface{} {
+	if keyExpr != "" {
+		ks := newModelScope(v.Interface())
+		ks.merge(s)
+		_, _, key, err := (&bindScope{ks}).evaluate(keyExpr)
+		if err != nil {
+			panic(fmt.Errorf(`Cannot evaluate the key "%v" of an item: %v`, keyExpr, err.Error()))
+		}
+		return key
+	}
+
+	if val.Kind() == reflect.Map {
+		return k
+	}
+
+	item := val.Index(i).Interface()
+	if item != nil && reflect.TypeOf(item).Comparable() {
+		return item
+	}
+	return k
+}
@@ -0,0 +1,86 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+type testKeyed struct {
+	Id    int
+	Title string
+}
+
+type testKeyedList struct {
+	Items []*testKeyed
+}
+
+func TestFlip(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	// the items are laid out by their index
+	b.RegisterHelper("rowTop", func(i int) string {
+		return fmt.Sprintf("top: %vpx", i*20)
+	})
+	a, c := &testKeyed{1, "a"}, &testKeyed{3, "c"}
+	model := &testKeyedList{[]*testKeyed{a, {2, "b"}, c}}
+	elem := gJQ(`<ul><li bind-each-flip="Items -> i, item" wade-key="Id" bind-attr-style="rowTop(i)"><span bind-text="item.Title"></span></li></ul>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	items := func() (titles string, moved []string) {
+		elem.Find("li").Each(func(i int, li jq.JQuery) {
+			titles += li.Text()
+			if li.Css("transition") != "" {
+				moved = append(moved, fmt.Sprintf("%v %v", li.Text(), li.Css("transition")))
+			}
+		})
+		return
+	}
+	if titles, moved := items(); titles != "abc" || len(moved) != 0 {
+		t.Errorf("Expected the items to be rendered without moving, got %v %v.", titles, moved)
+	}
+
+	// c moves to the top, d is new, b stays in place
+	model.Items = []*testKeyed{c, model.Items[1], a, {4, "d"}}
+	w.change()
+	titles, moved := items()
+	expected := []string{"c transform 300ms", "a transform 300ms"}
+	if titles != "cbad" || !reflect.DeepEqual(moved, expected) {
+		t.Errorf("Expected the moved items to transition, got %v %v.", titles, moved)
+	}
+	if tr := elem.Find("li").First().Css("transform"); tr != "" {
+		t.Errorf("Expected the moved item to transition back to its place, got %q.", tr)
+	}
+
+	// nothing moved
+	w.change()
+	if _, moved := items(); len(moved) != 0 {
+		t.Errorf("Expected no transition for the items that didn't move, got %v.", moved)
+	}
+}
+
+func TestItemKey(t *testing.T) {
+	b := NewBindEngine(nil)
+	t1, t2 := &testKeyed{1, "a"}, &testKeyed{2, "b"}
+	ptrs := reflect.ValueOf([]*testKeyed{t1, t2})
+	if key := itemKey("", b.scope, ptrs, 1, 1, ptrs.Index(1)); key != t2 {
+		t.Errorf("Expected the pointer to be the key, got %v.", key)
+	}
+	if key := itemKey("Id", b.scope, ptrs, 1, 1, ptrs.Index(1)); key != 2 {
+		t.Errorf("Expected the key expression to be evaluated, got %v.", key)
+	}
+
+	m := reflect.ValueOf(map[string][]int{"x": {1}})
+	if key := itemKey("", b.scope, m, 0, "x", m.MapIndex(reflect.ValueOf("x"))); key != "x" {
+		t.Errorf("Expected the map key to be the key, got %v.", key)
+	}
+
+	slices := reflect.ValueOf([][]int{{1}, {2}})
+	if key := itemKey("", b.scope, slices, 1, 1, slices.Index(1)); key != 1 {
+		t.Errorf("Expected the index for uncomparable items, got %v.", key)
+	}
+}
@@ -0,0 +1,185 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DefaultTimeLayout is the layout used to display time.Time values
+const DefaultTimeLayout = "2006-01-02 15:04:05"
+
+// TypeFormatter converts a value of a specific type to the string that's displayed
+type TypeFormatter func(interface{}) string
+
+func defaultTypeFormatters() map[reflect.Type]TypeFormatter {
+	return map[reflect.Type]TypeFormatter{
+		reflect.TypeOf(time.Duration(0)): func(v interface{}) string {
+			return v.(time.Duration).String()
+		},
+		reflect.TypeOf(time.Time{}): func(v interface{}) string {
+			return v.(time.Time).Format(DefaultTimeLayout)
+		},
+	}
+}
+
+// RegisterTypeFormatter registers a function that formats the values of the given type
+// for displaying by the text, html, value and attr binders. Values that are
+// already converted by a helper are not affected, since they have another type.
+func (b *Binding) RegisterTypeFormatter(t reflect.Type, fn func(interface{}) string) {
+	if fn == nil {
+		panic("Invalid type formatter, must not be nil.")
+	}
+
+	b.typeFormatters[t] = fn
+}
+
+// TypeParser converts the string received from an element, like the value of an input,
+// to a value of a specific type
+type TypeParser func(string) (interface{}, error)
+
+// RegisterConverter registers the conversion between the values of the given type and
+// strings, for the 2-way binding of custom value types. toStr is registered as the type
+// formatter of the type, fromStr converts the new values of the elements before they're
+// set to the model, instead of the conversion based on the kind of the type.
+//
+// Example:
+//	b.RegisterConverter(reflect.TypeOf(Money(0)), formatMoney, parseMoney)
+func (b *Binding) RegisterConverter(t reflect.Type, toStr func(interface{}) string, fromStr func(string) (interface{}, error)) {
+	if fromStr == nil {
+		panic("Invalid converter, must not be nil.")
+	}
+
+	b.RegisterTypeFormatter(t, toStr)
+	b.typeParsers[t] = fromStr
+}
+
+// parseValue converts a string received from an element to a value of the given type,
+// using the converter registered for the type if there's one
+func (b *Binding) parseValue(s string, typ reflect.Type) (v reflect.Value, err error) {
+	parse, ok := b.typeParsers[typ]
+	if !ok {
+		return convertString(s, typ)
+	}
+
+	value, err := parse(s)
+	if err != nil {
+		return
+	}
+
+	v = reflect.ValueOf(value)
+	if !v.IsValid() {
+		return reflect.Zero(typ), nil
+	}
+	if !v.Type().AssignableTo(typ) {
+		if !v.Type().ConvertibleTo(typ) {
+			err = fmt.Errorf(`The converter of type %v returned a value of type %v`, typ, v.Type())
+			return
+		}
+		v = v.Convert(typ)
+	}
+	return
+}
+
+// formatValue converts the value to a string for displaying,
+// using the type formatter registered for its type if there's one
+func (b *Binding) formatValue(value interface{}) string {
+	if value != nil {
+		if fn, ok := b.typeFormatters[reflect.TypeOf(value)]; ok {
+			return fn(value)
+		}
+	}
+
+	return toString(value)
+}
+
+// inputTimeLayouts are the layouts of the values of the date and time inputs, by input type
+var inputTimeLayouts = map[string]string{
+	"date":           "2006-01-02",
+	"datetime-local": "2006-01-02T15:04",
+	"month":          "2006-01",
+	"time":           "15:04",
+}
+
+// inputTimeValue formats a time.Time value for an input of the given type,
+// ok is false if the value is not a time.Time or the input is not a date or time input.
+// The zero time is displayed as an empty input.
+func inputTimeValue(typ string, value interface{}) (s string, ok bool) {
+	t, isTime := value.(time.Time)
+	layout, isTimeInput := inputTimeLayouts[strings.ToLower(typ)]
+	if !isTime || !isTimeInput {
+		return
+	}
+
+	if t.IsZero() {
+		return "", true
+	}
+	return t.Format(layout), true
+}
+
+// parseInputTime parses the value of a date or time input of the given type with the layout
+// of the input type, in the local time zone. The parts of the time that the input doesn't hold
+// are kept from the current value of the field: the time of day for a date input and the date
+// for a time input.
+func parseInputTime(typ, s string, current time.Time) (t time.Time, err error) {
+	typ = strings.ToLower(typ)
+	layout, ok := inputTimeLayouts[typ]
+	if !ok {
+		err = fmt.Errorf(`"%v" is not a date or time input type.`, typ)
+		return
+	}
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		err = fmt.Errorf(`Empty date.`)
+		return
+	}
+
+	t, err = time.ParseInLocation(layout, s, time.Local)
+	if err != nil && (typ == "time" || typ == "datetime-local") {
+		// the seconds are included when the step of the input is less than a minute
+		t, err = time.ParseInLocation(layout+":05", s, time.Local)
+	}
+	if err != nil {
+		err = fmt.Errorf(`Invalid %v "%v".`, typ, s)
+		return
+	}
+
+	c := current
+	switch {
+	case typ == "time":
+		t = time.Date(c.Year(), c.Month(), c.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), c.Location())
+	case typ == "date" && !c.IsZero():
+		t = time.Date(t.Year(), t.Month(), t.Day(), c.Hour(), c.Minute(), c.Second(), c.Nanosecond(), c.Location())
+	}
+	return
+}
+
+// parseTime parses a string written to a time.Time field: an RFC 3339 time, which the
+// value binder writes for the date and time inputs, or a date, month or date and time
+// like the values of those inputs, in the local time zone. A time of day alone
+// is not accepted, it has no date.
+func parseTime(s string) (t time.Time, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		err = fmt.Errorf(`Empty date.`)
+		return
+	}
+
+	layouts := []string{
+		time.RFC3339Nano,
+		inputTimeLayouts["datetime-local"],
+		inputTimeLayouts["datetime-local"] + ":05",
+		inputTimeLayouts["date"],
+		inputTimeLayouts["month"],
+	}
+	for _, layout := range layouts {
+		if t, err = time.ParseInLocation(layout, s, time.Local); err == nil {
+			return
+		}
+	}
+
+	err = fmt.Errorf(`Invalid date "%v".`, s)
+	return
+}
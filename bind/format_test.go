@@ -0,0 +1,186 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testCelsius float64
+
+type testRun struct {
+	Elapsed time.Duration
+	Started time.Time
+	Temp    testCelsius
+}
+
+func TestTypeFormatters(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testRun{
+		Elapsed: 90 * time.Second,
+		Started: time.Date(2014, 8, 1, 9, 30, 0, 0, time.UTC),
+		Temp:    21.5,
+	}
+	b.RegisterTypeFormatter(reflect.TypeOf(testCelsius(0)), func(v interface{}) string {
+		return fmt.Sprintf("%.1f°C", float64(v.(testCelsius)))
+	})
+
+	tests := map[string]string{
+		"Elapsed": "1m30s",
+		"Started": "2014-08-01 09:30:00",
+		"Temp":    "21.5°C",
+	}
+	for bstr, expected := range tests {
+		v, err := b.Eval(model, bstr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		d := DomBind{Value: v, binding: b}
+		if s := d.ValueString(); s != expected {
+			t.Errorf("%v: expected %q, got %q.", bstr, expected, s)
+		}
+	}
+
+	if s := b.formatValue(int64(model.Elapsed)); s != "90000000000" {
+		t.Errorf("Expected a value of another type not to be formatted, got %q.", s)
+	}
+}
+
+func TestInputTime(t *testing.T) {
+	date := time.Date(2014, time.July, 9, 0, 0, 0, 0, time.Local)
+	if s, ok := inputTimeValue("date", date); !ok || s != "2014-07-09" {
+		t.Errorf("Expected the date in the date input format, got %q.", s)
+	}
+	if s, ok := inputTimeValue("datetime-local", date.Add(90*time.Minute)); !ok || s != "2014-07-09T01:30" {
+		t.Errorf("Expected the datetime-local format, got %q.", s)
+	}
+	if s, ok := inputTimeValue("date", time.Time{}); !ok || s != "" {
+		t.Errorf("Expected the zero time to be empty, got %q.", s)
+	}
+	if _, ok := inputTimeValue("text", date); ok {
+		t.Errorf("Expected a text input not to be formatted.")
+	}
+
+	// what the value binder writes to the model
+	for input, expected := range map[string]time.Time{
+		"2014-07-09":       date,
+		"2014-07-09T01:30": date.Add(90 * time.Minute),
+		"2014-07":          date.AddDate(0, 0, -8),
+	} {
+		v, err := convertString(input, reflect.TypeOf(time.Time{}))
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", input, err)
+		} else if !v.Interface().(time.Time).Equal(expected) {
+			t.Errorf("%v: expected %v, got %v.", input, expected, v.Interface())
+		}
+
+		s, _ := inputTimeValue("date", v.Interface())
+		if s != expected.Format("2006-01-02") {
+			t.Errorf("Expected the date to round-trip, got %v.", s)
+		}
+	}
+
+	for _, input := range []string{"", "  ", "2014-13-45", "yesterday", "10:30"} {
+		if _, err := convertString(input, reflect.TypeOf(time.Time{})); err == nil {
+			t.Errorf("Expected an error for %q.", input)
+		}
+	}
+	if _, err := convertString("2014-07-09", reflect.TypeOf(struct{}{})); err == nil {
+		t.Errorf("Expected an error for a struct other than time.Time.")
+	}
+}
+
+func TestTimeInputWrite(t *testing.T) {
+	meeting := time.Date(2014, time.July, 9, 14, 30, 0, 0, time.Local)
+	b := &ValueBinder{value: meeting}
+
+	// what a time input writes back keeps the date of the field
+	tests := []struct {
+		typ, input string
+		expected   time.Time
+	}{
+		{"time", "09:15", time.Date(2014, time.July, 9, 9, 15, 0, 0, time.Local)},
+		{"time", "09:15:20", time.Date(2014, time.July, 9, 9, 15, 20, 0, time.Local)},
+		{"date", "2014-08-01", time.Date(2014, time.August, 1, 14, 30, 0, 0, time.Local)},
+		{"datetime-local", "2014-08-01T08:00", time.Date(2014, time.August, 1, 8, 0, 0, 0, time.Local)},
+		{"month", "2014-08", time.Date(2014, time.August, 1, 0, 0, 0, 0, time.Local)},
+	}
+	for _, test := range tests {
+		s, ok := b.inputTime(test.typ, test.input)
+		if !ok {
+			t.Errorf("%v %v: expected a time to be written.", test.typ, test.input)
+			continue
+		}
+		v, err := convertString(s, reflect.TypeOf(time.Time{}))
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", s, err)
+		} else if !v.Interface().(time.Time).Equal(test.expected) {
+			t.Errorf("%v %v: expected %v, got %v.", test.typ, test.input, test.expected, v.Interface())
+		}
+	}
+
+	// the layout of the input type is used
+	for _, test := range []struct{ typ, input string }{
+		{"time", "2014-08-01"},
+		{"date", "09:15"},
+		{"date", ""},
+		{"month", "2014-08-01"},
+	} {
+		if _, ok := b.inputTime(test.typ, test.input); ok {
+			t.Errorf("%v: expected %q to be rejected.", test.typ, test.input)
+		}
+	}
+
+	// other fields and inputs are written as is
+	if _, ok := (&ValueBinder{value: "09:15"}).inputTime("time", "09:15"); ok {
+		t.Errorf("Expected a string field not to be converted.")
+	}
+	if _, ok := b.inputTime("text", "09:15"); ok {
+		t.Errorf("Expected a text input not to be converted.")
+	}
+}
+
+type testMoney int64
+
+type testInvoice struct {
+	Total testMoney
+	Count int
+}
+
+func TestConverters(t *testing.T) {
+	b := NewBindEngine(nil)
+	b.RegisterConverter(reflect.TypeOf(testMoney(0)), func(v interface{}) string {
+		m := v.(testMoney)
+		return fmt.Sprintf("$%d.%02d", m/100, m%100)
+	}, func(s string) (interface{}, error) {
+		var dollars, cents int64
+		if _, err := fmt.Sscanf(s, "$%d.%02d", &dollars, &cents); err != nil {
+			return nil, fmt.Errorf("invalid amount %q", s)
+		}
+		return dollars*100 + cents, nil
+	})
+
+	model := &testInvoice{Total: 1250}
+	v, _ := b.Eval(model, "Total")
+	input := DomBind{Value: v, binding: b}.ValueString()
+	if input != "$12.50" {
+		t.Fatalf("Expected the converter to format the value, got %q.", input)
+	}
+
+	nv, err := b.parseValue("$7.05", reflect.TypeOf(model.Total))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reflect.ValueOf(model).Elem().FieldByName("Total").Set(nv)
+	if model.Total != 705 {
+		t.Errorf("Expected the input to be converted to the value, got %v.", model.Total)
+	}
+	if _, err := b.parseValue("7", reflect.TypeOf(model.Total)); err == nil {
+		t.Errorf("Expected the error of the converter.")
+	}
+
+	if nv, err := b.parseValue("3", reflect.TypeOf(model.Count)); err != nil || nv.Int() != 3 {
+		t.Errorf("Expected the conversion by kind for the other types, got %v (%v).", nv, err)
+	}
+}
@@ -0,0 +1,166 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+// TemplateSource is implemented by custom element managers that hold the templates,
+// it's used by BindFragment to find them. When the manager doesn't implement it,
+// the templates are looked up in the document.
+type TemplateSource interface {
+	Template(id string) (jq.JQuery, bool)
+}
+
+// template returns the element with the given id in the templates
+func (b *Binding) template(id string) (elem jq.JQuery, ok bool) {
+	if ts, isSource := b.tm.(TemplateSource); isSource {
+		return ts.Template(id)
+	}
+
+	elem = gJQ("#" + id)
+	return elem, elem.Length > 0
+}
+
+// newFragmentRoot returns a <div> holding a copy of the template's contents,
+// marked as the root of a fragment
+func newFragmentRoot(tmpl jq.JQuery) jq.JQuery {
+	return gJQ("<div></div>").SetAttr(fragmentMarkAttr, "t").Append(tmpl.Clone().Contents())
+}
+
+// BindFragment binds the model to a copy of the contents of the template with the given id,
+// without inserting it into the document. It returns the root of the fragment, a <div>
+// holding the contents, which can be inserted later, for example in a modal or a tooltip;
+// and a function that tears down the bindings of the fragment and removes it.
+func (b *Binding) BindFragment(templateId string, model interface{}) (jq.JQuery, func()) {
+	tmpl, ok := b.template(templateId)
+	if !ok {
+		panic(fmt.Sprintf(`Template "%v" for the fragment cannot be found.`, templateId))
+	}
+
+	root := newFragmentRoot(tmpl)
+	b.Bind(root, model, false, false)
+
+	torn := false
+	return root, func() {
+		if torn {
+			return
+		}
+		torn = true
+		// with the root, so that the bindings of the elements removed by
+		// their binders, like the prototypes of the each binder, are torn down too
+		b.teardown(root, true)
+		root.Remove()
+	}
+}
+
+// PooledFragment is a fragment of a pool created by BindPool, it's bound once
+// and reused for different items
+type PooledFragment struct {
+	// Root is the root of the fragment, a <div> holding a copy of the template's contents
+	Root jq.JQuery
+
+	binding *Binding
+	model   reflect.Value
+	item    interface{}
+	shown   reflect.Value
+}
+
+// BindPool creates a pool of fragments from the template with the given id, for
+// virtualized lists for example: the fragments are reused for the items shown as the
+// user scrolls, instead of binding and tearing down a fragment for each item.
+// The prototype is a pointer to a struct of the type of the items, each fragment is
+// bound to its own copy of it, whose fields are then set to those of the item shown,
+// see PooledFragment.Rebind. The fragments are not inserted into the document.
+// Usage:
+//	pool := binding.BindPool("t-row", 20, &Row{})
+//	for i, f := range pool {
+//		f.Rebind(rows[first+i])
+//	}
+func (b *Binding) BindPool(templateId string, size int, prototype interface{}) []*PooledFragment {
+	tmpl, ok := b.template(templateId)
+	if !ok {
+		panic(fmt.Sprintf(`Template "%v" for the fragment pool cannot be found.`, templateId))
+	}
+	pv := reflect.ValueOf(prototype)
+	if !isStructPtr(pv) {
+		panic(fmt.Sprintf("Wrong prototype %v for the fragment pool, must be a non-nil pointer to a struct.", prototype))
+	}
+
+	pool := make([]*PooledFragment, size)
+	for i := range pool {
+		model := reflect.New(pv.Elem().Type())
+		model.Elem().Set(copyStruct(pv.Elem()))
+		f := &PooledFragment{
+			Root:    newFragmentRoot(tmpl),
+			binding: b,
+			model:   model,
+		}
+		b.Bind(f.Root, model.Interface(), false, false)
+		pool[i] = f
+	}
+	return pool
+}
+
+func isStructPtr(v reflect.Value) bool {
+	return v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct
+}
+
+// Rebind shows the item in the fragment, the item must be a pointer to a struct
+// of the type of the pool's prototype. The fields of the fragment's model are set
+// to those of the item, so the bindings update in place. The changes made to the
+// model by the 2-way bindings for the previous item are saved to it first, see Save.
+func (f *PooledFragment) Rebind(item interface{}) {
+	v := reflect.ValueOf(item)
+	if !isStructPtr(v) {
+		panic(fmt.Sprintf("Wrong item %v for the pooled fragment, must be a non-nil pointer to a struct.", item))
+	}
+	if v.Type() != f.model.Type() {
+		panic(fmt.Sprintf("Cannot rebind the pooled fragment of %v items to an item of type %v.", f.model.Type(), v.Type()))
+	}
+
+	f.Save()
+	f.item = item
+	f.shown = copyStruct(v.Elem())
+	setChangedFields(f.model.Elem(), v.Elem())
+}
+
+// Save sets the fields of the item shown that have been changed in the fragment's
+// model since the item was shown, by the 2-way bindings, to their new values
+func (f *PooledFragment) Save() {
+	if f.item == nil {
+		return
+	}
+
+	item := reflect.ValueOf(f.item).Elem()
+	model := f.model.Elem()
+	for i := 0; i < model.NumField(); i++ {
+		if model.Type().Field(i).PkgPath != "" {
+			continue
+		}
+		if edited := model.Field(i); !reflect.DeepEqual(edited.Interface(), f.shown.Field(i).Interface()) {
+			item.Field(i).Set(edited)
+			f.shown.Field(i).Set(edited)
+		}
+	}
+}
+
+// Item returns the item shown by the fragment, nil before the first Rebind
+func (f *PooledFragment) Item() interface{} {
+	return f.item
+}
+
+// Model returns the fragment's copy of its item that it's bound to
+func (f *PooledFragment) Model() interface{} {
+	return f.model.Interface()
+}
+
+// Teardown saves the changes to the item shown, then tears down
+// the bindings of the fragment and removes it
+func (f *PooledFragment) Teardown() {
+	f.Save()
+	f.binding.teardown(f.Root, true)
+	f.Root.Remove()
+}
@@ -0,0 +1,135 @@
+package bind
+
+import (
+	"fmt"
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+type testTemplates map[string]jq.JQuery
+
+func (ts testTemplates) GetCustomTag(jq.JQuery) (CustomTag, bool) {
+	return nil, false
+}
+
+func (ts testTemplates) Template(id string) (elem jq.JQuery, ok bool) {
+	elem, ok = ts[id]
+	return
+}
+
+func TestBindFragment(t *testing.T) {
+	tmpl := gJQ(`<div id="t-modal"><h3 bind-text="Label"></h3><ul><li bind-each="Tags -> _, tag"><span bind-text="tag"></span></li></ul></div>`)
+	b := NewBindEngine(testTemplates{"t-modal": tmpl})
+	w := newFakeWatcher()
+	b.fields = w
+	if elem, ok := b.template("t-modal"); !ok || elem.Attr("id") != "t-modal" {
+		t.Errorf("Expected the template to be found through the template source.")
+	}
+	if _, ok := b.template("t-none"); ok {
+		t.Errorf("Expected an unknown template not to be found.")
+	}
+
+	model := &testBadge{"new", []string{"a", "b"}}
+	root, teardown := b.BindFragment("t-modal", model)
+	if jqExists(root) {
+		t.Errorf("Expected the fragment not to be inserted into the document.")
+	}
+	if root.Find("h3").Text() != "new" || root.Find("span").Text() != "ab" {
+		t.Fatalf("Expected the fragment to be bound, got %v.", root.Html())
+	}
+	if tmpl.Find("h3").Text() != "" || tmpl.Find("li").Length != 1 {
+		t.Errorf("Expected the template to be left as it is, got %v.", tmpl.Html())
+	}
+
+	root.AppendTo(gJQ("body"))
+	model.Label = "seen"
+	w.change()
+	if !jqExists(root) || root.Find("h3").Text() != "seen" {
+		t.Errorf("Expected the inserted fragment to be updated, got %v.", root.Html())
+	}
+
+	teardown()
+	teardown()
+	if jqExists(root) || len(w.targets) != 0 {
+		t.Errorf("Expected the fragment to be removed and its watchers to be released, got %v watchers.", w.targets)
+	}
+	model.Label = "gone"
+	w.change()
+	if root.Find("h3").Text() != "seen" {
+		t.Errorf("Expected the torn down fragment not to be updated, got %v.", root.Html())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a missing template.")
+		}
+	}()
+	b.BindFragment("t-none", nil)
+}
+
+type testFeedRow struct {
+	Title string
+	Votes int
+}
+
+func TestBindPool(t *testing.T) {
+	b := NewBindEngine(testTemplates{"t-row": gJQ(`<div id="t-row"><b bind-text="Title"></b><input bind-model="Title"><i bind-text="Votes"></i></div>`)})
+	w := newFakeWatcher()
+	b.fields = w
+	pool := b.BindPool("t-row", 2, &testFeedRow{Votes: -1})
+	if len(pool) != 2 || pool[0].Item() != nil || pool[0].Root.Find("i").Text() != "-1" || jqExists(pool[0].Root) {
+		t.Fatalf("Expected 2 fragments bound to the prototype, got %v.", pool[0].Root.Html())
+	}
+	watchers := len(w.targets)
+
+	rows := []*testFeedRow{{"first", 3}, {"second", 5}, {"third", 1}}
+	f := pool[0]
+	root := f.Root
+	model := f.Model()
+	for _, row := range rows {
+		f.Rebind(row)
+		w.change()
+		if f.Item() != row || f.Root.Find("b").Text() != row.Title || f.Root.Find("input").Val() != row.Title ||
+			f.Root.Find("i").Text() != fmt.Sprint(row.Votes) {
+			t.Errorf("Expected the fragment to show %v, got %v.", row, f.Root.Html())
+		}
+	}
+	if f.Root.Get(0) != root.Get(0) || f.Model() != model || len(w.targets) != watchers {
+		t.Errorf("Expected the fragment and its bindings to be reused, got %v watchers instead of %v.", len(w.targets), watchers)
+	}
+	if model == rows[2] {
+		t.Errorf("Expected the fragment to be bound to its own copy of the item.")
+	}
+
+	// the changes made through the 2-way bindings are saved to the item
+	f.Root.Find("input").SetVal("edited").Trigger(jq.CHANGE)
+	rows[2].Votes = 7
+	f.Rebind(rows[0])
+	if rows[2].Title != "edited" || rows[2].Votes != 7 {
+		t.Errorf("Expected the edit to be saved to the item without overwriting its other fields, got %v.", rows[2])
+	}
+	f.Root.Find("input").SetVal("changed").Trigger(jq.CHANGE)
+	f.Teardown()
+	if rows[0].Title != "changed" || len(w.targets) != watchers/2 {
+		t.Errorf("Expected the fragment to be saved and torn down, got %v and %v watchers.", rows[0], len(w.targets))
+	}
+
+	for _, item := range []interface{}{nil, testFeedRow{}, (*testFeedRow)(nil), &testTodo{}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected a panic for the item %#v.", item)
+				}
+			}()
+			pool[1].Rebind(item)
+		}()
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a missing template.")
+		}
+	}()
+	b.BindPool("t-none", 1, &testFeedRow{})
+}
@@ -3,13 +3,28 @@ package bind
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/gopherjs/gopherjs/js"
 	jq "github.com/gopherjs/jquery"
 )
 
+const (
+	AccessorGetSuffix = "Get"
+	AccessorSetSuffix = "Set"
+)
+
+var (
+	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+	jqueryType         = reflect.TypeOf(jq.JQuery{})
+	jsObjectType       = reflect.TypeOf((*js.Object)(nil)).Elem()
+	timeType           = reflect.TypeOf(time.Time{})
+	errorType          = reflect.TypeOf((*error)(nil)).Elem()
+)
+
 func elemError(elem jq.JQuery, errstr string) {
 	msg := fmt.Sprintf(`Error while processing: "%v"`, elem.Clone().Wrap("<p>").Parent().Html())
 	if len(msg) >= 200 {
@@ -24,7 +39,7 @@ func jqExists(elem jq.JQuery) bool {
 }
 
 func isValidExprChar(c rune) bool {
-	return c == '`' || c == '.' || c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+	return c == '`' || c == '.' || c == '_' || c == '$' || unicode.IsLetter(c) || unicode.IsDigit(c)
 }
 
 func jsGetType(obj js.Object) string {
@@ -46,17 +61,127 @@ func callFunc(fn reflect.Value, args []reflect.Value) (v reflect.Value, err erro
 		return
 	}
 
+	args = convertArgs(ftype, args)
 	rets := fn.Call(args)
 	if len(rets) == 1 {
 		v = rets[0]
 		return
 	}
 
+	if returnsError(ftype) {
+		if !rets[1].IsNil() {
+			err = rets[1].Interface().(error)
+			return
+		}
+		v = rets[0]
+	}
+
+	return
+}
+
+// argConvertible checks whether an argument of the type can be passed for a parameter
+// of the other type, either directly or by converting it between a named type and
+// its underlying type, like a type State string and a string
+func argConvertible(from, to reflect.Type) bool {
+	if from.AssignableTo(to) {
+		return true
+	}
+
+	switch from.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Func, reflect.Struct:
+		return false
+	}
+	return from.Kind() == to.Kind() && from.ConvertibleTo(to)
+}
+
+// convertArgs converts the arguments of a named type to the types of the parameters
+func convertArgs(ftype reflect.Type, args []reflect.Value) []reflect.Value {
+	nin := ftype.NumIn()
+	converted := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		var param reflect.Type
+		if ftype.IsVariadic() && i >= nin-1 {
+			param = ftype.In(nin - 1).Elem()
+		} else {
+			param = ftype.In(i)
+		}
+
+		converted[i] = arg
+		if arg.IsValid() && !arg.Type().AssignableTo(param) && argConvertible(arg.Type(), param) {
+			converted[i] = arg.Convert(param)
+		}
+	}
+	return converted
+}
+
+// returnsError checks whether the function returns a value and an error,
+// the Go idiom (T, error)
+func returnsError(ftype reflect.Type) bool {
+	return ftype.NumOut() == 2 && ftype.Out(1) == errorType
+}
+
+// convertString converts a string received from the html element to a value
+// of the given type, so that it can be set to the model field
+func convertString(s string, typ reflect.Type) (v reflect.Value, err error) {
+	var value interface{}
+	switch typ.Kind() {
+	case reflect.String, reflect.Interface:
+		value = s
+	case reflect.Bool:
+		value, err = strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err = strconv.ParseInt(s, 10, typ.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err = strconv.ParseUint(s, 10, typ.Bits())
+	case reflect.Float32, reflect.Float64:
+		value, err = strconv.ParseFloat(s, typ.Bits())
+	case reflect.Slice:
+		return convertSlice(s, typ)
+	case reflect.Struct:
+		if typ != timeType {
+			err = fmt.Errorf(`Cannot convert the value "%v" to type "%v".`, s, typ.String())
+			break
+		}
+		value, err = parseTime(s)
+	default:
+		err = fmt.Errorf(`Cannot convert the value "%v" to type "%v".`, s, typ.String())
+	}
+
+	if err != nil {
+		return
+	}
+
+	rv := reflect.ValueOf(value)
+	if typ.Kind() == reflect.Interface {
+		if !rv.Type().AssignableTo(typ) {
+			err = fmt.Errorf(`Cannot convert the value "%v" to type "%v".`, s, typ.String())
+		}
+		v = rv
+		return
+	}
+
+	v = rv.Convert(typ)
 	return
 }
 
 // evaluateObj uses reflection to access a field (obj.field1.field2.field3) of the given model.
-// It returns an evaluation of the field, and a bool which indicates whether the field is found
+// It returns an evaluation of the field, and a bool which indicates whether the field is found.
+//
+// Slices, arrays, maps and strings have a "length" (or "len") pseudo-property
+// that is their number of elements (obj.field1.length).
+//
+// The evaluated field is settable when the model is a pointer, including through
+// nested structs and maps, writes to values held by maps are written back to them.
+// The values are taken from the maps again for each write, so that the changes
+// made to them since the evaluation are kept.
+//
+// An unexported field can be accessed through a pair of accessor methods
+// XxxGet() and XxxSet(v) declared by the model, obj.Xxx then evaluates to the
+// result of XxxGet() and writes to it go through XxxSet.
+//
+// A field followed by the safe navigation operator "?." (obj.field1?.field2) is allowed
+// to be nil, in that case the evaluation short-circuits to the zero value of the
+// final field's type instead of failing.
 func evaluateObjField(query string, model reflect.Value) (*objEval, bool) {
 	flist := strings.Split(query, ".")
 	vals := make([]reflect.Value, len(flist)+1)
@@ -67,27 +192,250 @@ func evaluateObjField(query string, model reflect.Value) (*objEval, bool) {
 	}
 	vals[0] = o
 
+	writeBacks := make([]func(), 0)
 	for i, field := range flist {
+		optional := strings.HasSuffix(field, "?")
+		field = strings.TrimSuffix(field, "?")
+		flist[i] = field
+
 		var found bool
+		var setter reflect.Value
+		parent := o
 		o, found = getReflectField(o, field)
+		if found {
+			if wb, ok := addressableMapValue(parent, field, &o, i == len(flist)-1); ok {
+				writeBacks = append(writeBacks, wb)
+			}
+		}
 		if !found {
+			o, setter, found = getAccessorField(parent, field)
+			if found && i == len(flist)-1 {
+				return &objEval{
+					fieldRefl: o,
+					modelRefl: vals[i],
+					field:     accessorFieldName(field),
+					setter:    setter,
+				}, true
+			}
+		}
+		if !found {
+			if i > 0 && i == len(flist)-1 && isLengthField(field) {
+				if n, ok := collectionLen(parent); ok {
+					// the collection is watched instead of the pseudo-property,
+					// so that the length is reevaluated when the collection changes
+					return &objEval{
+						fieldRefl: reflect.ValueOf(n),
+						modelRefl: vals[i-1],
+						root:      model,
+						field:     flist[i-1],
+						readOnly:  true,
+					}, true
+				}
+			}
 			return nil, false
 		}
 		vals[i+1] = o
+
+		if optional && i < len(flist)-1 && isNilValue(o) {
+			return &objEval{
+				fieldRefl: zeroOfPath(o.Type(), flist[i+1:]),
+				modelRefl: vals[i],
+				root:      model,
+				field:     field,
+			}, true
+		}
 	}
 
-	return &objEval{
-		fieldRefl: vals[len(vals)-1],
-		modelRefl: vals[len(vals)-2],
-		field:     flist[len(flist)-1],
+	// the copies are written back from the innermost one
+	for i, j := 0, len(writeBacks)-1; i < j; i, j = i+1, j-1 {
+		writeBacks[i], writeBacks[j] = writeBacks[j], writeBacks[i]
+	}
+
+	oe := &objEval{
+		fieldRefl:  vals[len(vals)-1],
+		modelRefl:  vals[len(vals)-2],
+		root:       model,
+		field:      flist[len(flist)-1],
+		writeBacks: writeBacks,
+	}
+	if len(writeBacks) != 0 {
+		oe.refresh = func() (*objEval, bool) {
+			return evaluateObjField(query, model)
+		}
+	}
+	return oe, true
+}
+
+// nilFieldOf returns the path of the first nil pointer or interface that the
+// evaluation of the field (obj.field1.field2) goes through, if any
+func nilFieldOf(query string, model reflect.Value) (path string, ok bool) {
+	flist := strings.Split(query, ".")
+	o := model
+	for i, field := range flist[:len(flist)-1] {
+		var found bool
+		o, found = getReflectField(o, strings.TrimSuffix(field, "?"))
+		if !found {
+			return
+		}
+
+		if (o.Kind() == reflect.Ptr || o.Kind() == reflect.Interface) && o.IsNil() {
+			return strings.Join(flist[:i+1], "."), true
+		}
+	}
+
+	return
+}
+
+// addressableMapValue replaces a value v taken from the map m, which is not addressable,
+// by an addressable copy when it needs to be written to: when it's the last field of the path
+// or a struct or array whose fields are accessed. It returns a function writing the copy back
+// to the map.
+func addressableMapValue(m reflect.Value, key string, v *reflect.Value, last bool) (writeBack func(), ok bool) {
+	if m.Kind() == reflect.Ptr {
+		m = m.Elem()
+	}
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return
+	}
+
+	kind := v.Kind()
+	if !last && kind != reflect.Struct && kind != reflect.Array {
+		return
+	}
+
+	c := reflect.New(v.Type()).Elem()
+	c.Set(*v)
+	*v = c
+	k := reflect.ValueOf(key).Convert(m.Type().Key())
+	return func() {
+		m.SetMapIndex(k, c)
 	}, true
 }
 
+// indexValue returns the element of the map, slice, array or string at the given index.
+// A missing map key, an index out of range or a nil value is an error.
+func indexValue(target, index reflect.Value) (v reflect.Value, err error) {
+	target, index = unwrapValue(target), unwrapValue(index)
+	if !target.IsValid() || isNilValue(target) {
+		err = fmt.Errorf(`Cannot index a nil value.`)
+		return
+	}
+	if target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+	if !index.IsValid() {
+		err = fmt.Errorf(`Invalid nil index.`)
+		return
+	}
+
+	switch target.Kind() {
+	case reflect.Map:
+		ktype := target.Type().Key()
+		key := index
+		if !key.Type().AssignableTo(ktype) {
+			if !key.Type().ConvertibleTo(ktype) || (key.Kind() == reflect.String) != (ktype.Kind() == reflect.String) {
+				err = fmt.Errorf(`Invalid key "%v" of type "%v" for a map with keys of type "%v".`, key.Interface(), key.Type(), ktype)
+				return
+			}
+			key = key.Convert(ktype)
+		}
+
+		v = target.MapIndex(key)
+		if !v.IsValid() {
+			err = fmt.Errorf(`No key "%v" in the map.`, key.Interface())
+			return
+		}
+	case reflect.Slice, reflect.Array, reflect.String:
+		if !isIntKind(index.Kind()) {
+			err = fmt.Errorf(`Invalid index "%v", it must be an integer.`, index.Interface())
+			return
+		}
+		i := toInt64(index)
+		if i < 0 || i >= int64(target.Len()) {
+			err = fmt.Errorf(`Index %v out of range, the length is %v.`, i, target.Len())
+			return
+		}
+
+		v = target.Index(int(i))
+		// like the items of bind-each, structs are accessed by reference so that
+		// their fields can be watched and set
+		if v.Kind() == reflect.Struct && v.CanAddr() {
+			v = v.Addr()
+		}
+	default:
+		err = fmt.Errorf(`Cannot index a value of type "%v".`, target.Type())
+		return
+	}
+
+	v = unwrapValue(v)
+	return
+}
+
+// isLengthField checks whether the field is the "length" (or "len") pseudo-property
+// of collections
+func isLengthField(field string) bool {
+	return field == "length" || field == "len"
+}
+
+// collectionLen returns the length of a slice, array, map or string
+func collectionLen(o reflect.Value) (int, bool) {
+	if o.Kind() == reflect.Ptr {
+		o = o.Elem()
+	}
+
+	switch o.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		return o.Len(), true
+	}
+
+	return 0, false
+}
+
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// zeroOfPath returns the zero value of the type at the end of the given field path,
+// starting from typ. If the type cannot be determined without a value, the zero
+// value of interface{} (nil) is returned.
+func zeroOfPath(typ reflect.Type, fields []string) reflect.Value {
+	for _, field := range fields {
+		field = strings.TrimSuffix(field, "?")
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		switch typ.Kind() {
+		case reflect.Struct:
+			sf, ok := typ.FieldByName(field)
+			if !ok {
+				return reflect.Zero(emptyInterfaceType)
+			}
+			typ = sf.Type
+		case reflect.Map:
+			typ = typ.Elem()
+		default:
+			return reflect.Zero(emptyInterfaceType)
+		}
+	}
+
+	return reflect.Zero(typ)
+}
+
 // getReflectField returns the field value of an object, be it a struct instance
 // or a map
 func getReflectField(o reflect.Value, field string) (reflect.Value, bool) {
 	var rv reflect.Value
 
+	// an interface is resolved against its dynamic value
+	if o.Kind() == reflect.Interface {
+		o = o.Elem()
+	}
 	if o.Kind() == reflect.Ptr {
 		o = o.Elem()
 	}
@@ -98,6 +446,9 @@ func getReflectField(o reflect.Value, field string) (reflect.Value, bool) {
 		if !rv.IsValid() && o.CanAddr() {
 			rv = o.Addr().MethodByName(field)
 		}
+		if !rv.IsValid() {
+			rv = o.MethodByName(field)
+		}
 	case reflect.Map:
 		rv = o.MapIndex(reflect.ValueOf(field))
 		if rv.IsValid() {
@@ -113,3 +464,45 @@ func getReflectField(o reflect.Value, field string) (reflect.Value, bool) {
 
 	return rv, false
 }
+
+// getAccessorField evaluates the field through the model's accessor methods
+// (fieldGet and fieldSet), it returns the getter's result and the setter, if any
+func getAccessorField(o reflect.Value, field string) (v reflect.Value, setter reflect.Value, ok bool) {
+	if o.Kind() != reflect.Ptr {
+		if !o.CanAddr() {
+			return
+		}
+		o = o.Addr()
+	}
+
+	getter := o.MethodByName(field + AccessorGetSuffix)
+	if !getter.IsValid() {
+		return
+	}
+
+	gtype := getter.Type()
+	if gtype.NumIn() != 0 || gtype.NumOut() != 1 {
+		return
+	}
+
+	v = getter.Call([]reflect.Value{})[0]
+	ok = true
+
+	setter = o.MethodByName(field + AccessorSetSuffix)
+	if setter.IsValid() {
+		stype := setter.Type()
+		if stype.NumIn() != 1 || !gtype.Out(0).AssignableTo(stype.In(0)) {
+			setter = reflect.Value{}
+		}
+	}
+
+	return
+}
+
+// accessorFieldName returns the name of the unexported field behind
+// a pair of accessor methods, which is the one being watched
+func accessorFieldName(field string) string {
+	r := []rune(field)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
@@ -0,0 +1,74 @@
+package bind
+
+import (
+	"fmt"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+const (
+	// CommitWhenAttr gates the 2-way binding of an element behind a boolean expression.
+	// While the expression is false, the input isn't written to the model, the latest
+	// input is kept instead and written once the expression becomes true. While it's
+	// true, the input is written right away like without the attribute.
+	//
+	// Usage:
+	//	<input bind-value="Draft.Title" wade-commit-when="Committed" />
+	CommitWhenAttr = "wade-commit-when"
+)
+
+// commitGate buffers the writes to the model while it's closed
+type commitGate struct {
+	open    bool
+	write   ModelUpdateFn
+	pending *string
+}
+
+func newCommitGate(open bool, write ModelUpdateFn) *commitGate {
+	return &commitGate{open: open, write: write}
+}
+
+// input writes the value if the gate is open, otherwise it's kept
+// until the gate opens, replacing the previously kept one
+func (g *commitGate) input(value string) {
+	if g.open {
+		g.write(value)
+		return
+	}
+
+	g.pending = &value
+}
+
+// setOpen opens or closes the gate, opening it flushes the kept input
+func (g *commitGate) setOpen(open bool) {
+	g.open = open
+	if open && g.pending != nil {
+		value := *g.pending
+		g.pending = nil
+		g.write(value)
+	}
+}
+
+func gateValue(v interface{}, bstr string) bool {
+	open, ok := v.(bool)
+	if !ok {
+		bindStringPanic(fmt.Sprintf(`the %v expression must be a bool, got %v`, CommitWhenAttr, v), bstr)
+	}
+	return open
+}
+
+// gateWrites returns the update function that writes through a commit gate
+// if the element has the CommitWhenAttr attribute, or write otherwise
+func (b *Binding) gateWrites(elem jq.JQuery, bs *bindScope, write ModelUpdateFn) ModelUpdateFn {
+	if !elem.Is("[" + CommitWhenAttr + "]") {
+		return write
+	}
+
+	bstr := elem.Attr(CommitWhenAttr)
+	root, binds, v := bs.evaluateBindString(bstr)
+	gate := newCommitGate(gateValue(v, bstr), write)
+	b.watchModel(elem, binds, root, bs, func(v interface{}) {
+		gate.setOpen(gateValue(v, bstr))
+	})
+	return gate.input
+}
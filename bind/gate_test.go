@@ -0,0 +1,42 @@
+package bind
+
+import (
+	"testing"
+)
+
+func TestCommitGate(t *testing.T) {
+	model := &testSignup{}
+	written := 0
+	gate := newCommitGate(false, func(v string) {
+		written++
+		model.Username = v
+	})
+
+	gate.input("ja")
+	gate.input("jane")
+	if model.Username != "" || written != 0 {
+		t.Fatalf("Expected the input not to reach the model while the gate is closed, got %q.", model.Username)
+	}
+
+	gate.setOpen(false)
+	if written != 0 {
+		t.Errorf("Expected nothing to be flushed while the gate stays closed.")
+	}
+
+	gate.setOpen(true)
+	if model.Username != "jane" || written != 1 {
+		t.Errorf("Expected the latest input to be flushed once, got %q after %v writes.", model.Username, written)
+	}
+
+	gate.setOpen(true)
+	gate.input("janet")
+	if model.Username != "janet" || written != 2 {
+		t.Errorf("Expected the input to be written right away while the gate is open, got %q.", model.Username)
+	}
+
+	gate.setOpen(false)
+	gate.input("jan")
+	if model.Username != "janet" {
+		t.Errorf("Expected the input to be kept again once the gate closes, got %q.", model.Username)
+	}
+}
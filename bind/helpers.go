@@ -2,7 +2,10 @@ package bind
 
 import (
 	"fmt"
+	"math"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -17,22 +20,192 @@ type UrlInfo struct {
 	fullUrl string
 }
 
-func RegisterInternalHelpers(pm PageManager, b *Binding) {
-	b.RegisterHelper("url", func(pageid string, params ...interface{}) UrlInfo {
-		url, err := pm.PageUrl(pageid, params)
+// Option is an item of a select option list, as produced by the "options" helper.
+type Option struct {
+	Value string
+	Label string
+}
+
+type optionsByValue []Option
+
+func (o optionsByValue) Len() int           { return len(o) }
+func (o optionsByValue) Swap(i, j int)      { o[i], o[j] = o[j], o[i] }
+func (o optionsByValue) Less(i, j int) bool { return o[i].Value < o[j].Value }
+
+// makeOptions converts a map[string]string (value -> label) or a []string
+// into an option list. Map entries are sorted by value so that the order
+// is stable, slice items keep their order and use the item as both value and label.
+func makeOptions(src interface{}) []Option {
+	switch s := src.(type) {
+	case map[string]string:
+		opts := make([]Option, 0, len(s))
+		for value, label := range s {
+			opts = append(opts, Option{value, label})
+		}
+		sort.Sort(optionsByValue(opts))
+		return opts
+	case []string:
+		opts := make([]Option, len(s))
+		for i, item := range s {
+			opts[i] = Option{item, item}
+		}
+		return opts
+	}
+
+	panic(fmt.Errorf(`options helper: unsupported type %v, expected map[string]string or []string.`, reflect.TypeOf(src)))
+}
+
+// classNames joins the names of the active classes with spaces, like the classnames
+// javascript library. It accepts either class name and condition pairs, or a map
+// from class names to conditions (like a map literal), whose names are sorted.
+//
+// Usage:
+//	bind-attr-class="cx(`todo`, true, `done`, Done, `editing`, Editing)"
+//	bind-attr-class="cx({todo: true, done: Done})"
+func classNames(args ...interface{}) string {
+	active := make([]string, 0)
+	if len(args) == 1 {
+		classes, err := classMap(args[0])
 		if err != nil {
-			panic(fmt.Errorf(`url helper error: "%v", when getting url for page "%v"`, err.Error(), pageid))
+			panic(fmt.Errorf(`cx helper: %v`, err.Error()))
+		}
+		for class, on := range classes {
+			if on {
+				active = append(active, class)
+			}
+		}
+		sort.Strings(active)
+		return strings.Join(active, " ")
+	}
+
+	if len(args)%2 != 0 {
+		panic(fmt.Errorf(`cx helper: expected a map or class name and condition pairs, got %v arguments.`, len(args)))
+	}
+	for i := 0; i < len(args); i += 2 {
+		class, ok := args[i].(string)
+		if !ok {
+			panic(fmt.Errorf(`cx helper: argument %v must be a class name, got %v.`, i+1, reflect.TypeOf(args[i])))
+		}
+		on, ok := args[i+1].(bool)
+		if !ok {
+			panic(fmt.Errorf(`cx helper: the condition for "%v" must be a bool, got %v.`, class, reflect.TypeOf(args[i+1])))
+		}
+		if on {
+			active = append(active, class)
 		}
-		return UrlInfo{url, pm.Url(url)}
-	})
+	}
+	return strings.Join(active, " ")
+}
+
+// CountPlaceholder is replaced with the count in the forms given to the plural helper
+const CountPlaceholder = "{n}"
 
-	b.RegisterHelper("pageId", func() string {
-		return pm.CurrentPageId()
-	})
+// pluralForm returns the singular form if n is 1, the plural form otherwise,
+// with CountPlaceholder replaced by n.
+//
+// Usage:
+//	bind-text="plural(Entries.length, `{n} item left`, `{n} items left`)"
+func pluralForm(n interface{}, singular, plural string) string {
+	v := unwrapValue(reflect.ValueOf(n))
+	var one bool
+	switch {
+	case isIntKind(v.Kind()):
+		one = toInt64(v) == 1
+	case isFloatKind(v.Kind()):
+		one = v.Float() == 1
+	default:
+		panic(fmt.Errorf(`plural helper: the count must be a number, got %v.`, reflect.TypeOf(n)))
+	}
+
+	form := plural
+	if one {
+		form = singular
+	}
+	return strings.Replace(form, CountPlaceholder, toString(n), -1)
 }
 
-func defaultHelpers() map[string]interface{} {
+// ordinal returns the english ordinal of n, like 1st, 2nd, 3rd, 11th or 22nd
+func ordinal(n int) string {
+	suffix := "th"
+	switch abs := int(math.Abs(float64(n))); {
+	case abs%100 >= 11 && abs%100 <= 13:
+	case abs%10 == 1:
+		suffix = "st"
+	case abs%10 == 2:
+		suffix = "nd"
+	case abs%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// deref returns the value that the pointer points to, or def if the pointer is nil.
+// A value that is not a pointer is returned as is, unless it's nil.
+//
+// Usage:
+//	bind-text="deref(Profile?.Nickname, `anonymous`)"
+func deref(ptr interface{}, def interface{}) interface{} {
+	v := reflect.ValueOf(ptr)
+	if !v.IsValid() || isNilValue(v) {
+		return def
+	}
+
+	if v.Kind() == reflect.Ptr {
+		return v.Elem().Interface()
+	}
+	return ptr
+}
+
+// div divides a by b, or returns def if b is zero. Like the arithmetic operators,
+// the quotient has the type of the operands if it's the same, it's an int for mixed
+// integers and a float64 for other mixed numbers.
+//
+// Usage:
+//	bind-text="div(Done, Total, 0)"
+func div(a, b, def interface{}) interface{} {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	af, aok := toFloat(av)
+	bf, bok := toFloat(bv)
+	if !aok || !bok {
+		panic(fmt.Sprintf("div helper: %v and %v must be numbers.", a, b))
+	}
+	if bf == 0 {
+		return def
+	}
+
+	q := reflect.ValueOf(af / bf)
+	if isIntKind(av.Kind()) && isIntKind(bv.Kind()) {
+		q = reflect.ValueOf(int(toInt64(av) / toInt64(bv)))
+	}
+	if av.Type() == bv.Type() {
+		return q.Convert(av.Type()).Interface()
+	}
+	return q.Interface()
+}
+
+func RegisterInternalHelpers(pm PageManager, b *Binding) {
+	for name, fn := range internalHelpers(pm) {
+		b.RegisterHelper(name, fn)
+	}
+}
+
+func internalHelpers(pm PageManager) map[string]interface{} {
 	return map[string]interface{}{
+		"url": func(pageid string, params ...interface{}) UrlInfo {
+			url, err := pm.PageUrl(pageid, params)
+			if err != nil {
+				panic(fmt.Errorf(`url helper error: "%v", when getting url for page "%v"`, err.Error(), pageid))
+			}
+			return UrlInfo{url, pm.Url(url)}
+		},
+		"pageId": func() string {
+			return pm.CurrentPageId()
+		},
+	}
+}
+
+func defaultHelpers() map[string]interface{} {
+	helpers := map[string]interface{}{
 		"toUpper": strings.ToUpper,
 		"toLower": strings.ToLower,
 		"concat": func(s1, s2 string) string {
@@ -47,5 +220,22 @@ func defaultHelpers() map[string]interface{} {
 		"len": func(collection interface{}) int {
 			return reflect.ValueOf(collection).Len()
 		},
+		"options": makeOptions,
+		"number":  formatNumber,
+		"cx":      classNames,
+		"plural":  pluralForm,
+		"ordinal": ordinal,
+		"deref":   deref,
+		"div":     div,
+	}
+
+	for name, fn := range validationHelpers() {
+		helpers[name] = fn
 	}
+
+	for name, fn := range aggregateHelpers() {
+		helpers[name] = fn
+	}
+
+	return helpers
 }
@@ -0,0 +1,447 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type testChoices struct {
+	Colors map[string]string
+	Sizes  []string
+}
+
+func TestOptions(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testChoices{
+		Colors: map[string]string{"r": "Red", "g": "Green", "b": "Blue"},
+		Sizes:  []string{"S", "M", "L"},
+	}
+
+	_, _, v, err := b.evaluate("options(Colors)", model)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []Option{{"b", "Blue"}, {"g", "Green"}, {"r", "Red"}}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("Expected %v, got %v.", expected, v)
+	}
+
+	_, _, v, err = b.evaluate("options(Sizes)", model)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = []Option{{"S", "S"}, {"M", "M"}, {"L", "L"}}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("Expected %v, got %v.", expected, v)
+	}
+}
+
+func TestNumberHelper(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testCart{"wade", 1234567, 3}
+	tests := map[string]string{
+		"number(Price)":                 "1,234,567",
+		"number(Price, `en-US`)":        "1,234,567",
+		"number(Price, `de`)":           "1.234.567",
+		"number(1234.5)":                "1,234.5",
+		"number(Price * 1.25, `vi-VN`)": "1.543.208,75",
+		"number(0 - Price)":             "-1,234,567",
+		"number(Quantity)":              "3",
+		"number(999)":                   "999",
+		"number(1000, `fr`)":            "1 000",
+	}
+	for bstr, expected := range tests {
+		v, err := b.Eval(model, bstr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", bstr, err)
+		} else if v != expected {
+			t.Errorf("%v: expected %q, got %q.", bstr, expected, v)
+		}
+	}
+}
+
+type testTodo struct {
+	Title string
+	Done  bool
+	Hours float64
+	Est   int
+}
+
+type testTodoList struct {
+	Entries []*testTodo
+	Values  []interface{}
+}
+
+func TestAggregateHelpers(t *testing.T) {
+	b := NewBindEngine(nil)
+	b.RegisterHelper("isIncomplete", func(todo *testTodo) bool {
+		return !todo.Done
+	})
+	model := &testTodoList{
+		Entries: []*testTodo{
+			{"a", true, 1.5, 2},
+			{"b", false, 0.5, 3},
+			{"c", false, 0, 1},
+		},
+		Values: []interface{}{1, 2, 3.5},
+	}
+
+	tests := map[string]interface{}{
+		"count(Entries)":                       3,
+		"count(Entries, `Done`)":               1,
+		"count(Entries, isIncomplete)":         2,
+		"sum(Entries, `Est`)":                  6,
+		"sum(Entries, `Hours`)":                2.0,
+		"sum(Values)":                          6.5,
+		"count(filter(Entries, isIncomplete))": 2,
+		"sum(filter(Entries, `Done`), `Est`)":  2,
+	}
+	for bstr, expected := range tests {
+		v, err := b.Eval(model, bstr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", bstr, err)
+		} else if v != expected {
+			t.Errorf("%v: expected %v, got %v.", bstr, expected, v)
+		}
+	}
+
+	// it's evaluated again when the slice changes
+	model.Entries = append(model.Entries, &testTodo{"d", false, 1, 4})
+	if v, _ := b.Eval(model, "count(Entries, isIncomplete)"); v != 3 {
+		t.Errorf("Expected 3 after a change, got %v.", v)
+	}
+	v, _ := b.Eval(model, "filter(Entries, `Done`)")
+	if entries, ok := v.([]*testTodo); !ok || len(entries) != 1 || entries[0] != model.Entries[0] {
+		t.Errorf("Expected the filtered slice to hold the done item, got %v.", v)
+	}
+
+	for _, bstr := range []string{"count(Entries, `Title`)", "sum(Entries, `Title`)", "count(Entries, `Nothing`)", "count(Entries, toUpper)", "sum(Title)"} {
+		if _, err := b.Eval(model, bstr); err == nil {
+			t.Errorf("Expected an error for %v.", bstr)
+		}
+	}
+}
+
+func TestClassNames(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testTodo{Title: "a", Done: true}
+	tests := map[string]string{
+		"cx(`todo`, true, `done`, Done, `empty`, Title == ``)": "todo done",
+		"cx(`done`, !Done)": "",
+		"cx({todo: true, done: Done, `is-empty`: false})": "done todo",
+		"cx({})": "",
+	}
+	for bstr, expected := range tests {
+		v, err := b.Eval(model, bstr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", bstr, err)
+		} else if v != expected {
+			t.Errorf("%v: expected %q, got %q.", bstr, expected, v)
+		}
+	}
+
+	for _, bstr := range []string{"cx(`todo`)", "cx(`todo`, true, `done`)", "cx(Done, `done`)", "cx(`done`, Title)", "cx({done: Title})"} {
+		if _, err := b.Eval(model, bstr); err == nil {
+			t.Errorf("Expected an error for %v.", bstr)
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testTodoList{}
+	expected := []string{"0 items left", "1 item left", "2 items left"}
+	for n, exp := range expected {
+		model.Entries = make([]*testTodo, n)
+		v, err := b.Eval(model, "plural(Entries.length, `{n} item left`, `{n} items left`)")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if v != exp {
+			t.Errorf("n=%v: expected %q, got %q.", n, exp, v)
+		}
+	}
+
+	if v := pluralForm(1.5, "{n} hour", "{n} hours"); v != "1.5 hours" {
+		t.Errorf("Expected the plural form for 1.5, got %q.", v)
+	}
+	if _, err := b.Eval(model, "plural(`1`, `item`, `items`)"); err == nil {
+		t.Errorf("Expected an error for a non-number count.")
+	}
+
+	ordinals := map[int]string{0: "0th", 1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd", 101: "101st", 111: "111th"}
+	for n, exp := range ordinals {
+		if v := ordinal(n); v != exp {
+			t.Errorf("Expected %v for %v, got %v.", exp, n, v)
+		}
+	}
+}
+
+type testWallet struct {
+	Balance int
+}
+
+func (a *testWallet) Withdraw(amount int) (int, error) {
+	if amount > a.Balance {
+		return 0, fmt.Errorf("insufficient balance")
+	}
+	return a.Balance - amount, nil
+}
+
+type testTodoView struct {
+	Todos []*testTodo
+	Tags  []string
+	Mode  string
+}
+
+func TestFilterExpr(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testTodoView{
+		Todos: []*testTodo{
+			{"a", true, 1, 1},
+			{"b", false, 2, 2},
+			{"c", false, 3, 3},
+		},
+		Tags: []string{"go", "js", "gopher"},
+		Mode: "all",
+	}
+
+	bstr := "filter(Todos, Mode == 'all' || Done == (Mode == 'completed'))"
+	titles := func() string {
+		bs := &bindScope{newModelScope(model)}
+		bs.scope.merge(b.scope)
+		_, blist, v, err := bs.evaluate(bstr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		watched := false
+		for _, bi := range blist {
+			if bi.bindObj().field == "Mode" {
+				watched = true
+			}
+		}
+		if !watched {
+			t.Errorf("Expected the Mode field used by the predicate to be watched.")
+		}
+
+		s := ""
+		for _, todo := range v.([]*testTodo) {
+			s += todo.Title
+		}
+		return s
+	}
+
+	for mode, expected := range map[string]string{"all": "abc", "active": "bc", "completed": "a"} {
+		model.Mode = mode
+		if s := titles(); s != expected {
+			t.Errorf("Mode %v: expected %v, got %v.", mode, expected, s)
+		}
+	}
+	model.Mode = "completed"
+	model.Todos[1].Done = true
+	if s := titles(); s != "ab" {
+		t.Errorf("Expected the view to follow the items, got %v.", s)
+	}
+	model.Todos = nil
+	if s := titles(); s != "" {
+		t.Errorf("Expected an empty view, got %v.", s)
+	}
+
+	v, err := b.Eval(model, "filter(Tags, $item != 'js')")
+	if tags, ok := v.([]string); err != nil || !ok || len(tags) != 2 || tags[1] != "gopher" {
+		t.Errorf("Expected the items to be filtered by $item, got %v (%v).", v, err)
+	}
+
+	for _, bstr := range []string{"filter(Todos, `Title`)", "filter(Todos, Title)", "filter(Todos, Nothing)"} {
+		model.Todos = []*testTodo{{"a", true, 1, 1}}
+		if _, err := b.Eval(model, bstr); err == nil {
+			t.Errorf("Expected an error for %v.", bstr)
+		}
+	}
+}
+
+func TestErrorReturningFuncs(t *testing.T) {
+	b := NewBindEngine(nil)
+	b.RegisterHelper("parseInt", func(s string) (int, error) {
+		return strconv.Atoi(s)
+	})
+	model := &testWallet{10}
+
+	for bstr, expected := range map[string]interface{}{
+		"parseInt(`42`) + 1": 43,
+		"Withdraw(4)":        6,
+	} {
+		v, err := b.Eval(model, bstr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", bstr, err)
+		} else if v != expected {
+			t.Errorf("%v: expected %v, got %v.", bstr, expected, v)
+		}
+	}
+
+	for _, bstr := range []string{"parseInt(`forty`)", "Withdraw(20)"} {
+		if _, err := b.Eval(model, bstr); err == nil {
+			t.Errorf("Expected the error of %v.", bstr)
+		}
+	}
+	if errs := b.Check(`<p bind-text="parseInt(`+"`1`"+`) + 1"></p>`, model); len(errs) != 0 {
+		t.Errorf("Unexpected errors %v.", errs)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a helper with 2 return values other than an error.")
+		}
+	}()
+	b.RegisterHelper("divmod", func(a, b int) (int, int) {
+		return a / b, a % b
+	})
+}
+
+type testAccountProfile struct {
+	Nickname *string
+	Owner    *testTodo
+}
+
+func TestDeref(t *testing.T) {
+	b := NewBindEngine(nil)
+	nick := "jd"
+	model := &testAccountProfile{}
+
+	if v, err := b.Eval(model, "deref(Nickname, `anonymous`)"); err != nil || v != "anonymous" {
+		t.Errorf("Expected the default for a nil pointer, got %v (%v).", v, err)
+	}
+	model.Nickname = &nick
+	if v, err := b.Eval(model, "deref(Nickname, `anonymous`)"); err != nil || v != "jd" {
+		t.Errorf("Expected the dereferenced value, got %v (%v).", v, err)
+	}
+	if v, err := b.Eval(model, "deref(Owner?.Title, `none`)"); err != nil || v != "" {
+		t.Errorf("Expected a value that is not a pointer to be returned as is, got %v (%v).", v, err)
+	}
+
+	if s := (DomBind{Value: model.Owner}).ValueString(); s != "" {
+		t.Errorf("Expected a nil pointer to be displayed as empty, got %q.", s)
+	}
+	if s := (DomBind{Value: model.Nickname}).ValueString(); s == "" {
+		t.Errorf("Expected a pointer that is not nil to be displayed.")
+	}
+}
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		a, b, def interface{}
+		expected  interface{}
+	}{
+		{7, 2, 0, 3},
+		{int64(9), int64(3), 0, int64(3)},
+		{7, int64(2), 0, 3},
+		{7.0, 2.0, 0.0, 3.5},
+		{float32(1), float32(4), 0, float32(0.25)},
+		{3, 1.5, 0, 2.0},
+		{7, 0, 0, 0},
+		{7.5, 0.0, -1, -1},
+		{uint(5), 0, "n/a", "n/a"},
+	}
+	for _, test := range tests {
+		if q := div(test.a, test.b, test.def); !reflect.DeepEqual(q, test.expected) {
+			t.Errorf("div(%v, %v, %v): expected %#v, got %#v.", test.a, test.b, test.def, test.expected, q)
+		}
+	}
+
+	b := NewBindEngine(nil)
+	model := &testTodo{}
+	if v, err := b.Eval(model, "div(6, 0, -1)"); err != nil || v != -1 {
+		t.Errorf("Expected the default for a zero divisor, got %v (%v).", v, err)
+	}
+	if v, err := b.Eval(model, "div(6, 4, 0)"); err != nil || v != 1 {
+		t.Errorf("Expected the integer quotient, got %v (%v).", v, err)
+	}
+	if v, err := b.Eval(model, "div(6.0, 4.0, 0)"); err != nil || v != float32(1.5) {
+		t.Errorf("Expected the float quotient, got %v (%v).", v, err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected a panic for a value that is not a number.")
+			}
+		}()
+		div("6", 2, 0)
+	}()
+}
+
+type testMilestone struct {
+	Title string
+	Due   time.Time
+}
+
+type testSortList struct {
+	Todos      []*testTodo
+	Milestones []testMilestone
+	SortBy     string
+	Asc        bool
+}
+
+func TestSortHelper(t *testing.T) {
+	b := NewBindEngine(nil)
+	day := func(d int) time.Time { return time.Date(2014, 6, d, 0, 0, 0, 0, time.UTC) }
+	model := &testSortList{
+		Todos: []*testTodo{
+			{"b", false, 0.5, 3},
+			{"c", true, 2, 1},
+			{"a", false, 1.5, 3},
+		},
+		Milestones: []testMilestone{{"beta", day(20)}, {"alpha", day(2)}, {"final", day(30)}},
+		SortBy:     "Title",
+		Asc:        true,
+	}
+
+	titles := func(v interface{}) string {
+		s := ""
+		switch items := v.(type) {
+		case []*testTodo:
+			for _, item := range items {
+				s += item.Title
+			}
+		case []testMilestone:
+			for _, item := range items {
+				s += item.Title + " "
+			}
+		}
+		return s
+	}
+
+	tests := map[string]string{
+		"sort(Todos, `Title`)":           "abc",
+		"sort(Todos, `Title`, false)":    "cba",
+		"sort(Todos, `Est`, false)":      "bac",
+		"sort(Todos, `Hours`, true)":     "bac",
+		"sort(Todos, SortBy, Asc)":       "abc",
+		"sort(Milestones, `Due`, true)":  "alpha beta final ",
+		"sort(Milestones, `Due`, false)": "final beta alpha ",
+	}
+	for bstr, expected := range tests {
+		v, err := b.Eval(model, bstr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", bstr, err)
+		} else if s := titles(v); s != expected {
+			t.Errorf("%v: expected %v, got %v.", bstr, expected, s)
+		}
+	}
+	if model.Todos[0].Title != "b" {
+		t.Errorf("Expected the source slice not to be sorted.")
+	}
+
+	// it's sorted again when the sort key changes
+	model.SortBy, model.Asc = "Est", false
+	if v, _ := b.Eval(model, "sort(Todos, SortBy, Asc)"); titles(v) != "bac" {
+		t.Errorf("Expected the items to be sorted by the new key, got %v.", titles(v))
+	}
+
+	if _, err := b.Eval(model, "sort(Todos, `Done`)"); err == nil {
+		t.Errorf("Expected an error for a bool sort key.")
+	}
+}
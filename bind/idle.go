@@ -0,0 +1,110 @@
+package bind
+
+import (
+	"time"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// IdleTimeout is the delay after which the idle updates are run when the browser
+// doesn't support requestIdleCallback
+const IdleTimeout = 50 * time.Millisecond
+
+// UpdatePriority is the tier of a binder's updates
+type UpdatePriority int
+
+const (
+	// CriticalPriority updates are performed right away when the model changes
+	CriticalPriority UpdatePriority = iota
+	// IdlePriority updates are deferred until the browser is idle, they are
+	// for low priority bindings like tooltips or counters that shouldn't
+	// compete with the critical updates
+	IdlePriority
+)
+
+// PriorityBinder is implemented by binders that declare the tier of their updates.
+// Binders that don't implement it are critical. Only the updates that follow
+// model changes are deferred, the first Update is always performed when binding.
+type PriorityBinder interface {
+	DomBinder
+	Priority() UpdatePriority
+}
+
+// IdleScheduler runs functions when the browser is idle, it's used for the
+// updates of IdlePriority binders
+type IdleScheduler interface {
+	RequestIdle(fn func())
+}
+
+// idleCallbackScheduler is the default IdleScheduler, it uses the browser's
+// requestIdleCallback and falls back to a timeout when it's not available
+type idleCallbackScheduler struct{}
+
+func (s idleCallbackScheduler) RequestIdle(fn func()) {
+	ric := js.Global.Get("requestIdleCallback")
+	if ric.IsUndefined() {
+		time.AfterFunc(IdleTimeout, fn)
+		return
+	}
+
+	js.Global.Call("requestIdleCallback", func() {
+		fn()
+	})
+}
+
+// idleTask is a deferred update of a binding, an update requested while
+// the previous one is still pending replaces it
+type idleTask struct {
+	fn     func()
+	queued bool
+}
+
+// idleQueue gathers the idle tasks and runs them together in the next idle phase
+type idleQueue struct {
+	scheduler IdleScheduler
+	tasks     []*idleTask
+}
+
+func newIdleQueue(scheduler IdleScheduler) *idleQueue {
+	return &idleQueue{scheduler: scheduler}
+}
+
+func (q *idleQueue) schedule(task *idleTask, fn func()) {
+	task.fn = fn
+	if task.queued {
+		return
+	}
+
+	task.queued = true
+	q.tasks = append(q.tasks, task)
+	if len(q.tasks) == 1 {
+		q.scheduler.RequestIdle(q.run)
+	}
+}
+
+func (q *idleQueue) run() {
+	tasks := q.tasks
+	q.tasks = nil
+	for _, task := range tasks {
+		task.queued = false
+		task.fn()
+	}
+}
+
+// SetIdleScheduler sets the scheduler used for the updates of IdlePriority binders
+func (b *Binding) SetIdleScheduler(s IdleScheduler) {
+	b.idle = newIdleQueue(s)
+}
+
+// updateFunc returns the function performing the updates of the binder after
+// model changes, it defers them to the idle phase for IdlePriority binders
+func (b *Binding) updateFunc(binder DomBinder, update func()) func() {
+	if pb, ok := binder.(PriorityBinder); !ok || pb.Priority() != IdlePriority {
+		return update
+	}
+
+	task := &idleTask{}
+	return func() {
+		b.idle.schedule(task, update)
+	}
+}
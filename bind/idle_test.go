@@ -0,0 +1,64 @@
+package bind
+
+import (
+	"testing"
+)
+
+type fakeIdleScheduler struct {
+	pending []func()
+}
+
+func (s *fakeIdleScheduler) RequestIdle(fn func()) {
+	s.pending = append(s.pending, fn)
+}
+
+func (s *fakeIdleScheduler) idle() {
+	pending := s.pending
+	s.pending = nil
+	for _, fn := range pending {
+		fn()
+	}
+}
+
+type idleTestBinder struct {
+	BaseBinder
+}
+
+func (b *idleTestBinder) BindInstance() DomBinder  { return b }
+func (b *idleTestBinder) Priority() UpdatePriority { return IdlePriority }
+
+func TestIdleUpdates(t *testing.T) {
+	sched := &fakeIdleScheduler{}
+	b := NewBindEngine(nil)
+	b.SetIdleScheduler(sched)
+
+	var log []string
+	critical := b.updateFunc(new(ValueBinder), func() { log = append(log, "critical") })
+	tooltip := b.updateFunc(&idleTestBinder{}, func() { log = append(log, "tooltip") })
+	counter := b.updateFunc(&idleTestBinder{}, func() { log = append(log, "counter") })
+
+	tooltip()
+	counter()
+	tooltip()
+	critical()
+	if len(log) != 1 || log[0] != "critical" {
+		t.Fatalf("Expected only the critical update to run right away, got %v.", log)
+	}
+	if len(sched.pending) != 1 {
+		t.Fatalf("Expected one idle callback to be requested, got %v.", len(sched.pending))
+	}
+
+	sched.idle()
+	if len(log) != 3 || log[1] != "tooltip" || log[2] != "counter" {
+		t.Errorf("Expected the low priority updates to run once each in the idle phase, got %v.", log)
+	}
+
+	counter()
+	if len(sched.pending) != 1 {
+		t.Fatalf("Expected a new idle callback for the later update.")
+	}
+	sched.idle()
+	if len(log) != 4 || log[3] != "counter" {
+		t.Errorf("Expected the later update to run in the next idle phase, got %v.", log)
+	}
+}
@@ -0,0 +1,78 @@
+package bind
+
+import (
+	"fmt"
+	"strings"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+const (
+	// IncludeTag is the tag of the elements that are replaced by the contents
+	// of another template, bound in the scope of the place they're included in.
+	// The template is given by its id in the src attribute, it's looked up
+	// like the templates of BindFragment.
+	//
+	// Usage:
+	//	<winclude src="partial-id"></winclude>
+	IncludeTag = "winclude"
+	// IncludeSrcAttr is the attribute of an include element giving the template id
+	IncludeSrcAttr = "src"
+)
+
+// includeStack is the chain of the templates being included, to detect
+// templates that include themselves
+type includeStack []string
+
+func (s includeStack) push(id string) (includeStack, error) {
+	if id == "" {
+		return s, fmt.Errorf(`The "%v" attribute of <%v> is required.`, IncludeSrcAttr, IncludeTag)
+	}
+
+	for _, included := range s {
+		if included == id {
+			chain := strings.Join(append(s, id), " -> ")
+			return s, fmt.Errorf(`Include cycle of template "%v": %v.`, id, chain)
+		}
+	}
+
+	return append(s[:len(s):len(s)], id), nil
+}
+
+// expandInclude replaces the include element with a copy of the template's contents,
+// the includes in the copy are expanded too. It returns the nodes that replace it.
+func (b *Binding) expandInclude(elem jq.JQuery, stack includeStack) jq.JQuery {
+	id := elem.Attr(IncludeSrcAttr)
+	stack, err := stack.push(id)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	tmpl, ok := b.template(id)
+	if !ok {
+		panic(fmt.Sprintf(`Template "%v" to include cannot be found.`, id))
+	}
+
+	container := gJQ("<div></div>").Append(tmpl.Clone().Contents())
+	container.Find(IncludeTag).Each(func(_ int, inc jq.JQuery) {
+		b.expandInclude(inc, stack)
+	})
+
+	nodes := container.Contents()
+	b.replaceElem(elem, nodes)
+	return nodes
+}
+
+// prepareInclude expands the include element and prepares the binds
+// of the included elements in the current scope
+func (b *Binding) prepareInclude(elem jq.JQuery, bs *bindScope, once bool) (bindTasks []func(), customElemTasks []func()) {
+	bindTasks = make([]func(), 0)
+	customElemTasks = make([]func(), 0)
+
+	b.expandInclude(elem, nil).Filter("*").Each(func(_ int, elem jq.JQuery) {
+		bt, cet := b.prepareElem(elem, bs, once)
+		bindTasks = append(bindTasks, bt...)
+		customElemTasks = append(customElemTasks, cet...)
+	})
+	return
+}
@@ -0,0 +1,71 @@
+package bind
+
+import "testing"
+
+func TestIncludeStack(t *testing.T) {
+	var stack includeStack
+	stack, err := stack.push("layout")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	header, err := stack.push("header")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	footer, _ := stack.push("footer")
+	if len(header) != 2 || header[1] != "header" || footer[1] != "footer" {
+		t.Errorf("Expected the sibling includes to have separate chains, got %v and %v.", header, footer)
+	}
+
+	if _, err := header.push("layout"); err == nil || err.Error() != `Include cycle of template "layout": layout -> header -> layout.` {
+		t.Errorf("Expected an include cycle error, got %v.", err)
+	}
+	if _, err := header.push("header"); err == nil {
+		t.Errorf("Expected an error for a template including itself.")
+	}
+	if _, err := stack.push(""); err == nil {
+		t.Errorf("Expected an error for a missing src.")
+	}
+}
+
+type testAuthor struct {
+	Name string
+}
+
+type testPost struct {
+	Title  string
+	Author *testAuthor
+}
+
+func TestInclude(t *testing.T) {
+	b := NewBindEngine(testTemplates{
+		"t-header": gJQ(`<div><h1 bind-text="Title"></h1><winclude src="t-author"></winclude></div>`),
+		"t-author": gJQ(`<div><em bind-text="Author.Name"></em></div>`),
+		"t-loop":   gJQ(`<div><p><winclude src="t-loop"></winclude></p></div>`),
+	})
+	w := newFakeWatcher()
+	b.fields = w
+	model := &testPost{"Hello", &testAuthor{"Ann"}}
+	elem := gJQ(`<div><header><winclude src="t-header"></winclude></header></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	if elem.Find(IncludeTag).Length != 0 || elem.Find("header > h1").Text() != "Hello" || elem.Find("header > em").Text() != "Ann" {
+		t.Fatalf("Expected the includes to be replaced and bound to the model, got %v.", elem.Html())
+	}
+
+	model.Title, model.Author.Name = "Bye", "Bob"
+	w.change()
+	if elem.Find("h1").Text() != "Bye" || elem.Find("em").Text() != "Bob" {
+		t.Errorf("Expected the included elements to be updated, got %v.", elem.Html())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a template including itself.")
+		}
+	}()
+	loop := gJQ(`<div><winclude src="t-loop"></winclude></div>`).AppendTo(gJQ("body"))
+	defer loop.Remove()
+	b.Bind(loop, model, false, false)
+}
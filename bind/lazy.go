@@ -0,0 +1,129 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+	jq "github.com/gopherjs/jquery"
+)
+
+// ViewportObserver watches whether elements are visible in the viewport,
+// it's used by the lazy binder
+type ViewportObserver interface {
+	// Observe calls fn when the element enters (visible is true) or leaves the viewport,
+	// extended by rootMargin (like "200px" or "100px 0px"). It returns a function
+	// that stops observing.
+	Observe(elem jq.JQuery, rootMargin string, fn func(visible bool)) (stop func())
+}
+
+// intersectionObserver is the default ViewportObserver, using the browser's IntersectionObserver.
+// The elements are considered visible right away if it's not available.
+type intersectionObserver struct{}
+
+func (o intersectionObserver) Observe(elem jq.JQuery, rootMargin string, fn func(visible bool)) (stop func()) {
+	ioClass := js.Global.Get("IntersectionObserver")
+	if ioClass.IsUndefined() {
+		fn(true)
+		return func() {}
+	}
+
+	io := ioClass.New(func(entries js.Object) {
+		for i := 0; i < entries.Length(); i++ {
+			fn(entries.Index(i).Get("isIntersecting").Bool())
+		}
+	}, map[string]interface{}{"rootMargin": rootMargin})
+	io.Call("observe", elem.Get(0))
+	return func() {
+		io.Call("disconnect")
+	}
+}
+
+// SetViewportObserver sets the observer used by the lazy binder
+func (b *Binding) SetViewportObserver(o ViewportObserver) {
+	b.viewport = o
+}
+
+// lazyRender keeps track of the rendering state of a lazy element
+type lazyRender struct {
+	rendered      bool
+	unbindOnLeave bool
+	render        func()
+	unrender      func()
+}
+
+func (l *lazyRender) visibility(visible bool) {
+	switch {
+	case visible && !l.rendered:
+		l.rendered = true
+		l.render()
+	case !visible && l.rendered && l.unbindOnLeave:
+		l.rendered = false
+		l.unrender()
+	}
+}
+
+// LazyBinder is a binder that defers the binding of the element's contents until
+// the element enters the viewport, to speed up long pages and lists.
+// Its value is a bool telling whether the contents are unbound (torn down and
+// reset to the template) when the element leaves the viewport again.
+// It takes the root margin as extra dash args, "0px" by default.
+//
+// Usage:
+//	bind-lazy="UnbindOnLeave"
+//	bind-lazy-200px="UnbindOnLeave"
+//	bind-lazy-200px-0px="UnbindOnLeave"
+// Example:
+//	<div bind-lazy-300px="true"><p bind-html="Post.Body"></p></div>
+type LazyBinder struct {
+	BaseBinder
+	lazy *lazyRender
+	stop func()
+}
+
+// lazyUnbind returns whether the contents should be unbound when leaving the viewport
+func lazyUnbind(d DomBind) bool {
+	if d.Value == nil {
+		return false
+	}
+
+	unbind, ok := d.Value.(bool)
+	if !ok {
+		d.Panic(fmt.Sprintf("Wrong type %v for the lazy binder, must be a bool.", reflect.TypeOf(d.Value)))
+	}
+	return unbind
+}
+
+func (b *LazyBinder) Bind(d DomBind) {
+	template := d.Elem.Contents().Clone()
+	b.lazy = &lazyRender{
+		unbindOnLeave: lazyUnbind(d),
+		render: func() {
+			d.bind(d.Elem, nil, d.once, false)
+		},
+		unrender: func() {
+			d.binding.Teardown(d.Elem.Children("*"))
+			d.Elem.Empty().Append(template.Clone())
+		},
+	}
+
+	rootMargin := "0px"
+	if len(d.Args) > 0 {
+		rootMargin = strings.Join(d.Args, " ")
+	}
+	b.stop = d.binding.viewport.Observe(d.Elem, rootMargin, b.lazy.visibility)
+}
+
+func (b *LazyBinder) Update(d DomBind) {
+	b.lazy.unbindOnLeave = lazyUnbind(d)
+}
+
+func (b *LazyBinder) Teardown(d DomBind) {
+	if b.stop != nil {
+		b.stop()
+		b.stop = nil
+	}
+}
+
+func (b *LazyBinder) BindInstance() DomBinder { return new(LazyBinder) }
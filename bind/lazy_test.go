@@ -0,0 +1,86 @@
+package bind
+
+import (
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+type fakeObserver struct {
+	rootMargin string
+	fn         func(bool)
+	stopped    bool
+}
+
+func (o *fakeObserver) Observe(elem jq.JQuery, rootMargin string, fn func(visible bool)) func() {
+	o.rootMargin, o.fn = rootMargin, fn
+	return func() {
+		o.stopped = true
+	}
+}
+
+func TestLazyRender(t *testing.T) {
+	obs := &fakeObserver{}
+	rendered, unrendered := 0, 0
+	l := &lazyRender{
+		unbindOnLeave: true,
+		render:        func() { rendered++ },
+		unrender:      func() { unrendered++ },
+	}
+	stop := obs.Observe(jq.JQuery{}, "200px", l.visibility)
+
+	obs.fn(false)
+	if rendered != 0 {
+		t.Fatalf("Expected the binding to be deferred until the intersection.")
+	}
+	obs.fn(true)
+	obs.fn(true)
+	if rendered != 1 {
+		t.Errorf("Expected the contents to be bound once on intersection, got %v.", rendered)
+	}
+	obs.fn(false)
+	if unrendered != 1 {
+		t.Errorf("Expected the contents to be unbound when leaving the viewport.")
+	}
+	obs.fn(true)
+	if rendered != 2 {
+		t.Errorf("Expected the contents to be bound again on reentering.")
+	}
+
+	l.unbindOnLeave = false
+	obs.fn(false)
+	if unrendered != 1 {
+		t.Errorf("Expected the contents to be kept.")
+	}
+
+	stop()
+	if !obs.stopped {
+		t.Errorf("Expected the observation to be stopped.")
+	}
+}
+
+func TestLazyBinder(t *testing.T) {
+	obs := &fakeObserver{}
+	b := NewBindEngine(nil)
+	b.SetViewportObserver(obs)
+
+	lb := new(LazyBinder)
+	d := DomBind{Elem: jq.JQuery{Length: 1}, Value: true, Args: []string{"100px", "0px"}, binding: b, scope: b.scope}
+	lb.Bind(d)
+	if obs.rootMargin != "100px 0px" || obs.fn == nil {
+		t.Fatalf("Expected the element to be observed with the root margin, got %q.", obs.rootMargin)
+	}
+	if lb.lazy.rendered || !lb.lazy.unbindOnLeave {
+		t.Errorf("Expected the contents to be deferred, to be unbound on leave.")
+	}
+
+	obs.fn(true)
+	if !lb.lazy.rendered {
+		t.Errorf("Expected the contents to be rendered on intersection.")
+	}
+
+	lb.Teardown(d)
+	if !obs.stopped {
+		t.Errorf("Expected the observation to be stopped on teardown.")
+	}
+}
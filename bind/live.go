@@ -0,0 +1,99 @@
+package bind
+
+import (
+	"fmt"
+	"time"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+// LiveRegionDelay is how long a live region stays empty before a message
+// identical to the previous one is set again, so that it's announced again
+const LiveRegionDelay = 100 * time.Millisecond
+
+// liveAnnouncer sets the messages of a live region. Screen readers only announce
+// changes, so a message identical to the previous one is announced again by
+// clearing the region and setting the message after LiveRegionDelay.
+type liveAnnouncer struct {
+	region  jq.JQuery
+	browser browser
+	text    string
+	// seq is incremented by each message, a pending reset is dropped
+	// when a newer message has been set
+	seq int
+}
+
+func (a *liveAnnouncer) announce(text string) {
+	a.seq++
+	if text != a.text || text == "" {
+		a.text = text
+		a.region.SetText(text)
+		return
+	}
+
+	seq := a.seq
+	a.region.SetText("")
+	a.browser.after(LiveRegionDelay, func() {
+		if seq == a.seq {
+			a.region.SetText(text)
+		}
+	})
+}
+
+// livePoliteness returns the aria-live value given by the dash args, "polite" by default
+func livePoliteness(args []string) (string, error) {
+	if len(args) == 0 {
+		return "polite", nil
+	}
+
+	switch args[0] {
+	case "polite", "assertive", "off":
+		if len(args) == 1 {
+			return args[0], nil
+		}
+	}
+	return "", fmt.Errorf(`Invalid politeness "%v" for the live binder, must be "polite", "assertive" or "off".`, args)
+}
+
+// setupLiveRegion sets the aria attributes of the live region,
+// with the politeness given by the dash args
+func setupLiveRegion(region jq.JQuery, args []string) error {
+	politeness, err := livePoliteness(args)
+	if err != nil {
+		return err
+	}
+
+	region.SetAttr("aria-live", politeness)
+	region.SetAttr("aria-atomic", "true")
+	return nil
+}
+
+// LiveBinder is a 1-way binder that sets the text of an aria-live region, so that
+// screen readers announce the messages, like the status of a form submission.
+// It sets the aria-live and aria-atomic attributes of the element. A message identical
+// to the previous one is announced again, the region is briefly cleared for that.
+// It takes the politeness as an optional dash arg: "polite" (the default), "assertive"
+// for urgent messages that interrupt the user, or "off".
+//
+// Usage:
+//	bind-live="Expression"
+//	bind-live-assertive="Expression"
+// Example:
+//	<p class="sr-only" bind-live="Cart.Status"></p>
+type LiveBinder struct {
+	BaseBinder
+	announcer *liveAnnouncer
+}
+
+func (b *LiveBinder) Bind(d DomBind) {
+	if err := setupLiveRegion(d.Elem, d.Args); err != nil {
+		d.Panic(err.Error())
+	}
+	b.announcer = &liveAnnouncer{region: d.Elem, browser: d.binding.browser}
+}
+
+func (b *LiveBinder) Update(d DomBind) {
+	b.announcer.announce(d.ValueString())
+}
+
+func (b *LiveBinder) BindInstance() DomBinder { return new(LiveBinder) }
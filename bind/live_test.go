@@ -0,0 +1,79 @@
+package bind
+
+import (
+	"testing"
+)
+
+type testCheckout struct {
+	Status string
+}
+
+func TestLiveBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	br := newFakeBrowser()
+	b.browser = br
+	model := &testCheckout{"Saving..."}
+	elem := gJQ(`<div><p bind-live="Status"></p><p class="urgent" bind-live-assertive="Status"></p></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	region := elem.Find("p").First()
+	if region.Attr("aria-live") != "polite" || region.Attr("aria-atomic") != "true" ||
+		elem.Find(".urgent").Attr("aria-live") != "assertive" {
+		t.Errorf("Expected the live regions to be set up, got %v.", elem.Html())
+	}
+	if region.Text() != "Saving..." {
+		t.Errorf("Expected the message to be set, got %q.", region.Text())
+	}
+
+	model.Status = "Saved."
+	w.change()
+	if region.Text() != "Saved." || br.pending() != 0 {
+		t.Errorf("Expected a new message to be set right away, got %q.", region.Text())
+	}
+
+	// the same message again
+	w.change()
+	if region.Text() != "" {
+		t.Fatalf("Expected the region to be cleared, got %q.", region.Text())
+	}
+	br.advance(LiveRegionDelay)
+	if region.Text() != "Saved." {
+		t.Errorf("Expected the message to be set again, got %q.", region.Text())
+	}
+
+	// a newer message drops the pending reset
+	w.change()
+	model.Status = "Error."
+	w.change()
+	br.advance(LiveRegionDelay)
+	if region.Text() != "Error." {
+		t.Errorf("Expected the newer message to be kept, got %q.", region.Text())
+	}
+}
+
+func TestLiveRegionSetup(t *testing.T) {
+	tests := map[string][]string{
+		"polite":    nil,
+		"assertive": []string{"assertive"},
+		"off":       []string{"off"},
+	}
+	for expected, args := range tests {
+		region := gJQ("<p></p>")
+		if err := setupLiveRegion(region, args); err != nil {
+			t.Errorf("Unexpected error for %v: %v", args, err)
+		}
+		if region.Attr("aria-live") != expected || region.Attr("aria-atomic") != "true" {
+			t.Errorf("%v: expected aria-live to be %v, got %v.", args, expected, region.Attr("aria-live"))
+		}
+	}
+
+	for _, args := range [][]string{{"rude"}, {"polite", "assertive"}} {
+		region := gJQ("<p></p>")
+		if err := setupLiveRegion(region, args); err == nil || region.Is("[aria-live]") {
+			t.Errorf("Expected an error for %v.", args)
+		}
+	}
+}
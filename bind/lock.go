@@ -0,0 +1,56 @@
+package bind
+
+import (
+	"reflect"
+)
+
+// ReadLocker is implemented by the models that are mutated from other goroutines,
+// like a model embedding a sync.RWMutex. The read locks of the models of a scope are
+// taken while a bound expression is evaluated, when the bind is made and each time
+// it's reevaluated after a change of the model, and released before the binders
+// update the elements. The model must take the write lock while it's mutated, and
+// must not hold it while calling into the binding (like a template function would).
+type ReadLocker interface {
+	RLock()
+	RUnlock()
+}
+
+// readLock takes the read locks of the models of the scope that implement ReadLocker,
+// and returns the function that releases them
+func (s *scope) readLock() (unlock func()) {
+	lockers := make([]ReadLocker, 0)
+	seen := make(map[uintptr]bool)
+	for _, st := range s.symTables {
+		mst, ok := st.(modelSymbolTable)
+		if !ok || !mst.model.IsValid() || !mst.model.CanInterface() {
+			continue
+		}
+
+		locker, ok := mst.model.Interface().(ReadLocker)
+		if !ok {
+			continue
+		}
+		if mst.model.Kind() == reflect.Ptr {
+			if seen[mst.model.Pointer()] {
+				continue
+			}
+			seen[mst.model.Pointer()] = true
+		}
+
+		locker.RLock()
+		lockers = append(lockers, locker)
+	}
+
+	return func() {
+		for i := len(lockers) - 1; i >= 0; i-- {
+			lockers[i].RUnlock()
+		}
+	}
+}
+
+// evaluateLocked evaluates the expression while holding the read locks of the models
+func (b *bindScope) evaluateLocked(root *expr) (v reflect.Value, blist []bindable, err error) {
+	unlock := b.scope.readLock()
+	defer unlock()
+	return b.evaluateRec(root)
+}
@@ -0,0 +1,48 @@
+package bind
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testLockedCounter struct {
+	Count int
+	calls []string
+}
+
+func (c *testLockedCounter) RLock()   { c.calls = append(c.calls, "lock") }
+func (c *testLockedCounter) RUnlock() { c.calls = append(c.calls, "unlock") }
+
+func (c *testLockedCounter) Doubled() int {
+	c.calls = append(c.calls, "eval")
+	return c.Count * 2
+}
+
+func TestReadLocker(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testLockedCounter{Count: 2}
+	bs := &bindScope{newModelScope(model)}
+	bs.scope.merge(b.scope)
+
+	root, _, v, err := bs.evaluate("Doubled()")
+	if err != nil || v != 4 {
+		t.Fatalf("Unexpected result %v (%v).", v, err)
+	}
+	if expected := []string{"lock", "eval", "unlock"}; !reflect.DeepEqual(model.calls, expected) {
+		t.Errorf("Expected the evaluation to be bracketed by the lock, got %v.", model.calls)
+	}
+
+	model.calls = nil
+	nested := &bindScope{newModelScope(model)}
+	nested.scope.merge(bs.scope)
+	nested.evaluateLocked(root)
+	if expected := []string{"lock", "eval", "unlock"}; !reflect.DeepEqual(model.calls, expected) {
+		t.Errorf("Expected the model to be locked once per evaluation, got %v.", model.calls)
+	}
+
+	plain := &bindScope{newModelScope(&testTodo{})}
+	plain.scope.merge(b.scope)
+	if _, _, _, err := plain.evaluate("Title"); err != nil {
+		t.Errorf("Unexpected error for a model without lock: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package bind
+
+import (
+	"html"
+)
+
+// MarkdownRenderer converts markdown to html for the markdown binder,
+// the app provides the implementation with Binding.SetMarkdownRenderer
+type MarkdownRenderer interface {
+	Render(source string) string
+}
+
+// SetMarkdownRenderer sets the renderer used by the markdown binder
+func (b *Binding) SetMarkdownRenderer(r MarkdownRenderer) {
+	b.markdown = r
+}
+
+// renderMarkdown renders the source with the markdown renderer, the source is
+// only escaped if there's none
+func (b *Binding) renderMarkdown(source string) string {
+	if b.markdown == nil {
+		return html.EscapeString(source)
+	}
+
+	return b.markdown.Render(source)
+}
+
+// MarkdownBinder is a 1-way binder that renders the value, a markdown source, to html
+// and sets it as the element's content. The renderer is set with Binding.SetMarkdownRenderer,
+// without one the source is inserted as escaped text.
+// With the "bind" dash arg, the bind attributes in the resulting html are bound too,
+// in the scope of the element.
+//
+// Usage:
+//	bind-markdown="Expression"
+// Or
+//	bind-markdown-bind="Expression"
+type MarkdownBinder struct {
+	BaseBinder
+	rebind bool
+}
+
+func (b *MarkdownBinder) Bind(d DomBind) {
+	for _, arg := range d.Args {
+		if arg != "bind" {
+			d.Panic(`Unknown dash arg "` + arg + `".`)
+		}
+		b.rebind = true
+	}
+}
+
+func (b *MarkdownBinder) Update(d DomBind) {
+	content := d.binding.renderMarkdown(d.ValueString())
+	if !b.rebind {
+		d.Elem.SetHtml(content)
+		return
+	}
+
+	d.binding.Teardown(d.Elem.Children("*"))
+	d.Elem.SetHtml(content)
+	d.bind(d.Elem, nil, d.once, false)
+}
+
+func (b *MarkdownBinder) BindInstance() DomBinder { return new(MarkdownBinder) }
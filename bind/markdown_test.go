@@ -0,0 +1,37 @@
+package bind
+
+import (
+	"strings"
+	"testing"
+)
+
+type testMarkdown struct{}
+
+func (r testMarkdown) Render(source string) string {
+	return "<p>" + strings.Replace(strings.Replace(source, "**", "<strong>", 1), "**", "</strong>", 1) + "</p>"
+}
+
+func TestMarkdown(t *testing.T) {
+	b := NewBindEngine(nil)
+	source := "Some **bold** <text>"
+	if s := b.renderMarkdown(source); s != "Some **bold** &lt;text&gt;" {
+		t.Errorf("Expected the source to be escaped without a renderer, got %q.", s)
+	}
+
+	b.SetMarkdownRenderer(testMarkdown{})
+	if s := b.renderMarkdown(source); s != "<p>Some <strong>bold</strong> <text></p>" {
+		t.Errorf("Expected the rendered html, got %q.", s)
+	}
+
+	mb := (&MarkdownBinder{}).BindInstance().(*MarkdownBinder)
+	mb.Bind(DomBind{Args: []string{"bind"}, binding: b})
+	if !mb.rebind {
+		t.Errorf("Expected the bind dash arg to enable rebinding.")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for an unknown dash arg.")
+		}
+	}()
+	mb.Bind(DomBind{Args: []string{"raw"}, binding: b})
+}
@@ -0,0 +1,110 @@
+package bind
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// maxFractionDigits is the maximum number of decimals of formatted numbers,
+// the default of javascript's toLocaleString
+const maxFractionDigits = 3
+
+type numberSeparators struct {
+	group   string
+	decimal string
+}
+
+// localeSeparators are the separators of the languages, used when the number
+// is not formatted by the browser. The default is English's.
+var localeSeparators = map[string]numberSeparators{
+	"en": {",", "."},
+	"de": {".", ","},
+	"es": {".", ","},
+	"id": {".", ","},
+	"it": {".", ","},
+	"nl": {".", ","},
+	"pt": {".", ","},
+	"vi": {".", ","},
+	"tr": {".", ","},
+	"fr": {" ", ","},
+	"ru": {" ", ","},
+	"pl": {" ", ","},
+	"cs": {" ", ","},
+	"sv": {" ", ","},
+	"fi": {" ", ","},
+	"nb": {" ", ","},
+}
+
+// formatNumber formats an integer or a float with grouping separators according to the
+// locale, like "1,234,567.5" for "en". It's the "number" helper, the locale is optional
+// and defaults to the browser's. The browser's toLocaleString is used when it's available.
+func formatNumber(n interface{}, locale ...string) string {
+	f, ok := toFloat(reflect.ValueOf(n))
+	if !ok {
+		panic(fmt.Sprintf("number helper: %v is not a number.", n))
+	}
+
+	loc := ""
+	if len(locale) > 0 {
+		loc = locale[0]
+	}
+
+	if js.Global != nil && !js.Global.Get("Number").IsUndefined() {
+		num := js.Global.Get("Number").New(f)
+		if loc == "" {
+			return num.Call("toLocaleString").Str()
+		}
+		return num.Call("toLocaleString", loc).Str()
+	}
+
+	return formatNumberLocale(f, loc)
+}
+
+// separatorsOf returns the separators of the locale's language, English's by default
+func separatorsOf(locale string) numberSeparators {
+	lang := strings.ToLower(strings.SplitN(strings.Replace(locale, "_", "-", -1), "-", 2)[0])
+	if seps, ok := localeSeparators[lang]; ok {
+		return seps
+	}
+	return localeSeparators["en"]
+}
+
+// formatNumberLocale is the Go implementation of the number formatting
+func formatNumberLocale(f float64, locale string) string {
+	seps := separatorsOf(locale)
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	s := strconv.FormatFloat(math.Abs(f), 'f', maxFractionDigits, 64)
+	intPart, fracPart := s, ""
+	if i := strings.Index(s, "."); i >= 0 {
+		intPart, fracPart = s[:i], strings.TrimRight(s[i+1:], "0")
+	}
+
+	result := groupDigits(intPart, seps.group)
+	if fracPart != "" {
+		result += seps.decimal + fracPart
+	}
+	if f < 0 && strings.Trim(result, "0., ") != "" {
+		result = "-" + result
+	}
+	return result
+}
+
+// groupDigits inserts the group separator between each group of 3 digits
+func groupDigits(digits string, sep string) string {
+	groups := make([]string, 0)
+	for len(digits) > 3 {
+		groups = append([]string{digits[len(digits)-3:]}, groups...)
+		digits = digits[:len(digits)-3]
+	}
+	groups = append([]string{digits}, groups...)
+	return strings.Join(groups, sep)
+}
@@ -0,0 +1,204 @@
+package bind
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+// groupChars returns the characters accepted as the group separator,
+// a space separator also accepts the other kinds of spaces
+func groupChars(group string) string {
+	if strings.TrimSpace(group) == "" {
+		return " \u00a0\u202f"
+	}
+	return group
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// splitNumberInput splits a number typed with the separators into its sign, its integer
+// digits without the group separators, and its fraction digits. ok is false if it's
+// not a number.
+func splitNumberInput(s string, seps numberSeparators) (neg bool, intPart, fracPart string, hasFrac, ok bool) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "-") {
+		neg, s = true, s[1:]
+	}
+
+	intPart = s
+	if i := strings.Index(s, seps.decimal); i >= 0 {
+		intPart, fracPart, hasFrac = s[:i], s[i+len(seps.decimal):], true
+	}
+	intPart = strings.Map(func(c rune) rune {
+		if strings.ContainsRune(groupChars(seps.group), c) {
+			return -1
+		}
+		return c
+	}, intPart)
+
+	ok = isDigits(intPart) && isDigits(fracPart)
+	return
+}
+
+// parseGroupedNumber converts a number typed with the separators to a plain number
+// string, like "1,234.5" to "1234.5". ok is false if it's empty or not a number.
+func parseGroupedNumber(s string, seps numberSeparators) (n string, ok bool) {
+	neg, intPart, fracPart, _, ok := splitNumberInput(s, seps)
+	if !ok || intPart == "" && fracPart == "" {
+		return "", false
+	}
+
+	if intPart == "" {
+		intPart = "0"
+	}
+	n = intPart
+	if fracPart != "" {
+		n += "." + fracPart
+	}
+	if neg {
+		n = "-" + n
+	}
+	return n, true
+}
+
+// regroupNumber groups the integer digits of a number being typed, the fraction
+// is kept as typed, so that typing a decimal separator or zeros isn't undone.
+// ok is false if it's not a number.
+func regroupNumber(s string, seps numberSeparators) (grouped string, ok bool) {
+	neg, intPart, fracPart, hasFrac, ok := splitNumberInput(s, seps)
+	if !ok {
+		return
+	}
+
+	grouped = groupDigits(intPart, seps.group)
+	if hasFrac {
+		grouped += seps.decimal + fracPart
+	}
+	if neg {
+		grouped = "-" + grouped
+	}
+	return grouped, true
+}
+
+// regroupCaret returns the position in the regrouped text that stands for the caret
+// position in the typed text: the caret stays after the same digit.
+func regroupCaret(typed string, caret int, regrouped string, group string) int {
+	chars := groupChars(group)
+	tr, rr := []rune(typed), []rune(regrouped)
+	if caret > len(tr) {
+		caret = len(tr)
+	}
+
+	n := 0
+	for _, c := range tr[:caret] {
+		if !strings.ContainsRune(chars, c) {
+			n++
+		}
+	}
+
+	pos := 0
+	for ; pos < len(rr) && n > 0; pos++ {
+		if !strings.ContainsRune(chars, rr[pos]) {
+			n--
+		}
+	}
+	return pos
+}
+
+// numberInputSync displays a number field with grouped digits in a text input,
+// and writes the typed numbers back to the model without the separators
+type numberInputSync struct {
+	input  jq.JQuery
+	locale string
+	update ModelUpdateFn
+}
+
+// typed groups the digits as the user types, keeping the caret after the same digit
+func (s *numberInputSync) typed() {
+	v := s.input.Val()
+	grouped, ok := regroupNumber(v, separatorsOf(s.locale))
+	if !ok || grouped == v {
+		return
+	}
+
+	input := s.input.Get(0)
+	caret := input.Get("selectionStart").Int()
+	s.input.SetVal(grouped)
+	caret = regroupCaret(v, caret, grouped, separatorsOf(s.locale).group)
+	input.Call("setSelectionRange", caret, caret)
+}
+
+// changed writes the typed number to the model, an input that is not a number
+// doesn't change the model
+func (s *numberInputSync) changed() {
+	if n, ok := parseGroupedNumber(s.input.Val(), separatorsOf(s.locale)); ok {
+		s.update(n)
+	}
+}
+
+// show displays the value with grouped digits. The input is left untouched if it
+// already holds the number, so that the text and the caret are kept while typing.
+func (s *numberInputSync) show(value interface{}) {
+	f, ok := toFloat(unwrapValue(reflect.ValueOf(value)))
+	if !ok {
+		s.input.SetVal(toString(value))
+		return
+	}
+
+	if n, ok := parseGroupedNumber(s.input.Val(), separatorsOf(s.locale)); ok {
+		if cur, err := strconv.ParseFloat(n, 64); err == nil && cur == f {
+			return
+		}
+	}
+	s.input.SetVal(formatNumberLocale(f, s.locale))
+}
+
+// NumberBinder is a 2-way binder for text inputs bound to number fields. The number is
+// displayed with grouped digits, like "1,234,567.5", and the digits are regrouped as
+// the user types. The input is parsed back ignoring the group separators when it
+// changes, an input that is not a number doesn't change the model.
+// It takes the locale of the separators as extra dash args, English by default.
+//
+// Usage:
+//	bind-number="Field"
+//	bind-number-de="Field"
+// Example:
+//	<input type="text" inputmode="decimal" bind-number="Price">
+type NumberBinder struct {
+	BaseBinder
+	sync *numberInputSync
+}
+
+func (b *NumberBinder) Watch(elem jq.JQuery, ufn ModelUpdateFn) {
+	b.sync = &numberInputSync{input: elem, update: ufn}
+	elem.On("input", func(evt jq.Event) {
+		b.sync.typed()
+	})
+	elem.On(jq.CHANGE, func(evt jq.Event) {
+		b.sync.changed()
+	})
+}
+
+func (b *NumberBinder) Bind(d DomBind) {
+	if b.sync == nil {
+		b.sync = &numberInputSync{input: d.Elem, update: func(string) {}}
+	}
+	b.sync.locale = strings.Join(d.Args, "-")
+}
+
+func (b *NumberBinder) Update(d DomBind) {
+	b.sync.show(d.Value)
+}
+
+func (b *NumberBinder) BindInstance() DomBinder { return new(NumberBinder) }
+func (b *NumberBinder) TwoWay()                 {}
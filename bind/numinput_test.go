@@ -0,0 +1,103 @@
+package bind
+
+import (
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+type testOrder struct {
+	Quantity int
+	Price    float64
+}
+
+func TestNumberInput(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	model := &testOrder{Quantity: 1234567, Price: 1234.5}
+	elem := gJQ(`<div><input type="text" bind-number="Quantity"><input class="price" type="text" bind-number-de="Price"></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	input := elem.Find("input").First()
+	if input.Val() != "1,234,567" || elem.Find(".price").Val() != "1.234,5" {
+		t.Errorf("Expected the numbers to be displayed with grouped digits, got %q and %q.", input.Val(), elem.Find(".price").Val())
+	}
+
+	// round trip
+	input.SetVal("1,234").Trigger(jq.CHANGE)
+	if model.Quantity != 1234 {
+		t.Errorf("Expected the separators to be ignored, got %v.", model.Quantity)
+	}
+	input.SetVal("")
+	w.change()
+	if input.Val() != "1,234" {
+		t.Errorf("Expected the number to be displayed with grouped digits, got %q.", input.Val())
+	}
+
+	for _, invalid := range []string{"12a4", "1.2.3", "", "-", "1e5"} {
+		input.SetVal(invalid).Trigger(jq.CHANGE)
+		if model.Quantity != 1234 {
+			t.Errorf("Expected %q not to change the model, got %v.", invalid, model.Quantity)
+		}
+	}
+
+	// the input is kept while it holds the number
+	input.SetVal("1234")
+	w.change()
+	if input.Val() != "1234" {
+		t.Errorf("Expected the input to be kept, got %q.", input.Val())
+	}
+
+	// typing regroups the digits and keeps the caret after the same digit
+	typeText := func(text string, caret int) int {
+		input.SetVal(text)
+		input.Get(0).Call("setSelectionRange", caret, caret)
+		input.Trigger("input")
+		return input.Get(0).Get("selectionStart").Int()
+	}
+	if caret := typeText("1,2345", 3); input.Val() != "12,345" || caret != 2 {
+		t.Errorf("Expected the digits to be regrouped with the caret after the 2, got %q at %v.", input.Val(), caret)
+	}
+	if caret := typeText("12345.", 6); input.Val() != "12,345." || caret != 7 {
+		t.Errorf("Expected the decimal separator to be kept, got %q at %v.", input.Val(), caret)
+	}
+	if model.Quantity != 1234 {
+		t.Errorf("Expected typing not to change the model before the input changes, got %v.", model.Quantity)
+	}
+
+	elem.Find(".price").SetVal("2.500,25").Trigger(jq.CHANGE)
+	if model.Price != 2500.25 {
+		t.Errorf("Expected the number to be parsed with the german separators, got %v.", model.Price)
+	}
+}
+
+func TestGroupedNumbers(t *testing.T) {
+	en, de, fr := separatorsOf("en-US"), separatorsOf("de"), separatorsOf("fr")
+	tests := []struct {
+		s        string
+		seps     numberSeparators
+		expected string
+		ok       bool
+	}{
+		{"1,234", en, "1234", true},
+		{" -1,234,567.25 ", en, "-1234567.25", true},
+		{".5", en, "0.5", true},
+		{"1.234,5", de, "1234.5", true},
+		{"1 234\u00a0567,5", fr, "1234567.5", true},
+		{"1,234", de, "1.234", true},
+		{"1.234.5", en, "", false},
+		{"12x", en, "", false},
+		{"", en, "", false},
+	}
+	for _, test := range tests {
+		if n, ok := parseGroupedNumber(test.s, test.seps); n != test.expected || ok != test.ok {
+			t.Errorf("%q: expected %q (%v), got %q (%v).", test.s, test.expected, test.ok, n, ok)
+		}
+	}
+
+	if s, ok := regroupNumber("-1234567,50", de); !ok || s != "-1.234.567,50" {
+		t.Errorf("Expected the integer digits to be regrouped, got %q (%v).", s, ok)
+	}
+}
@@ -0,0 +1,359 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var (
+	intType     = reflect.TypeOf(0)
+	float64Type = reflect.TypeOf(float64(0))
+	stringType  = reflect.TypeOf("")
+	boolType    = reflect.TypeOf(true)
+)
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isUintKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// toInt64 converts an integer value to int64
+func toInt64(v reflect.Value) int64 {
+	if isUintKind(v.Kind()) {
+		return int64(v.Uint())
+	}
+	return v.Int()
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// arithmeticType returns the type of the result of an arithmetic operation on operands
+// of the given types: their type if it's the same, int for mixed integers and
+// float64 for other mixed numbers. "+" also concatenates strings.
+func arithmeticType(op string, x, y reflect.Type) (reflect.Type, bool) {
+	xk, yk := x.Kind(), y.Kind()
+	if xk == reflect.String && yk == reflect.String {
+		if op != "+" {
+			return nil, false
+		}
+		if x == y {
+			return x, true
+		}
+		return stringType, true
+	}
+
+	xi, yi := isIntKind(xk), isIntKind(yk)
+	if !(xi || isFloatKind(xk)) || !(yi || isFloatKind(yk)) {
+		return nil, false
+	}
+	if op == "%" && !(xi && yi) {
+		return nil, false
+	}
+
+	switch {
+	case x == y:
+		return x, true
+	case xi && yi:
+		return intType, true
+	}
+	return float64Type, true
+}
+
+// operatorType returns the type of the result of the operator for operands of the given types
+func operatorType(op string, operands []reflect.Type) (typ reflect.Type, err error) {
+	invalid := func() {
+		err = fmt.Errorf(`Invalid operand types %v for operator "%v"`, operands, op)
+	}
+
+	if len(operands) == 1 {
+		x := operands[0]
+		switch {
+		case op == "!" && x.Kind() == reflect.Bool:
+			typ = boolType
+		case op == "-" && (isIntKind(x.Kind()) || isFloatKind(x.Kind())):
+			typ = x
+		default:
+			invalid()
+		}
+		return
+	}
+
+	x, y := operands[0], operands[1]
+	switch op {
+	case "&&":
+		if x.Kind() != reflect.Bool || y.Kind() != reflect.Bool {
+			invalid()
+		}
+		typ = boolType
+	case "||":
+		switch {
+		case x.Kind() == reflect.Bool && y.Kind() == reflect.Bool:
+			typ = boolType
+		case x.Kind() == reflect.Bool:
+			invalid()
+		case x == y:
+			typ = x
+		}
+	case "==", "!=", "<", "<=", ">", ">=":
+		typ = boolType
+	default:
+		var ok bool
+		if typ, ok = arithmeticType(op, x, y); !ok {
+			invalid()
+		}
+	}
+	return
+}
+
+// unwrapValue returns the concrete value held by an interface value
+func unwrapValue(v reflect.Value) reflect.Value {
+	if v.IsValid() && v.Kind() == reflect.Interface && !v.IsNil() {
+		return v.Elem()
+	}
+	return v
+}
+
+// isFalsy tells whether the value is false, zero, an empty string or nil,
+// like the falsy values of javascript
+func isFalsy(v reflect.Value) bool {
+	switch {
+	case !v.IsValid():
+		return true
+	case v.Kind() == reflect.Bool:
+		return !v.Bool()
+	case v.Kind() == reflect.String:
+		return v.Len() == 0
+	}
+	if f, isNumber := toFloat(v); isNumber {
+		return f == 0
+	}
+	return isNilValue(v)
+}
+
+// evaluateOp evaluates an operation. The operands of "&&" and "||" are all evaluated so
+// that the fields they use are watched, but the right operand's errors are ignored when
+// the left operand alone decides the result, so that "HasUser && User.Name" works.
+// With a left operand that is not a bool, "||" results in the left operand unless it's
+// falsy, and in the right operand otherwise, like "Name || `Anonymous`".
+func (b *bindScope) evaluateOp(e *expr) (v reflect.Value, blist []bindable, err error) {
+	blist = make([]bindable, 0)
+	args := make([]reflect.Value, len(e.args))
+	errs := make([]error, len(e.args))
+	for i, arg := range e.args {
+		var cblist []bindable
+		args[i], cblist, errs[i] = b.evaluateRec(arg)
+		args[i] = unwrapValue(args[i])
+		blist = append(blist, cblist...)
+	}
+
+	if errs[0] != nil {
+		err = errs[0]
+		return
+	}
+
+	if len(args) == 2 && e.name == "||" && args[0].Kind() != reflect.Bool {
+		if !isFalsy(args[0]) {
+			v = args[0]
+			return
+		}
+		v, err = args[1], errs[1]
+		return
+	}
+
+	if len(args) == 2 && (e.name == "&&" || e.name == "||") {
+		if args[0].Kind() != reflect.Bool {
+			err = fmt.Errorf(`The operands of "%v" must be bool, got %v`, e.name, args[0].Type())
+			return
+		}
+		if left := args[0].Bool(); left != (e.name == "&&") {
+			v = reflect.ValueOf(left)
+			return
+		}
+	}
+
+	for _, er := range errs {
+		if er != nil {
+			err = er
+			return
+		}
+	}
+
+	v, err = applyOperator(e.name, args)
+	return
+}
+
+// applyOperator applies the operator to the evaluated operands
+func applyOperator(op string, args []reflect.Value) (v reflect.Value, err error) {
+	types := make([]reflect.Type, len(args))
+	for i, arg := range args {
+		if !arg.IsValid() {
+			if op == "==" || op == "!=" {
+				return reflect.ValueOf(valuesEqual(args[0], args[1]) == (op == "==")), nil
+			}
+			err = fmt.Errorf(`Invalid nil operand for operator "%v"`, op)
+			return
+		}
+		types[i] = arg.Type()
+	}
+
+	typ, err := operatorType(op, types)
+	if err != nil {
+		return
+	}
+
+	if len(args) == 1 {
+		x := args[0]
+		switch {
+		case op == "!":
+			v = reflect.ValueOf(!x.Bool())
+		case isFloatKind(x.Kind()):
+			v = reflect.ValueOf(-x.Float()).Convert(typ)
+		default:
+			v = reflect.ValueOf(-toInt64(x)).Convert(typ)
+		}
+		return
+	}
+
+	x, y := args[0], args[1]
+	switch op {
+	case "&&":
+		v = reflect.ValueOf(x.Bool() && y.Bool())
+	case "||":
+		v = reflect.ValueOf(x.Bool() || y.Bool())
+	case "==", "!=":
+		v = reflect.ValueOf(valuesEqual(x, y) == (op == "=="))
+	case "<", "<=", ">", ">=":
+		var cmp int
+		cmp, err = compareValues(x, y)
+		if err != nil {
+			err = fmt.Errorf(`Operator "%v": %v`, op, err.Error())
+			return
+		}
+		v = reflect.ValueOf(map[string]bool{
+			"<":  cmp < 0,
+			"<=": cmp <= 0,
+			">":  cmp > 0,
+			">=": cmp >= 0,
+		}[op])
+	default:
+		v, err = arithmetic(op, x, y, typ)
+	}
+
+	return
+}
+
+func arithmetic(op string, x, y reflect.Value, typ reflect.Type) (v reflect.Value, err error) {
+	switch {
+	case typ.Kind() == reflect.String:
+		v = reflect.ValueOf(x.String() + y.String()).Convert(typ)
+		return
+	case isIntKind(typ.Kind()):
+		m, n := toInt64(x), toInt64(y)
+		var r int64
+		switch op {
+		case "+":
+			r = m + n
+		case "-":
+			r = m - n
+		case "*":
+			r = m * n
+		case "/", "%":
+			if n == 0 {
+				err = fmt.Errorf("Division by zero")
+				return
+			}
+			if op == "/" {
+				r = m / n
+			} else {
+				r = m % n
+			}
+		}
+		v = reflect.ValueOf(r).Convert(typ)
+		return
+	}
+
+	a, _ := toFloat(x)
+	b, _ := toFloat(y)
+	var r float64
+	switch op {
+	case "+":
+		r = a + b
+	case "-":
+		r = a - b
+	case "*":
+		r = a * b
+	case "/":
+		if b == 0 {
+			err = fmt.Errorf("Division by zero")
+			return
+		}
+		r = a / b
+	}
+	v = reflect.ValueOf(r).Convert(typ)
+	return
+}
+
+// valuesEqual compares two values, numbers are compared by value regardless
+// of their types, and so are strings of different string types
+func valuesEqual(x, y reflect.Value) bool {
+	if !x.IsValid() || !y.IsValid() {
+		return (!x.IsValid() || isNilValue(x)) && (!y.IsValid() || isNilValue(y))
+	}
+
+	if a, ok := toFloat(x); ok {
+		b, ok := toFloat(y)
+		return ok && a == b
+	}
+
+	if x.Kind() == reflect.String && y.Kind() == reflect.String {
+		return x.String() == y.String()
+	}
+
+	if x.Kind() == reflect.Bool && y.Kind() == reflect.Bool {
+		return x.Bool() == y.Bool()
+	}
+
+	return reflect.DeepEqual(x.Interface(), y.Interface())
+}
+
+// compareValues compares two numbers or two strings, it returns the sign of the difference
+func compareValues(x, y reflect.Value) (cmp int, err error) {
+	a, aok := toFloat(x)
+	b, bok := toFloat(y)
+	switch {
+	case aok && bok:
+	case x.Kind() == reflect.String && y.Kind() == reflect.String:
+		switch xs, ys := x.String(), y.String(); {
+		case xs < ys:
+			cmp = -1
+		case xs > ys:
+			cmp = 1
+		}
+		return
+	default:
+		err = fmt.Errorf(`Cannot compare values of types "%v" and "%v"`, x.Type(), y.Type())
+		return
+	}
+
+	switch {
+	case a < b:
+		cmp = -1
+	case a > b:
+		cmp = 1
+	}
+	return
+}
@@ -0,0 +1,202 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Pager is a paginated data source, for long lists that are loaded on demand
+type Pager interface {
+	// Page returns the items of the page n, counting from 0, as a slice.
+	// It's called in a goroutine, so it may block on a server request.
+	Page(n int) (interface{}, error)
+}
+
+// pagedEntry is a page of a PagedList, loaded or being loaded
+type pagedEntry struct {
+	state AsyncState
+	items interface{}
+}
+
+// PagedList is the current page of a Pager, it's bound with the paged binder.
+// The pages are loaded when they're navigated to, and kept once loaded.
+// It embeds the AsyncState of the current page, so the loading and the error
+// of the page can be displayed with the loading binder.
+type PagedList struct {
+	AsyncState
+	Source Pager
+	// Current is the number of the current page
+	Current int
+
+	pages        map[int]*pagedEntry
+	listeners    map[int]func()
+	lastListener int
+	// run runs the loading of a page, in a goroutine by default
+	run func(func())
+}
+
+// Goto navigates to the page n, loading it if it hasn't been loaded yet
+func (l *PagedList) Goto(n int) {
+	if n < 0 {
+		n = 0
+	}
+	if l.pages == nil {
+		l.pages = make(map[int]*pagedEntry)
+	}
+
+	l.Current = n
+	entry, ok := l.pages[n]
+	if !ok || entry.state.Status == AsyncFailed {
+		entry = &pagedEntry{}
+		entry.state.Start()
+		l.pages[n] = entry
+		l.load(n, entry)
+	}
+	l.AsyncState = entry.state
+	l.changed()
+}
+
+// Next navigates to the page after the current one
+func (l *PagedList) Next() {
+	l.Goto(l.Current + 1)
+}
+
+// Prev navigates to the page before the current one
+func (l *PagedList) Prev() {
+	l.Goto(l.Current - 1)
+}
+
+// Reload drops the loaded pages and loads the current one again
+func (l *PagedList) Reload() {
+	l.pages = nil
+	l.Goto(l.Current)
+}
+
+// Items returns the items of the current page, nil if it's not loaded
+func (l *PagedList) Items() interface{} {
+	if entry, ok := l.pages[l.Current]; ok {
+		return entry.items
+	}
+	return nil
+}
+
+func (l *PagedList) load(n int, entry *pagedEntry) {
+	run := l.run
+	if run == nil {
+		run = func(fn func()) { go fn() }
+	}
+
+	run(func() {
+		items, err := l.Source.Page(n)
+		if err != nil {
+			entry.state.Fail(err)
+		} else {
+			entry.items = items
+			entry.state.Resolve()
+		}
+
+		// the page may have been dropped by Reload, or navigated away from
+		if l.pages[n] != entry || l.Current != n {
+			return
+		}
+		l.AsyncState = entry.state
+		l.changed()
+	})
+}
+
+func (l *PagedList) changed() {
+	for _, fn := range l.listeners {
+		fn()
+	}
+}
+
+// onChange registers a function called when the current page or its state changes,
+// it returns the function that unregisters it
+func (l *PagedList) onChange(fn func()) (stop func()) {
+	if l.listeners == nil {
+		l.listeners = make(map[int]func())
+	}
+	l.lastListener++
+	id := l.lastListener
+	l.listeners[id] = fn
+	return func() {
+		delete(l.listeners, id)
+	}
+}
+
+// pageWindow returns the items of the current page to render, empty while
+// the page is loading or if it failed
+func pageWindow(l *PagedList) interface{} {
+	if items := l.Items(); items != nil {
+		if kind := reflect.TypeOf(items).Kind(); kind != reflect.Slice && kind != reflect.Array {
+			panic(fmt.Sprintf("Wrong kind %v of page items, must be a slice.", kind))
+		}
+		return items
+	}
+	return []interface{}{}
+}
+
+// PagedBinder is a 1-way binder that repeats an element for the items of the current
+// page of a *PagedList, like the each binder does for a slice. The first page is loaded
+// when it's bound if no page has been navigated to, the navigation is done with the methods
+// of PagedList. While a page is loading or if it failed, no item is rendered.
+// It takes the same dash args and outputs as the each binder.
+//
+// Usage:
+//	bind-paged="PagedList -> outputKey, outputValue"
+// Example:
+//	<li bind-paged="Results -> i, result"><% result.Title %></li>
+//	<p bind-loading="Results"></p>
+//	<button bind-on-click="Results.Next()">Next</button>
+type PagedBinder struct {
+	BaseBinder
+	each *EachBinder
+	list *PagedList
+	stop func()
+}
+
+func pagedList(d DomBind) *PagedList {
+	l, ok := d.Value.(*PagedList)
+	if !ok {
+		d.Panic(fmt.Sprintf("Wrong type %v for the paged binder, must be a *PagedList.", reflect.TypeOf(d.Value)))
+	}
+	return l
+}
+
+func (b *PagedBinder) Bind(d DomBind) {
+	d.Elem.RemoveAttr(BindPrefix + strings.Join(append([]string{"paged"}, d.Args...), "-"))
+	b.each = new(EachBinder)
+	d.Value = []interface{}{}
+	b.each.Bind(d)
+}
+
+func (b *PagedBinder) Update(d DomBind) {
+	l := pagedList(d)
+	if l != b.list {
+		b.Teardown(d)
+		b.list = l
+		b.stop = l.onChange(func() {
+			b.render(d)
+		})
+		if l.pages == nil {
+			l.Goto(l.Current)
+			return
+		}
+	}
+	b.render(d)
+}
+
+func (b *PagedBinder) render(d DomBind) {
+	d.Value = pageWindow(b.list)
+	b.each.Update(d)
+}
+
+func (b *PagedBinder) Teardown(d DomBind) {
+	if b.stop != nil {
+		b.stop()
+		b.stop = nil
+	}
+}
+
+func (b *PagedBinder) BindInstance() DomBinder { return new(PagedBinder) }
@@ -0,0 +1,82 @@
+package bind
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type fakePager struct {
+	pages [][]string
+	calls []int
+}
+
+func (p *fakePager) Page(n int) (interface{}, error) {
+	p.calls = append(p.calls, n)
+	if n >= len(p.pages) {
+		return nil, errors.New("no such page")
+	}
+	return p.pages[n], nil
+}
+
+func TestPagedList(t *testing.T) {
+	pager := &fakePager{pages: [][]string{{"a", "b"}, {"c", "d"}, {"e"}}}
+	// the loads are run when flushed, like responses arriving later
+	pending := []func(){}
+	flush := func() {
+		for len(pending) > 0 {
+			fn := pending[0]
+			pending = pending[1:]
+			fn()
+		}
+	}
+	l := &PagedList{Source: pager, run: func(fn func()) { pending = append(pending, fn) }}
+	renders := 0
+	stop := l.onChange(func() { renders++ })
+
+	l.Goto(0)
+	if l.Status != AsyncPending || len(pageWindow(l).([]interface{})) != 0 {
+		t.Fatalf("Expected an empty window while the page is loading, got %v.", pageWindow(l))
+	}
+	flush()
+	if l.Status != AsyncResolved || !reflect.DeepEqual(pageWindow(l), []string{"a", "b"}) {
+		t.Errorf("Expected the first page to be rendered, got %v (%v).", pageWindow(l), l.Status)
+	}
+
+	l.Next()
+	l.Next()
+	flush()
+	if l.Current != 2 || !reflect.DeepEqual(pageWindow(l), []string{"e"}) {
+		t.Errorf("Expected the last page navigated to to be rendered, got %v.", pageWindow(l))
+	}
+
+	l.Prev()
+	l.Prev()
+	if !reflect.DeepEqual(pageWindow(l), []string{"a", "b"}) || len(pending) != 0 {
+		t.Errorf("Expected the loaded pages to be kept, got %v.", pageWindow(l))
+	}
+	if !reflect.DeepEqual(pager.calls, []int{0, 1, 2}) {
+		t.Errorf("Expected each page to be fetched once, got %v.", pager.calls)
+	}
+	if renders != 7 {
+		t.Errorf("Expected 7 renders, got %v.", renders)
+	}
+
+	l.Goto(5)
+	flush()
+	if l.Status != AsyncFailed || l.Error != "no such page" || len(pageWindow(l).([]interface{})) != 0 {
+		t.Errorf("Expected the page to fail with an empty window, got %v %q.", l.Status, l.Error)
+	}
+	l.Goto(5)
+	if l.Status != AsyncPending {
+		t.Errorf("Expected a failed page to be loaded again.")
+	}
+	flush()
+
+	stop()
+	l.Reload()
+	flush()
+	if renders != 11 || !reflect.DeepEqual(pager.calls, []int{0, 1, 2, 5, 5, 5}) {
+		t.Errorf("Expected the reload to fetch the page again without notifying, got %v renders and %v.", renders, pager.calls)
+	}
+}
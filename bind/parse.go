@@ -1,7 +1,6 @@
 package bind
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -13,6 +12,7 @@ type TokenType int
 const (
 	ExprToken TokenType = iota
 	PuncToken
+	OpToken
 )
 
 type ExprType int
@@ -20,143 +20,545 @@ type ExprType int
 const (
 	ValueExpr ExprType = iota
 	CallExpr
+	OpExpr
+	MapExpr
+	IndexExpr
 )
 
+// binaryOps maps the binary operators to their precedence,
+// an operator with higher precedence binds tighter
+var binaryOps = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// unaryOps is the set of unary prefix operators
+var unaryOps = map[string]bool{
+	"!": true,
+	"-": true,
+}
+
+func isOperator(op string) bool {
+	_, isBinary := binaryOps[op]
+	return isBinary || unaryOps[op]
+}
+
+// ParseError is a syntax error in a bind string
+type ParseError struct {
+	// Pos is the position (in characters, starting from 0) of the offending
+	// token in the bind string
+	Pos int
+	// Token is the offending token, it's empty at the end of the bind string
+	Token string
+	Msg   string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v (column %v)", e.Msg, e.Pos+1)
+}
+
+func newParseError(pos int, tok string, msg string) *ParseError {
+	return &ParseError{pos, tok, msg}
+}
+
+func unexpectedToken(t token) *ParseError {
+	kind := "token"
+	if t.kind == OpToken {
+		kind = "operator"
+	}
+	return newParseError(t.pos, t.v, fmt.Sprintf("Unexpected %v '%v'", kind, t.v))
+}
+
 type token struct {
 	kind TokenType
 	v    string
+	pos  int
 }
 
+// expr is a node of the parsed expression tree. For an OpExpr, name is the operator
+// and args are its operands (one for unary operators, two for binary ones).
+// For a MapExpr (a map literal like {active: IsActive}), args are the values of the keys.
+// For an IndexExpr (like Groups[`cpu`][0].Value), args are the indexed expression and
+// the index, name is the field path following the brackets, if any.
 type expr struct {
 	name string
 	typ  ExprType
 	args []*expr
+	keys []string
 }
 
-// tokenize simply splits the bind target string syntax into expressions (SomeObject.SomeField) and punctuations (().,), making
-// it a little bit easier to parse
+// tokenize simply splits the bind target string syntax into expressions (SomeObject.SomeField),
+// punctuations (().,{}:[]) and operators (&& || == < + ...), making it a little bit easier to parse.
+// Each token records its position in the bind string, for error messages.
 func tokenize(spec string) (tokens []token, err error) {
 	tokens = make([]token, 0)
 	err = nil
 	var tok string
+	tokPos := 0
 	flush := func() {
 		if tok != "" {
-			if strings.HasPrefix(tok, ".") || strings.HasSuffix(tok, ".") {
-				err = errors.New("Invalid '.'")
+			// a field path may follow an index, like in Items[0].Name
+			afterIndex := len(tokens) > 0 && tokens[len(tokens)-1].v == "]" && tokens[len(tokens)-1].pos == tokPos-1
+			if (strings.HasPrefix(tok, ".") && !afterIndex) || strings.HasSuffix(tok, ".") || tok == "." {
+				err = newParseError(tokPos, tok, "Invalid '.'")
 				return
 			}
-			tokens = append(tokens, token{ExprToken, tok})
+			if strings.Count(tok, "?") != strings.Count(tok, "?.") {
+				err = newParseError(tokPos, tok, "Invalid '?', it must be followed by '.'")
+				return
+			}
+			tokens = append(tokens, token{ExprToken, tok, tokPos})
 		}
 		tok = ""
 	}
+	runes := []rune(spec)
 	strlitMode := false //string literal mode
-	for _, c := range spec {
+	var quote rune
+	for i := 0; i < len(runes) && err == nil; i++ {
+		c := runes[i]
 		if !strlitMode {
-			switch c {
-			case ' ':
-				if tok != "" {
-					err = errors.New("Invalid space")
-					return
-				}
-			case '(', ')', ',':
+			if tok == "" {
+				tokPos = i
+			}
+			switch {
+			case unicode.IsSpace(c):
+				flush()
+			case strings.ContainsRune("(),{}:[]", c):
 				flush()
-				tokens = append(tokens, token{PuncToken, string(c)})
-			case '`':
+				tokens = append(tokens, token{PuncToken, string(c), i})
+			case c == '`' || c == '\'':
+				// 'abc' is the same as `abc`, it's handy inside an attribute
 				strlitMode = true
+				quote = c
+				tok += "`"
+			case c == '?':
+				// the '?' of the safe navigation operator "?."
 				tok += string(c)
+			case strings.ContainsRune("|&=!<>+-*/%", c):
+				flush()
+				op := string(c)
+				if i+1 < len(runes) && isOperator(op+string(runes[i+1])) {
+					op += string(runes[i+1])
+				}
+				if !isOperator(op) {
+					err = newParseError(i, op, fmt.Sprintf("Invalid operator '%v'", op))
+					return
+				}
+				tokens = append(tokens, token{OpToken, op, i})
+				i += len(op) - 1
 			default:
 				if isValidExprChar(c) {
 					tok += string(c)
 				} else {
-					err = fmt.Errorf("Character '%q' is not allowed", c)
+					err = newParseError(i, string(c), fmt.Sprintf("Character '%q' is not allowed", c))
 					return
 				}
 			}
 		} else {
-			if c == '`' {
+			// spaces and braces are allowed for the texts of helpers like plural,
+			// with the {n} placeholder: plural(Count, `{n} item left`, `{n} items left`)
+			if c == quote {
 				strlitMode = false
-			} else if !unicode.IsDigit(c) && !unicode.IsLetter(c) && !strings.ContainsRune(",(-_.)", c) {
-				err = fmt.Errorf("Use of characters other than numbers, " +
-					"letters, parentheses ('(', ')'), dash ('-'), comma (','), " +
-					"underscore ('_'), and dot ('.') is forbidden " +
-					"inside string literals of bind string, " +
-					"heavy processing and logic should not be in html template. Consider " +
+				c = '`'
+			} else if !unicode.IsDigit(c) && !unicode.IsLetter(c) && !strings.ContainsRune(",(-_.) {}", c) {
+				err = newParseError(i, string(c), "Use of characters other than numbers, "+
+					"letters, parentheses ('(', ')'), braces ('{', '}'), dash ('-'), comma (','), "+
+					"underscore ('_'), dot ('.') and space is forbidden "+
+					"inside string literals of bind string, "+
+					"heavy processing and logic should not be in html template. Consider "+
 					"moving your data to the model instead of putting it into the bind string.")
 				return
 			}
 			tok += string(c)
 		}
 	}
+	if err != nil {
+		return
+	}
+	if strlitMode {
+		err = newParseError(tokPos, tok, "Unterminated string literal")
+		return
+	}
 	flush()
 
 	return
 }
 
-// parse parses the bind target string, populate information into a tree of Expr pointers.
-// Each helper call has a list arguments, each argument may be another helper call or an object expression.
-func parse(spec string) (root *expr, err error) {
-	tokens, err := tokenize(spec)
+// parser is a recursive descent parser for the token list of a bind string
+type parser struct {
+	tokens []token
+	i      int
+	end    int
+}
+
+func (p *parser) peek() (t token, ok bool) {
+	if p.i < len(p.tokens) {
+		return p.tokens[p.i], true
+	}
+	return
+}
+
+// parseBinary parses a chain of binary operations whose operators have at least
+// the given precedence
+func (p *parser) parseBinary(minPrec int) (e *expr, err error) {
+	e, err = p.parseUnary()
 	if err != nil {
 		return
 	}
-	invalid := func() {
-		err = errors.New("Invalid syntax")
+
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != OpToken {
+			return
+		}
+		prec, isBinary := binaryOps[t.v]
+		if !isBinary || prec < minPrec {
+			return
+		}
+		p.i++
+
+		var right *expr
+		right, err = p.parseBinary(prec + 1)
+		if err != nil {
+			return
+		}
+		e = &expr{
+			name: t.v,
+			typ:  OpExpr,
+			args: []*expr{e, right},
+		}
 	}
-	if len(tokens) == 0 {
-		err = errors.New("Empty bind string")
+}
+
+func (p *parser) parseUnary() (e *expr, err error) {
+	if e, ok := p.parseNegativeLiteral(); ok {
+		return e, nil
 	}
-	if tokens[0].kind != ExprToken {
-		invalid()
+
+	t, ok := p.peek()
+	if ok && t.kind == OpToken && unaryOps[t.v] {
+		p.i++
+		var operand *expr
+		operand, err = p.parseUnary()
+		if err != nil {
+			return
+		}
+		e = &expr{
+			name: t.v,
+			typ:  OpExpr,
+			args: []*expr{operand},
+		}
 		return
 	}
-	stack := make([]*expr, 0)
-	exprOf := make([]*expr, len(tokens))
-	root = &expr{
-		name: tokens[0].v,
+
+	return p.parsePrimary()
+}
+
+// parseNegativeLiteral parses a negative number literal like -5 or -0.5, a '-' directly
+// followed by a number. Otherwise (like in "-Balance" or "- 5"), the '-' is the
+// unary minus operator, which negates its operand when evaluated.
+func (p *parser) parseNegativeLiteral() (e *expr, ok bool) {
+	if p.i+1 >= len(p.tokens) {
+		return
+	}
+
+	minus, num := p.tokens[p.i], p.tokens[p.i+1]
+	if minus.kind != OpToken || minus.v != "-" || num.kind != ExprToken || num.pos != minus.pos+1 {
+		return
+	}
+
+	lit, isLiteral, err := parseExpr(minus.v + num.v)
+	if err != nil || !isLiteral {
+		return
+	}
+	switch lit.(type) {
+	case int, float32:
+	default:
+		return
+	}
+
+	p.i += 2
+	return &expr{
+		name: minus.v + num.v,
 		typ:  ValueExpr,
 		args: make([]*expr, 0),
+	}, true
+}
+
+// parsePrimary parses a parenthesized expression, a map literal, a value or a function call,
+// followed by indexes if any
+func (p *parser) parsePrimary() (e *expr, err error) {
+	e, err = p.parseOperand()
+	if err != nil {
+		return
 	}
-	exprOf[0] = root
-	var parent *expr = nil
-	for ii, token := range tokens[1:] {
-		i := ii + 1 //i starts from 1 instead of 1, more intuitive
-		switch token.v {
-		case "(":
-			if tokens[i-1].kind != ExprToken {
-				invalid()
-				return
-			}
-			parent = exprOf[i-1]
-			parent.typ = CallExpr
-			stack = append(stack, parent)
-		case ")":
-			if parent == nil {
-				invalid()
-				return
-			}
-			stack = stack[:len(stack)-1]
 
-		case ",":
-			if !(tokens[i-1].kind == ExprToken || tokens[i-1].v == ")") {
-				invalid()
-				return
-			}
-		//expression
+	return p.parseIndexes(e)
+}
+
+// parseIndexes parses the indexes following the expression and the field paths
+// following them, like [`cpu`][0].Value
+func (p *parser) parseIndexes(target *expr) (e *expr, err error) {
+	e = target
+	for {
+		open, ok := p.peek()
+		if !ok || open.kind != PuncToken || open.v != "[" {
+			return
+		}
+		p.i++
+
+		var index *expr
+		index, err = p.parseBinary(1)
+		if err != nil {
+			return
+		}
+		close, ok := p.peek()
+		if !ok || close.v != "]" {
+			err = newParseError(open.pos, open.v, "Unmatched '['")
+			return
+		}
+		p.i++
+
+		e = &expr{
+			typ:  IndexExpr,
+			args: []*expr{e, index},
+		}
+		if t, ok := p.peek(); ok && t.kind == ExprToken && strings.HasPrefix(t.v, ".") && t.pos == close.pos+1 {
+			p.i++
+			e.name = t.v[1:]
+		}
+	}
+}
+
+// parseOperand parses a parenthesized expression, a map literal, a value or a function call
+func (p *parser) parseOperand() (e *expr, err error) {
+	t, ok := p.peek()
+	if !ok {
+		err = newParseError(p.end, "", "Unexpected end of bind string")
+		return
+	}
+	p.i++
+
+	switch {
+	case t.kind == PuncToken && t.v == "(":
+		e, err = p.parseBinary(1)
+		if err != nil {
+			return
+		}
+		if c, ok := p.peek(); !ok || c.v != ")" {
+			err = newParseError(t.pos, t.v, "Unmatched '('")
+			return
+		}
+		p.i++
+	case t.kind == PuncToken && t.v == "{":
+		e = &expr{
+			typ:  MapExpr,
+			args: make([]*expr, 0),
+			keys: make([]string, 0),
+		}
+		err = p.parseMap(e, t)
+	case t.kind == ExprToken:
+		if _, _, er := parseExpr(t.v); er != nil {
+			err = newParseError(t.pos, t.v, er.Error())
+			return
+		}
+		e = &expr{
+			name: t.v,
+			typ:  ValueExpr,
+			args: make([]*expr, 0),
+		}
+		if c, ok := p.peek(); ok && c.v == "(" {
+			p.i++
+			e.typ = CallExpr
+			err = p.parseArgs(e, c)
+		}
+	default:
+		err = unexpectedToken(t)
+	}
+
+	return
+}
+
+// parseArgs parses the comma-separated arguments of a function call, until the closing parenthesis
+func (p *parser) parseArgs(e *expr, open token) (err error) {
+	if c, ok := p.peek(); ok && c.v == ")" {
+		p.i++
+		return
+	}
+
+	for {
+		var arg *expr
+		arg, err = p.parseBinary(1)
+		if err != nil {
+			return
+		}
+		e.args = append(e.args, arg)
+
+		c, ok := p.peek()
+		switch {
+		case !ok:
+			return newParseError(open.pos, open.v, "Unmatched '('")
+		case c.v == ",":
+			p.i++
+		case c.v == ")":
+			p.i++
+			return
 		default:
-			e := &expr{
-				name: tokens[i].v,
-				typ:  ValueExpr,
-				args: make([]*expr, 0),
+			return unexpectedToken(c)
+		}
+	}
+}
+
+// parseMap parses the "key: value" entries of a map literal, until the closing brace.
+// A key is a name or a string literal, for keys that aren't valid names (like "is-active").
+func (p *parser) parseMap(e *expr, open token) (err error) {
+	if c, ok := p.peek(); ok && c.v == "}" {
+		p.i++
+		return
+	}
+
+	for {
+		k, ok := p.peek()
+		if !ok {
+			return newParseError(open.pos, open.v, "Unmatched '{'")
+		}
+		key, isLiteral, er := parseExpr(k.v)
+		_, isString := key.(string)
+		if k.kind != ExprToken || er != nil || (isLiteral && !isString) || strings.ContainsAny(k.v, ".?") {
+			return newParseError(k.pos, k.v, fmt.Sprintf("Invalid map key '%v'", k.v))
+		}
+		if !isLiteral {
+			key = k.v
+		}
+		p.i++
+
+		if c, ok := p.peek(); !ok || c.v != ":" {
+			if !ok {
+				return newParseError(p.end, "", "Expected ':' after the map key")
 			}
-			exprOf[i] = e
-			if len(stack) == 0 {
-				invalid()
-				return
+			return unexpectedToken(c)
+		}
+		p.i++
+
+		var value *expr
+		value, err = p.parseBinary(1)
+		if err != nil {
+			return
+		}
+		e.keys = append(e.keys, key.(string))
+		e.args = append(e.args, value)
+
+		c, ok := p.peek()
+		switch {
+		case !ok:
+			return newParseError(open.pos, open.v, "Unmatched '{'")
+		case c.v == ",":
+			p.i++
+		case c.v == "}":
+			p.i++
+			return
+		default:
+			return unexpectedToken(c)
+		}
+	}
+}
+
+// parse parses the bind target string, populate information into a tree of Expr pointers.
+// Each helper call has a list arguments, each argument may be another helper call, an object expression,
+// a map literal or an operation. Operations follow the usual precedence (|| < && < comparisons < + - < * / %),
+// which can be overridden with parentheses.
+func parse(spec string) (root *expr, err error) {
+	tokens, err := tokenize(spec)
+	if err != nil {
+		return
+	}
+	if len(tokens) == 0 {
+		err = newParseError(0, "", "Empty bind string")
+		return
+	}
+
+	p := &parser{tokens: tokens, end: len([]rune(spec))}
+	root, err = p.parseBinary(1)
+	if err != nil {
+		return
+	}
+
+	if t, ok := p.peek(); ok {
+		if t.v == ")" || t.v == "}" || t.v == "]" {
+			err = newParseError(t.pos, t.v, fmt.Sprintf("Unmatched '%v'", t.v))
+		} else {
+			err = unexpectedToken(t)
+		}
+	}
+
+	return
+}
+
+// splitTopLevel splits the string at the separators that are neither nested in
+// parentheses, braces or brackets nor inside string literals, so that map literals
+// and function calls are kept whole. Like strings.SplitN, at most n parts are
+// returned and n < 0 means all of them.
+func splitTopLevel(s string, sep rune, n int) []string {
+	parts := make([]string, 0)
+	depth, start := 0, 0
+	var quote rune
+	for i, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
 			}
-			stack[len(stack)-1].args = append(stack[len(stack)-1].args, e)
+		case c == '`' || c == '\'':
+			quote = c
+		case strings.ContainsRune("({[", c):
+			depth++
+		case strings.ContainsRune(")}]", c):
+			depth--
+		case c == sep && depth == 0 && (n < 0 || len(parts) < n-1):
+			parts = append(parts, s[start:i])
+			start = i + len(string(c))
 		}
 	}
 
+	return append(parts, s[start:])
+}
+
+// splitFieldBind splits a field bind of an attribute bind, like "Field: Expression",
+// at its first top-level colon
+func splitFieldBind(fb string) (field, expr string, ok bool) {
+	fv := splitTopLevel(fb, ':', 2)
+	if len(fv) != 2 {
+		return
+	}
+	return strings.TrimSpace(fv[0]), strings.TrimSpace(fv[1]), true
+}
+
+// ExprCacheSize is the maximum number of parsed bind strings kept in the cache
+const ExprCacheSize = 4096
+
+// exprCache caches the parsed expression trees by bind string, so that the same bind
+// string used in many elements (for example inside bind-each) is only parsed once.
+// The trees are never modified after parsing, so they can be shared. The least
+// recently used trees are dropped when the cache is full, so that the bind strings
+// built at runtime don't make it grow forever.
+var exprCache = newLruCache(ExprCacheSize)
+
+// parseCached is parse with caching of the successful results
+func parseCached(spec string) (root *expr, err error) {
+	if cached, ok := exprCache.get(spec); ok {
+		return cached.(*expr), nil
+	}
+
+	root, err = parse(spec)
+	if err != nil {
+		return
+	}
+
+	exprCache.add(spec, root)
 	return
 }
 
@@ -173,6 +575,8 @@ func parseExpr(expr string) (value interface{}, isLiteral bool, err error) {
 	floatMode := false
 	for i, c := range expr {
 		switch {
+		case c == '-' && i == 0 && len(re) > 1 && unicode.IsDigit(re[1]):
+			numberMode = true
 		case c == '`':
 			if i == 0 { //string literal
 				if re[len(expr)-1] == '`' {
@@ -189,11 +593,16 @@ func parseExpr(expr string) (value interface{}, isLiteral bool, err error) {
 			if i == 0 {
 				numberMode = true
 			}
-		case unicode.IsLetter(c) || c == '_':
+		case unicode.IsLetter(c) || c == '_' || c == '$':
 			if numberMode {
 				err = fmt.Errorf("Invalid: dynamic expression cannot start with a number")
 				return
 			}
+		case c == '?':
+			if numberMode {
+				err = fmt.Errorf("Invalid '?' in a number")
+				return
+			}
 		case c == '.':
 			if floatMode {
 				err = fmt.Errorf("Multiple dot '.' for a number, invalid")
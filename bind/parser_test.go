@@ -1,6 +1,9 @@
 package bind
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -12,6 +15,31 @@ type TestUser struct {
 	}
 }
 
+type testAddress struct {
+	City string
+}
+
+type testProfile struct {
+	Address *testAddress
+}
+
+type testAccount struct {
+	Profile *testProfile
+}
+
+// evaluate evaluates the bind string against the given model and the global helpers
+func (b *Binding) evaluate(bstr string, model interface{}) (root *expr, blist []bindable, value interface{}, err error) {
+	s := newModelScope(model)
+	s.merge(b.scope)
+	return (&bindScope{s}).evaluate(bstr)
+}
+
+func (b *Binding) evaluateBindString(bstr string, model interface{}) (root *expr, blist []bindable, value interface{}) {
+	s := newModelScope(model)
+	s.merge(b.scope)
+	return (&bindScope{s}).evaluateBindString(bstr)
+}
+
 func TestParser(t *testing.T) {
 	model := new(TestUser)
 	model.Data.Username = "Hai"
@@ -65,3 +93,521 @@ func TestParser(t *testing.T) {
 		}
 	}
 }
+
+func TestOptionalChaining(t *testing.T) {
+	b := NewBindEngine(nil)
+	models := []*testAccount{
+		&testAccount{},
+		&testAccount{&testProfile{}},
+		&testAccount{&testProfile{&testAddress{}}},
+	}
+
+	for _, model := range models {
+		_, _, v, err := b.evaluate("Profile?.Address?.City", model)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if toString(v) != "" {
+			t.Errorf("Expected an empty string, got %v.", v)
+		}
+
+		_, _, v, err = b.evaluate("toUpper(Profile?.Address?.City)", model)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if v != "" {
+			t.Errorf("Expected an empty string, got %v.", v)
+		}
+	}
+
+	model := &testAccount{&testProfile{&testAddress{"Hanoi"}}}
+	_, _, v, err := b.evaluate("Profile?.Address?.City", model)
+	if err != nil || v != "Hanoi" {
+		t.Errorf(`Expected "Hanoi", got %v (error: %v).`, v, err)
+	}
+
+	_, _, _, err = b.evaluate("Profile.Address.City", models[0])
+	if err == nil {
+		t.Errorf("Expected an error for a nil intermediate without '?.'.")
+	}
+
+	for _, et := range []string{"Profile?", "Profile?Address", "Profile.?Address"} {
+		_, _, _, err = b.evaluate(et, model)
+		if err == nil {
+			t.Errorf("Expected an error for %v, no error is returned.", et)
+		}
+	}
+}
+
+type testTodos struct {
+	Entries []string
+	Done    map[string]bool
+	Title   string
+	Tags    [2]string
+}
+
+func TestLengthProperty(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testTodos{
+		Entries: []string{"a", "b"},
+		Done:    map[string]bool{"a": true},
+		Title:   "Todos",
+	}
+
+	tests := map[string]int{
+		"Entries.length": 2,
+		"Entries.len":    2,
+		"Done.length":    1,
+		"Title.length":   5,
+		"Tags.len":       2,
+	}
+	for bstr, expected := range tests {
+		_, _, v, err := b.evaluate(bstr, model)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if v != expected {
+			t.Errorf("Expected %v for %v, got %v.", expected, bstr, v)
+		}
+	}
+
+	s := newModelScope(model)
+	s.merge(b.scope)
+	bs := &bindScope{s}
+	root, blist, _, err := bs.evaluate("Entries.length")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(blist) != 1 || blist[0].bindObj().field != "Entries" {
+		t.Fatalf("Expected the Entries field to be watched.")
+	}
+	if blist[0].bindObj().canSet() {
+		t.Errorf("Expected the length to be read-only.")
+	}
+
+	model.Entries = append(model.Entries, "c")
+	v, _, err := bs.evaluateRec(root)
+	if err != nil || v.Interface() != 3 {
+		t.Errorf("Expected 3 after append, got %v (error: %v).", v, err)
+	}
+
+	_, _, _, err = b.evaluate("Entries.length.length", model)
+	if err == nil {
+		t.Errorf("Expected an error for a length of a length.")
+	}
+
+	for bstr := range tests {
+		ti, ok := typeOfField(reflect.TypeOf(model), strings.Split(bstr, "."))
+		if !ok || ti.typ != reflect.TypeOf(0) {
+			t.Errorf("Expected %v to be typed as int, got %v.", bstr, ti.typ)
+		}
+	}
+}
+
+type testFlags struct {
+	A, B, C bool
+	X, Y    int
+}
+
+func TestGrouping(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testFlags{A: true, B: false, C: false, X: 2, Y: 3}
+	tests := []struct {
+		bstr     string
+		expected interface{}
+	}{
+		{"A || B && C", true},
+		{"(A || B) && C", false},
+		{"X + Y * 2", 8},
+		{"(X + Y) * 2", 10},
+		{"X - Y - 1", -2},
+		{"X - (Y - 1)", 0},
+		{"!(A && C)", true},
+		{"-(X + Y)", -5},
+		{"((X))", 2},
+		{"addInt((X + 1) * 2, Y)", 9},
+		{"X * 2 >= Y && !C", true},
+		{"toUpper(`a`) == `A`", true},
+	}
+	b.RegisterHelper("addInt", func(a, b int) int {
+		return a + b
+	})
+
+	for _, test := range tests {
+		_, _, v, err := b.evaluate(test.bstr, model)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", test.bstr, err)
+			continue
+		}
+		if v != test.expected {
+			t.Errorf("%v: expected %v, got %v.", test.bstr, test.expected, v)
+		}
+	}
+
+	root, err := parse("(A || B) && C")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if root.typ != OpExpr || root.name != "&&" || root.args[0].name != "||" || root.args[1].name != "C" {
+		t.Errorf("Expected the grouped operation to be nested under &&.")
+	}
+
+	errtests := map[string]string{
+		"(A || B":       "column 1",
+		"A || B)":       "column 7",
+		"toUpper((A)":   "column 8",
+		"(A && (B || C": "column 7",
+		"A &&":          "column 5",
+		"A & B":         "column 3",
+		"()":            "column 2",
+	}
+	for bstr, pos := range errtests {
+		_, err := parse(bstr)
+		if err == nil {
+			t.Errorf("Expected an error for %v, no error is returned.", bstr)
+		} else if !strings.Contains(err.Error(), pos) {
+			t.Errorf("Expected the error for %v to be at %v, got %q.", bstr, pos, err.Error())
+		}
+	}
+}
+
+type testPlaceholder struct {
+	Name    string
+	Count   int
+	Ratio   float32
+	Owner   *testAddress
+	Enabled bool
+}
+
+func TestCoalescing(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testPlaceholder{}
+	tests := []struct {
+		bstr     string
+		expected interface{}
+	}{
+		{"Name || `Anonymous`", "Anonymous"},
+		{"Count || 10", 10},
+		{"Ratio || 0.5", float32(0.5)},
+		{"Owner || `nobody`", "nobody"},
+		{"Owner?.City || `nowhere`", "nowhere"},
+		{"Name || Count || `none`", "none"},
+		{"Enabled || false", false},
+	}
+	for _, test := range tests {
+		_, _, v, err := b.evaluate(test.bstr, model)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", test.bstr, err)
+			continue
+		}
+		if v != test.expected {
+			t.Errorf("%v: expected the fallback %v, got %v.", test.bstr, test.expected, v)
+		}
+	}
+
+	model.Name, model.Count, model.Owner = "Jo", 3, &testAddress{"Hue"}
+	tests = tests[:5]
+	for i, expected := range []interface{}{"Jo", 3, float32(0.5), model.Owner, "Hue"} {
+		tests[i].expected = expected
+	}
+	for _, test := range tests {
+		if _, _, v, err := b.evaluate(test.bstr, model); err != nil || v != test.expected {
+			t.Errorf("%v: expected the value %v, got %v (%v).", test.bstr, test.expected, v, err)
+		}
+	}
+
+	if _, _, _, err := b.evaluate("Name && true", model); err == nil {
+		t.Errorf("Expected && to require bool operands.")
+	}
+	if _, _, _, err := b.evaluate("Enabled || Name", model); err == nil {
+		t.Errorf("Expected an error for a bool operand with a string fallback.")
+	}
+	if errs := b.Check(`<span bind-text="Enabled || Name"></span>`, model); len(errs) != 1 {
+		t.Errorf("Expected the mismatched operands to be reported, got %v.", errs)
+	}
+	if errs := b.Check(`<span bind-text="Name || `+"`Anonymous`"+`"></span>`, model); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v.", errs)
+	}
+}
+
+func TestStringLiterals(t *testing.T) {
+	b := NewBindEngine(nil)
+	tests := map[string]interface{}{
+		"`{n} items left`":                   "{n} items left",
+		"concat('a (b)', `, c-d_e.f`)":       "a (b), c-d_e.f",
+		"plural(2, `{n} item`, `{n} items`)": "2 items",
+		"{label: `{n} new`}":                 map[string]interface{}{"label": "{n} new"},
+		"toUpper(concat(`x `, ` y`))":        "X  Y",
+	}
+	for bstr, expected := range tests {
+		v, err := b.Eval(nil, bstr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", bstr, err)
+		} else if !reflect.DeepEqual(v, expected) {
+			t.Errorf("%v: expected %q, got %q.", bstr, expected, v)
+		}
+	}
+
+	for _, bstr := range []string{"`a = b`", "`a < b`", "`a + b`", "`a: b`", "`a!`", "'a \"b\"'"} {
+		if _, err := parse(bstr); err == nil {
+			t.Errorf("Expected an error for the characters of %v.", bstr)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		bstr  string
+		pos   int
+		token string
+	}{
+		{"concat(Name, `abc)", 13, "`abc)"},
+		{"Name == && Age", 8, "&&"},
+		{"toUpper(Name) *", 15, ""},
+		{"concat(Name,, Age)", 12, ","},
+		{"addInt(1a, 2)", 7, "1a"},
+	}
+
+	for _, test := range tests {
+		_, err := parse(test.bstr)
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%v: expected a ParseError, got %v.", test.bstr, err)
+			continue
+		}
+		if pe.Pos != test.pos || pe.Token != test.token {
+			t.Errorf("%v: expected the error at %v on %q, got %v on %q.", test.bstr, test.pos, test.token, pe.Pos, pe.Token)
+		}
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(fmt.Sprint(r), "column 9") {
+			t.Errorf("Expected the panic message to contain the column, got %v.", r)
+		}
+	}()
+	NewBindEngine(nil).evaluateBindString("Name == && Age", &TestUser{})
+}
+
+func TestMapLiteral(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testFlags{A: true, B: true, X: 2}
+	_, blist, v, err := b.evaluate("{active: A, done: !B, `is-big`: X > 1, empty: {}}", model)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"active": true,
+		"done":   false,
+		"is-big": true,
+		"empty":  map[string]interface{}{},
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("Expected %v, got %v.", expected, v)
+	}
+	if len(blist) != 3 {
+		t.Errorf("Expected the fields of the values to be watched, got %v bindables.", len(blist))
+	}
+
+	model.B = false
+	_, _, v, _ = b.evaluate("{active: A, done: !B}", model)
+	classes, err := classMap(v)
+	if err != nil || !reflect.DeepEqual(classes, map[string]bool{"active": true, "done": true}) {
+		t.Errorf("Unexpected classes %v (error: %v).", classes, err)
+	}
+	if _, err := classMap(map[string]interface{}{"active": 2}); err == nil {
+		t.Errorf("Expected an error for a non-bool class state.")
+	}
+
+	errtests := map[string]string{
+		"{active A}":     "column 9",
+		"{active: A":     "column 1",
+		"{1: A}":         "column 2",
+		"{Data.Name: A}": "column 2",
+		"{active: A,, }": "column 12",
+		"{active: A} }":  "column 13",
+		"toUpper({a: A}": "column 8",
+	}
+	for bstr, pos := range errtests {
+		_, err := parse(bstr)
+		if err == nil {
+			t.Errorf("Expected an error for %v, no error is returned.", bstr)
+		} else if !strings.Contains(err.Error(), pos) {
+			t.Errorf("Expected the error for %v to be at %v, got %q.", bstr, pos, err.Error())
+		}
+	}
+}
+
+type testAccountBalance struct {
+	Balance int
+	Rate    float64
+	Name    string
+}
+
+func TestUnaryMinus(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testAccountBalance{Balance: 120, Rate: 0.25, Name: "wade"}
+	tests := []struct {
+		bstr     string
+		expected interface{}
+	}{
+		{"-Balance", -120},
+		{"-Rate", -0.25},
+		{"- Balance", -120},
+		{"--Balance", 120},
+		{"-5", -5},
+		{"-0.5", float32(-0.5)},
+		{"Balance-5", 115},
+		{"Balance - -5", 125},
+		{"-Balance * 2", -240},
+		{"-(Balance + 5)", -125},
+	}
+	for _, test := range tests {
+		_, _, v, err := b.evaluate(test.bstr, model)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", test.bstr, err)
+		} else if v != test.expected {
+			t.Errorf("%v: expected %v, got %v.", test.bstr, test.expected, v)
+		}
+	}
+
+	root, err := parse("-5")
+	if err != nil || root.typ != ValueExpr || root.name != "-5" {
+		t.Errorf("Expected -5 to be parsed as a literal, got %v (error: %v).", root, err)
+	}
+	root, err = parse("-Balance")
+	if err != nil || root.typ != OpExpr || root.name != "-" || root.args[0].name != "Balance" {
+		t.Errorf("Expected -Balance to be a negation, got %v (error: %v).", root, err)
+	}
+
+	for _, bstr := range []string{"-Name", "-true", "-`5`"} {
+		if _, _, _, err := b.evaluate(bstr, model); err == nil {
+			t.Errorf("Expected an error for %v.", bstr)
+		}
+	}
+}
+
+type testSubject struct {
+	Title string
+}
+
+func (s testSubject) Short() string {
+	return s.Title[:1]
+}
+
+type testEvent struct {
+	Subject interface{}
+}
+
+func TestInterfaceFields(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &struct{ Event testEvent }{testEvent{testSubject{"Write docs"}}}
+	for _, subject := range []interface{}{testSubject{"Write docs"}, &testSubject{"Write docs"}} {
+		model.Event.Subject = subject
+		_, _, v, err := b.evaluate("Event.Subject.Title", model)
+		if err != nil || v != "Write docs" {
+			t.Errorf("%T: expected the title of the concrete value, got %v (error: %v).", subject, v, err)
+		}
+		_, _, v, err = b.evaluate("toUpper(Event.Subject.Short())", model)
+		if err != nil || v != "W" {
+			t.Errorf("%T: expected the method of the concrete value to be called, got %v (error: %v).", subject, v, err)
+		}
+	}
+
+	// fields of a pointer held by an interface are settable
+	oe, ok := evaluateObjField("Event.Subject.Title", reflect.ValueOf(model))
+	if !ok || !oe.canSet() {
+		t.Fatalf("Expected the field to be settable.")
+	}
+	oe.set(reflect.ValueOf("Review"))
+	if model.Event.Subject.(*testSubject).Title != "Review" {
+		t.Errorf("Expected the field to be set.")
+	}
+
+	model.Event.Subject = nil
+	_, _, _, err := b.evaluate("Event.Subject.Title", model)
+	if err == nil || !strings.Contains(err.Error(), `"Event.Subject" is nil`) {
+		t.Errorf("Expected an error about the nil interface, got %v.", err)
+	}
+	_, _, v, err := b.evaluate("Event.Subject?.Title", model)
+	if err != nil || v != nil {
+		t.Errorf("Expected nil with '?.', got %v (error: %v).", v, err)
+	}
+}
+
+type testMetric struct {
+	Value float64
+	Unit  string
+}
+
+type testMetrics struct {
+	Groups map[string][]testMetric
+	Hosts  []string
+	Ports  map[int]string
+	Empty  map[string][]*testMetric
+}
+
+func TestIndexing(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testMetrics{
+		Groups: map[string][]testMetric{
+			"cpu": {{0.5, "%"}, {0.7, "%"}},
+			"mem": {},
+		},
+		Hosts: []string{"alpha", "beta"},
+		Ports: map[int]string{80: "http"},
+		Empty: map[string][]*testMetric{"disk": {nil}},
+	}
+
+	tests := map[string]interface{}{
+		"Groups['cpu'][0].Value":                  0.5,
+		"Groups[`cpu`][1].Unit":                   "%",
+		"Groups['cpu'][Groups.cpu.len - 1].Value": 0.7,
+		"toUpper(Hosts[1])":                       "BETA",
+		"Hosts[0] + Ports[80]":                    "alphahttp",
+		"Groups['mem'].length":                    0,
+		"len(Groups['cpu'])":                      2,
+	}
+	for bstr, expected := range tests {
+		v, err := b.Eval(model, bstr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", bstr, err)
+		} else if v != expected {
+			t.Errorf("%v: expected %v, got %v.", bstr, expected, v)
+		}
+	}
+
+	for _, bstr := range []string{
+		"Groups['disk'][0].Value", // missing key
+		"Groups['mem'][0].Value",  // out of range
+		"Empty['disk'][0].Value",  // nil item
+		"Hosts['x']",
+		"Groups['cpu'][0].Nothing",
+		"Hosts[0",
+		"Hosts]",
+	} {
+		if _, err := b.Eval(model, bstr); err == nil {
+			t.Errorf("Expected an error for %v.", bstr)
+		}
+	}
+
+	// the fields after the index are watched and settable
+	bs := &bindScope{newModelScope(model)}
+	bs.scope.merge(b.scope)
+	_, blist, _, err := bs.evaluate("Groups['cpu'][1].Value")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	oe := blist[len(blist)-1].bindObj()
+	if oe.field != "Value" || !oe.canSet() {
+		t.Fatalf("Expected the indexed field to be bindable, got %v.", oe.field)
+	}
+	oe.set(reflect.ValueOf(0.9))
+	if model.Groups["cpu"][1].Value != 0.9 {
+		t.Errorf("Expected the slice item to be set, got %v.", model.Groups["cpu"][1].Value)
+	}
+
+	if errs := b.Check(`<p bind-text="Groups['cpu'][0].Value + 1.5"></p><p bind-text="Hosts[0].Nothing"></p>`, model); len(errs) != 1 {
+		t.Errorf("Expected one error, got %v.", errs)
+	}
+}
@@ -0,0 +1,110 @@
+package bind
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+const (
+	// PollAttr enables polling for the binds of an element, as a fallback for
+	// the model changes that can't be observed (writes to slice indexes, map inserts...).
+	// Its value is the interval in milliseconds, DefaultPollInterval is used if it's empty.
+	//
+	// Usage:
+	//	<li wade-poll="500" bind-text="Scores.length"></li>
+	PollAttr = "wade-poll"
+
+	DefaultPollInterval = 250 * time.Millisecond
+)
+
+// pollInterval returns the polling interval of the element, and whether polling
+// is enabled for it
+func pollInterval(elem jq.JQuery) (time.Duration, bool) {
+	if !elem.Is("[" + PollAttr + "]") {
+		return 0, false
+	}
+
+	ms, err := strconv.Atoi(elem.Attr(PollAttr))
+	if err != nil || ms <= 0 {
+		return DefaultPollInterval, true
+	}
+
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// poller reevaluates a bound expression and calls the callback when its value changed
+type poller struct {
+	eval     func() interface{}
+	last     interface{}
+	callback func(interface{})
+}
+
+func newPoller(eval func() interface{}, callback func(interface{})) *poller {
+	return &poller{eval, snapshot(eval()), callback}
+}
+
+func (p *poller) check() {
+	v := p.eval()
+	if s := snapshot(v); !reflect.DeepEqual(s, p.last) {
+		p.last = s
+		p.callback(v)
+	}
+}
+
+// snapshot copies the slices, arrays and maps of the value, so that later
+// in-place changes of them are detected when comparing
+func snapshot(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return copyValue(reflect.ValueOf(v)).Interface()
+}
+
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMap(v.Type())
+		for _, key := range v.MapKeys() {
+			c.SetMapIndex(key, copyValue(v.MapIndex(key)))
+		}
+		return c
+	}
+
+	return v
+}
+
+// startPolling checks the poller at each interval until the element is torn down
+func (b *Binding) startPolling(elem jq.JQuery, interval time.Duration, p *poller) {
+	var stop func()
+	var tick func()
+	tick = func() {
+		p.check()
+		stop = b.browser.after(interval, tick)
+	}
+	stop = b.browser.after(interval, tick)
+
+	b.addTeardown(elem, func() {
+		stop()
+	})
+}
@@ -0,0 +1,91 @@
+package bind
+
+import (
+	"testing"
+	"time"
+)
+
+type testScores struct {
+	Scores []int
+	Names  map[string]string
+}
+
+func TestPoller(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testScores{[]int{1, 2}, map[string]string{}}
+
+	for _, bstr := range []string{"Scores", "Names", "len(Names) + Scores.length"} {
+		updates := make([]interface{}, 0)
+		p := newPoller(func() interface{} {
+			v, err := b.Eval(model, bstr)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			return v
+		}, func(v interface{}) {
+			updates = append(updates, v)
+		})
+
+		p.check()
+		if len(updates) != 0 {
+			t.Fatalf("%v: expected no update without changes.", bstr)
+		}
+
+		// mutations that watchjs can't observe
+		model.Scores[0]++
+		model.Names[bstr] = "x"
+		p.check()
+		if len(updates) != 1 {
+			t.Fatalf("%v: expected 1 update after the mutation, got %v.", bstr, len(updates))
+		}
+
+		p.check()
+		model.Scores = append(model.Scores, 3)
+		model.Names[bstr+"2"] = "y"
+		p.check()
+		if len(updates) != 2 {
+			t.Errorf("%v: expected 2 updates, got %v.", bstr, len(updates))
+		}
+	}
+}
+
+func TestPollAttr(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	br := newFakeBrowser()
+	b.browser = br
+	model := &testScores{[]int{1, 2}, map[string]string{}}
+	elem := gJQ(`<div><p wade-poll="500" bind-text="len(Scores)"></p><span wade-poll bind-text="len(Names)"></span></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	p, span := elem.Find("p"), elem.Find("span")
+	if p.Text() != "2" || span.Text() != "0" || br.pending() != 2 {
+		t.Fatalf("Expected both elements to be polled, got %v with %v pending.", elem.Html(), br.pending())
+	}
+
+	// mutations that watchjs can't observe
+	model.Scores = append(model.Scores, 3)
+	model.Names["a"] = "x"
+	br.advance(DefaultPollInterval)
+	if p.Text() != "2" || span.Text() != "1" {
+		t.Errorf("Expected only the default interval to have passed, got %v.", elem.Html())
+	}
+	br.advance(500*time.Millisecond - DefaultPollInterval)
+	if p.Text() != "3" {
+		t.Errorf("Expected the change to be seen at the next check, got %v.", p.Text())
+	}
+
+	// the checks go on
+	model.Names["b"] = "y"
+	br.advance(DefaultPollInterval)
+	if span.Text() != "2" || br.pending() != 2 {
+		t.Errorf("Expected the polling to go on, got %v with %v pending.", span.Text(), br.pending())
+	}
+
+	b.Teardown(elem)
+	if br.pending() != 0 {
+		t.Errorf("Expected the polling to stop on teardown, %v checks pending.", br.pending())
+	}
+}
@@ -0,0 +1,82 @@
+package bind
+
+import (
+	"reflect"
+	"strconv"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+// progressNumber converts a value of the progress binder to a number
+func progressNumber(v interface{}) (f float64, ok bool) {
+	if s, isString := v.(string); isString {
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	}
+	return toFloat(unwrapValue(reflect.ValueOf(v)))
+}
+
+// renderProgress sets the value, clamped between 0 and max, and the max of the element.
+// The progress is indeterminate when the value or the max is not a number, or the max
+// is not positive, the value is then removed.
+func renderProgress(elem jq.JQuery, value, max interface{}) {
+	v, vok := progressNumber(value)
+	m, mok := progressNumber(max)
+	if !vok || !mok || m <= 0 {
+		elem.RemoveAttr("value")
+		elem.RemoveAttr("max")
+		return
+	}
+
+	switch {
+	case v < 0:
+		v = 0
+	case v > m:
+		v = m
+	}
+	elem.SetAttr("max", strconv.FormatFloat(m, 'f', -1, 64))
+	elem.SetAttr("value", strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+// ProgressBinder is a 1-way binder that sets the value and max of a <progress> element.
+// The max is given by an expression after "->", it's watched like the value.
+// When the max is zero or missing, the progress is indeterminate.
+// It takes no extra dash args.
+//
+// Usage:
+//	bind-progress="ValueExpression -> MaxExpression"
+// Example:
+//	<progress bind-progress="Done -> Total"></progress>
+type ProgressBinder struct {
+	BaseBinder
+	value, max interface{}
+}
+
+func (b *ProgressBinder) Bind(d DomBind) {
+	if len(d.outputs) > 1 {
+		d.Panic("The progress binder takes a single max expression after ->.")
+	}
+	if len(d.outputs) == 0 {
+		return
+	}
+
+	bs := &bindScope{d.scope}
+	root, binds, max, err := bs.evaluate(d.outputs[0])
+	if err != nil {
+		d.Panic(err.Error())
+	}
+	b.max = max
+	if !d.once {
+		d.binding.watchModel(d.Elem, binds, root, bs, func(max interface{}) {
+			b.max = max
+			renderProgress(d.Elem, b.value, b.max)
+		})
+	}
+}
+
+func (b *ProgressBinder) Update(d DomBind) {
+	b.value = d.Value
+	renderProgress(d.Elem, b.value, b.max)
+}
+
+func (b *ProgressBinder) BindInstance() DomBinder { return new(ProgressBinder) }
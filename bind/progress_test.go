@@ -0,0 +1,77 @@
+package bind
+
+import (
+	"testing"
+)
+
+func TestRenderProgress(t *testing.T) {
+	tests := []struct {
+		value, max interface{}
+		// the expected attributes, none when the progress is indeterminate
+		expected string
+	}{
+		{3, 10, "3/10"},
+		{0.5, 1.0, "0.5/1"},
+		{"4", "8", "4/8"},
+		{12, 10, "10/10"},
+		{-1, 10, "0/10"},
+		{3, 0, ""},
+		{3, nil, ""},
+		{nil, 10, ""},
+		{"x", 10, ""},
+	}
+
+	for _, test := range tests {
+		elem := gJQ(`<progress max="5" value="2"></progress>`)
+		renderProgress(elem, test.value, test.max)
+		attrs := ""
+		if elem.Is("[value]") || elem.Is("[max]") {
+			attrs = elem.Attr("value") + "/" + elem.Attr("max")
+		}
+		if attrs != test.expected {
+			t.Errorf("%v -> %v: expected %q, got %q.", test.value, test.max, test.expected, attrs)
+		}
+	}
+
+	b := &ProgressBinder{}
+	b.Bind(DomBind{})
+	if b.max != nil {
+		t.Errorf("Expected no max without an expression after ->.")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for several max expressions.")
+		}
+	}()
+	b.Bind(DomBind{outputs: []string{"Total", "Max"}})
+}
+
+type testUpload struct {
+	Done, Total int
+}
+
+func TestProgressBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	model := &testUpload{3, 0}
+	elem := gJQ(`<div><progress bind-progress="Done -> Total"></progress></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	progress := elem.Find("progress")
+	if progress.Is("[value]") || progress.Is("[max]") {
+		t.Errorf("Expected the progress to be indeterminate, got %v.", elem.Html())
+	}
+
+	model.Total = 8
+	w.change()
+	if progress.Attr("value") != "3" || progress.Attr("max") != "8" {
+		t.Errorf("Expected the progress to follow the max, got %v.", elem.Html())
+	}
+	model.Done = 6
+	w.change()
+	if progress.Attr("value") != "6" || progress.Attr("max") != "8" {
+		t.Errorf("Expected the progress to follow the value, got %v.", elem.Html())
+	}
+}
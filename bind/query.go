@@ -0,0 +1,142 @@
+package bind
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gopherjs/gopherjs/js"
+	jq "github.com/gopherjs/jquery"
+)
+
+// QueryWriteDelay is the time the query binder waits for more changes before
+// writing the query parameters to the url
+const QueryWriteDelay = 300 * time.Millisecond
+
+// QueryLocation gives access to the query parameters of the url,
+// it's used by the query binder
+type QueryLocation interface {
+	// QueryParam returns the value of the query parameter, ok is false if it's not set
+	QueryParam(name string) (value string, ok bool)
+	// SetQueryParams sets the query parameters without reloading the page,
+	// the parameters with an empty value are removed
+	SetQueryParams(params map[string]string)
+}
+
+// browserLocation is the default QueryLocation, it uses the location of the
+// document and replaces the history entry
+type browserLocation struct{}
+
+func (l browserLocation) query() url.Values {
+	values, _ := url.ParseQuery(strings.TrimPrefix(js.Global.Get("location").Get("search").Str(), "?"))
+	return values
+}
+
+func (l browserLocation) QueryParam(name string) (value string, ok bool) {
+	values := l.query()
+	if _, ok = values[name]; ok {
+		value = values.Get(name)
+	}
+	return
+}
+
+func (l browserLocation) SetQueryParams(params map[string]string) {
+	values := l.query()
+	for name, value := range params {
+		if value == "" {
+			values.Del(name)
+		} else {
+			values.Set(name, value)
+		}
+	}
+
+	location := js.Global.Get("location")
+	u := location.Get("pathname").Str()
+	if query := values.Encode(); query != "" {
+		u += "?" + query
+	}
+	u += location.Get("hash").Str()
+	js.Global.Get("history").Call("replaceState", js.Global.Get("history").Get("state"), "", u)
+}
+
+// queryWriter gathers the changes of query parameters and writes them together
+// once no change has happened for the delay
+type queryWriter struct {
+	location QueryLocation
+	pending  map[string]string
+	debounce *debouncer
+}
+
+func newQueryWriter(location QueryLocation, br browser) *queryWriter {
+	w := &queryWriter{
+		location: location,
+		pending:  make(map[string]string),
+	}
+	w.debounce = newDebouncer(br, QueryWriteDelay, w.flush)
+	return w
+}
+
+func (w *queryWriter) set(name, value string) {
+	w.pending[name] = value
+	w.debounce.trigger()
+}
+
+func (w *queryWriter) flush() {
+	w.debounce.cancel()
+	if len(w.pending) == 0 {
+		return
+	}
+
+	w.location.SetQueryParams(w.pending)
+	w.pending = make(map[string]string)
+}
+
+// SetQueryLocation sets the location used by the query binder, in a wade app
+// it's the Pager, whose current page url holds the query parameters
+func (b *Binding) SetQueryLocation(location QueryLocation) {
+	b.query = newQueryWriter(location, b.browser)
+}
+
+// QueryBinder syncs a model field with a query parameter of the url. The field is
+// set to the parameter's value when the element is bound, if the parameter is set;
+// afterwards the changes of the field are written to the url without reloading the page.
+//
+// Usage:
+//	bind-query-PARAM="Field"
+// Example:
+//	<input bind-value="Filter" bind-query-filter="Filter" />
+type QueryBinder struct {
+	BaseBinder
+	param   string
+	update  ModelUpdateFn
+	started bool
+}
+
+func (b *QueryBinder) Watch(elem jq.JQuery, updateFn ModelUpdateFn) {
+	b.update = updateFn
+}
+
+func (b *QueryBinder) Bind(d DomBind) {
+	if len(d.Args) == 0 {
+		d.Panic("The name of the query parameter is required, like bind-query-filter.")
+	}
+
+	// the parameter name may contain dashes, which separate the args
+	b.param = strings.Join(d.Args, "-")
+	if value, ok := d.binding.query.location.QueryParam(b.param); ok && b.update != nil {
+		b.update(value)
+	}
+}
+
+func (b *QueryBinder) Update(d DomBind) {
+	// the initial value is already in sync or comes from the url
+	if !b.started {
+		b.started = true
+		return
+	}
+
+	d.binding.query.set(b.param, d.ValueString())
+}
+
+func (b *QueryBinder) BindInstance() DomBinder { return &QueryBinder{} }
+func (b *QueryBinder) TwoWay()                 {}
@@ -0,0 +1,127 @@
+package bind
+
+import (
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+type testLocation struct {
+	params map[string]string
+	writes int
+}
+
+func (l *testLocation) QueryParam(name string) (value string, ok bool) {
+	value, ok = l.params[name]
+	return
+}
+
+func (l *testLocation) SetQueryParams(params map[string]string) {
+	l.writes++
+	for name, value := range params {
+		if value == "" {
+			delete(l.params, name)
+		} else {
+			l.params[name] = value
+		}
+	}
+}
+
+func TestQueryBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	br := newFakeBrowser()
+	b.browser = br
+	loc := &testLocation{params: map[string]string{"filter": "active"}}
+	b.SetQueryLocation(loc)
+
+	// url -> model
+	model := ""
+	qb := (&QueryBinder{}).BindInstance()
+	qb.Watch(jq.JQuery{}, func(v string) {
+		model = v
+	})
+	d := DomBind{Value: "all", Args: []string{"filter"}, binding: b}
+	qb.Bind(d)
+	qb.Update(d)
+	if model != "active" {
+		t.Errorf("Expected the model to be set from the url, got %q.", model)
+	}
+	if len(b.query.pending) != 0 {
+		t.Errorf("The initial value should not be written to the url.")
+	}
+
+	// model -> url, debounced
+	d.Value = "done"
+	qb.Update(d)
+	d.Value = "completed"
+	qb.Update(d)
+	if loc.params["filter"] != "active" {
+		t.Errorf("Expected the url write to be delayed.")
+	}
+	b.query.flush()
+	if loc.params["filter"] != "completed" || loc.writes != 1 {
+		t.Errorf("Expected a single write of the last value, got %v after %v writes.", loc.params, loc.writes)
+	}
+
+	d.Value = ""
+	qb.Update(d)
+	b.query.flush()
+	b.query.flush()
+	if _, ok := loc.params["filter"]; ok || loc.writes != 2 {
+		t.Errorf("Expected the parameter to be removed once, got %v after %v writes.", loc.params, loc.writes)
+	}
+
+	// parameter names with dashes and missing parameters
+	qb = (&QueryBinder{}).BindInstance()
+	model = "kept"
+	qb.Watch(jq.JQuery{}, func(v string) {
+		model = v
+	})
+	qb.Bind(DomBind{Args: []string{"page", "size"}, binding: b})
+	if model != "kept" || qb.(*QueryBinder).param != "page-size" {
+		t.Errorf("Expected the model to be kept for a missing parameter, got %q.", model)
+	}
+
+	d.Value = "all"
+	qb.Update(d)
+	qb.Update(d)
+	br.advance(QueryWriteDelay)
+	if loc.params["page-size"] != "all" {
+		t.Errorf("Expected the write to happen after the delay, got %v.", loc.params)
+	}
+}
+
+type testFilter struct {
+	Filter string
+}
+
+func TestQueryBinderBind(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	br := newFakeBrowser()
+	b.browser = br
+	loc := &testLocation{params: map[string]string{"filter": "active"}}
+	b.SetQueryLocation(loc)
+
+	model := &testFilter{"all"}
+	elem := gJQ(`<div><input bind-value="Filter" bind-query-filter="Filter"></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+	b.Bind(elem, model, false, false)
+	w.change()
+	if model.Filter != "active" || elem.Find("input").Val() != "active" {
+		t.Fatalf("Expected the model to be set from the url, got %q.", model.Filter)
+	}
+
+	elem.Find("input").SetVal("done").Trigger(jq.CHANGE)
+	w.change()
+	model.Filter = "completed"
+	w.change()
+	if br.pending() != 1 || loc.writes != 0 {
+		t.Fatalf("Expected the url write to be debounced.")
+	}
+	br.advance(QueryWriteDelay)
+	if loc.params["filter"] != "completed" || loc.writes != 1 {
+		t.Errorf("Expected a single write of the last value, got %v after %v writes.", loc.params, loc.writes)
+	}
+}
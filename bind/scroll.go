@@ -0,0 +1,84 @@
+package bind
+
+import (
+	"strconv"
+	"time"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+// ScrollThrottle is the minimum time between two updates of the model
+// by the scroll binder while the user scrolls
+const ScrollThrottle = 100 * time.Millisecond
+
+// scrollSync mirrors the scroll position of an element to the model, the scroll
+// events are throttled so that the model is updated at most once per ScrollThrottle,
+// with the position at the end of the delay
+type scrollSync struct {
+	elem    jq.JQuery
+	browser browser
+	update  ModelUpdateFn
+	pending bool
+}
+
+func (s *scrollSync) scrolled() {
+	if s.pending {
+		return
+	}
+
+	s.pending = true
+	s.browser.after(ScrollThrottle, func() {
+		s.pending = false
+		s.update(strconv.Itoa(s.elem.ScrollTop()))
+	})
+}
+
+// scrollTo scrolls the element to the position given by the model,
+// it does nothing if the value is not a number
+func (s *scrollSync) scrollTo(value interface{}) {
+	f, err := strconv.ParseFloat(toString(value), 64)
+	if err != nil {
+		return
+	}
+
+	if top := int(f); top != s.elem.ScrollTop() {
+		s.elem.SetScrollTop(top)
+	}
+}
+
+// ScrollBinder is a 2-way binder that binds the vertical scroll position (scrollTop)
+// of an element to a numeric model field. The field is updated as the user scrolls,
+// at most once every ScrollThrottle, and the element is scrolled when the field changes.
+// It takes no extra dash args.
+//
+// Usage:
+//	bind-scroll="Field"
+// Example:
+//	<div class="article" bind-scroll="ScrollTop">...</div>
+type ScrollBinder struct {
+	BaseBinder
+	update ModelUpdateFn
+	sync   *scrollSync
+}
+
+func (b *ScrollBinder) Watch(elem jq.JQuery, ufn ModelUpdateFn) {
+	b.update = ufn
+	elem.On("scroll", func(evt jq.Event) {
+		b.sync.scrolled()
+	})
+}
+
+func (b *ScrollBinder) Bind(d DomBind) {
+	update := b.update
+	if update == nil {
+		update = func(string) {}
+	}
+	b.sync = &scrollSync{elem: d.Elem, browser: d.binding.browser, update: update}
+}
+
+func (b *ScrollBinder) Update(d DomBind) {
+	b.sync.scrollTo(d.Value)
+}
+
+func (b *ScrollBinder) BindInstance() DomBinder { return new(ScrollBinder) }
+func (b *ScrollBinder) TwoWay()                 {}
@@ -0,0 +1,60 @@
+package bind
+
+import (
+	"testing"
+)
+
+type testArticle struct {
+	ScrollTop int
+}
+
+func TestScrollBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	br := newFakeBrowser()
+	b.browser = br
+	model := &testArticle{40}
+	elem := gJQ(`<div><div class="article" bind-scroll="ScrollTop"></div></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	article := elem.Find(".article")
+	if article.ScrollTop() != 40 {
+		t.Fatalf("Expected the element to be scrolled to the model's value, got %v.", article.ScrollTop())
+	}
+
+	// the scroll events are throttled
+	article.SetScrollTop(10).Trigger("scroll")
+	article.SetScrollTop(60).Trigger("scroll")
+	article.Trigger("scroll")
+	if model.ScrollTop != 40 || br.pending() != 1 {
+		t.Fatalf("Expected the scroll events to be throttled, got %v with %v pending.", model.ScrollTop, br.pending())
+	}
+	br.advance(ScrollThrottle)
+	if model.ScrollTop != 60 {
+		t.Errorf("Expected one update with the latest position, got %v.", model.ScrollTop)
+	}
+	article.SetScrollTop(80).Trigger("scroll")
+	br.advance(ScrollThrottle)
+	if model.ScrollTop != 80 {
+		t.Errorf("Expected the model to follow the scrolling, got %v.", model.ScrollTop)
+	}
+
+	model.ScrollTop = 120
+	w.change()
+	if article.ScrollTop() != 120 {
+		t.Errorf("Expected the element to be scrolled when the model changes, got %v.", article.ScrollTop())
+	}
+	article.SetScrollTop(30)
+	w.change()
+	if article.ScrollTop() != 120 {
+		t.Errorf("Expected the element to be scrolled back to the model's value, got %v.", article.ScrollTop())
+	}
+
+	// a value that's not a number is ignored
+	(&ScrollBinder{sync: &scrollSync{elem: article}}).Update(DomBind{Value: "abc"})
+	if article.ScrollTop() != 120 {
+		t.Errorf("Expected the element not to be scrolled, got %v.", article.ScrollTop())
+	}
+}
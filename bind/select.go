@@ -0,0 +1,80 @@
+package bind
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+// option returns the value of the option and whether it's selected
+func option(opt jq.JQuery) (value string, selected bool) {
+	selected, _ = opt.Prop("selected").(bool)
+	return opt.Val(), selected
+}
+
+func isMultiSelect(elem jq.JQuery) bool {
+	tagname, _ := elem.Prop("tagName").(string)
+	multiple, _ := elem.Prop("multiple").(bool)
+	return strings.ToUpper(tagname) == "SELECT" && multiple
+}
+
+// selectedValues returns the values of the selected options, in the order
+// of the options and without duplicates
+func selectedValues(options jq.JQuery) []string {
+	values := make([]string, 0)
+	seen := make(map[string]bool)
+	for i := 0; i < options.Length; i++ {
+		if value, selected := option(options.Eq(i)); selected && !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// selectValues selects the options whose value is an item of the slice,
+// and unselects the others
+func selectValues(options jq.JQuery, slice interface{}) {
+	set := make(map[string]bool)
+	if v := reflect.ValueOf(slice); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		for i := 0; i < v.Len(); i++ {
+			set[toString(v.Index(i).Interface())] = true
+		}
+	}
+
+	for i := 0; i < options.Length; i++ {
+		opt := options.Eq(i)
+		if value, selected := option(opt); set[value] != selected {
+			opt.SetProp("selected", set[value])
+		}
+	}
+}
+
+// encodeSelection encodes the selected values for the model update function,
+// convertString decodes them to a slice
+func encodeSelection(values []string) string {
+	b, _ := json.Marshal(values)
+	return string(b)
+}
+
+// convertSlice decodes the values of a multiple select, encoded as a json
+// array of strings, to a slice of the given type
+func convertSlice(s string, typ reflect.Type) (v reflect.Value, err error) {
+	var values []string
+	if err = json.Unmarshal([]byte(s), &values); err != nil {
+		return
+	}
+
+	v = reflect.MakeSlice(typ, 0, len(values))
+	for _, value := range values {
+		var item reflect.Value
+		item, err = convertString(value, typ.Elem())
+		if err != nil {
+			return
+		}
+		v = reflect.Append(v, item)
+	}
+	return
+}
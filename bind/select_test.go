@@ -0,0 +1,69 @@
+package bind
+
+import (
+	"reflect"
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+type testMultiSelect struct {
+	Tags []string
+	Ids  []int
+}
+
+func TestMultiSelect(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	model := &testMultiSelect{Tags: []string{"js", "css", "js"}}
+	elem := gJQ(`<div><select multiple bind-value="Tags"><option value="go">Go</option><option value="js">JS</option><option value="css">CSS</option><option value="go">Golang</option></select><select class="ids" multiple bind-value="Ids"><option value="1">1</option><option value="7">7</option></select></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	options := elem.Find("select").First().Find("option")
+	selected := func() (s []bool) {
+		for i := 0; i < options.Length; i++ {
+			_, sel := option(options.Eq(i))
+			s = append(s, sel)
+		}
+		return
+	}
+	if !reflect.DeepEqual(selected(), []bool{false, true, true, false}) {
+		t.Errorf("Expected the selection to follow the slice, got %v.", selected())
+	}
+
+	options.Eq(1).SetProp("selected", false)
+	options.Eq(0).SetProp("selected", true)
+	options.Eq(3).SetProp("selected", true)
+	options.Parent().Trigger(jq.CHANGE)
+	if !reflect.DeepEqual(model.Tags, []string{"go", "css"}) {
+		t.Errorf("Expected the selected values in order without duplicates, got %v.", model.Tags)
+	}
+
+	model.Tags = []string{}
+	w.change()
+	if len(selectedValues(options)) != 0 {
+		t.Errorf("Expected all options to be unselected, got %v.", selected())
+	}
+
+	ids := elem.Find(".ids option")
+	model.Ids = []int{7}
+	w.change()
+	if values := selectedValues(ids); !reflect.DeepEqual(values, []string{"7"}) {
+		t.Errorf("Expected the options to be selected by the items' string values, got %v.", values)
+	}
+	ids.Eq(0).SetProp("selected", true)
+	ids.Parent().Trigger(jq.CHANGE)
+	if !reflect.DeepEqual(model.Ids, []int{1, 7}) {
+		t.Errorf("Expected the values to be converted to the item type, got %v.", model.Ids)
+	}
+
+	v, err := convertString(`["3","1"]`, reflect.TypeOf(model.Ids))
+	if err != nil || !reflect.DeepEqual(v.Interface(), []int{3, 1}) {
+		t.Errorf("Expected the values to be converted to the item type, got %v (%v).", v, err)
+	}
+	if _, err := convertString(`["a"]`, reflect.TypeOf(model.Ids)); err == nil {
+		t.Errorf("Expected an error for an invalid item.")
+	}
+}
@@ -0,0 +1,154 @@
+package bind
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/gopherjs/gopherjs/js"
+	jq "github.com/gopherjs/jquery"
+)
+
+// SizeObserver watches the size of elements, it's used by the size binders
+type SizeObserver interface {
+	// Observe calls fn with the width and height of the element, in pixels, each time
+	// it's resized. It returns a function that stops observing.
+	Observe(elem jq.JQuery, fn func(width, height float64)) (stop func())
+}
+
+// resizeObserver is the default SizeObserver, using the browser's ResizeObserver.
+// The size is measured once if it's not available.
+type resizeObserver struct{}
+
+func (o resizeObserver) Observe(elem jq.JQuery, fn func(width, height float64)) (stop func()) {
+	roClass := js.Global.Get("ResizeObserver")
+	if roClass.IsUndefined() {
+		fn(float64(elem.Width()), float64(elem.Height()))
+		return func() {}
+	}
+
+	ro := roClass.New(func(entries js.Object) {
+		for i := 0; i < entries.Length(); i++ {
+			rect := entries.Index(i).Get("contentRect")
+			fn(rect.Get("width").Float(), rect.Get("height").Float())
+		}
+	})
+	ro.Call("observe", elem.Get(0))
+	return func() {
+		ro.Call("disconnect")
+	}
+}
+
+// SetSizeObserver sets the observer used by the size binders
+func (b *Binding) SetSizeObserver(o SizeObserver) {
+	b.resize = o
+}
+
+// sizeValue converts a dimension to the type of the model field,
+// it's rounded for integer fields
+func sizeValue(f float64, typ reflect.Type) (v reflect.Value, err error) {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.ValueOf(math.Floor(f + 0.5)).Convert(typ), nil
+	case reflect.Float32, reflect.Float64:
+		return reflect.ValueOf(f).Convert(typ), nil
+	case reflect.Interface:
+		if typ.NumMethod() == 0 {
+			return reflect.ValueOf(f), nil
+		}
+	}
+
+	err = fmt.Errorf("Wrong type %v of the field for the size binder, must be a number.", typ)
+	return
+}
+
+// sizeField returns the model field that a dimension is written to
+func sizeField(d DomBind, binds []bindable) *objEval {
+	if len(binds) != 1 {
+		d.Panic("A dimension of the size binder must be bound to exactly 1 model field.")
+	}
+
+	oe := binds[0].bindObj()
+	if !oe.canSet() {
+		d.Panic("The field of the size binder cannot be set.")
+	}
+	if _, err := sizeValue(0, oe.typ()); err != nil {
+		d.Panic(err.Error())
+	}
+	return oe
+}
+
+// sizeSync writes the measured dimensions of an element to the model fields,
+// a nil field is not written
+type sizeSync struct {
+	width, height *objEval
+}
+
+func (s sizeSync) resized(width, height float64) {
+	for _, dim := range []struct {
+		oe *objEval
+		f  float64
+	}{{s.width, width}, {s.height, height}} {
+		if dim.oe == nil {
+			continue
+		}
+		v, _ := sizeValue(dim.f, dim.oe.typ())
+		dim.oe.set(v)
+	}
+}
+
+// SizeBinder is a 1-way binder that writes the measured size of an element to model
+// fields, for responsive canvases for example. The fields are updated each time the
+// element is resized, they must be numbers (integer fields are rounded).
+// The size binder takes the width field, and the height field after "->",
+// the width and height binders take a single field.
+// They take no extra dash args.
+//
+// Usage:
+//	bind-size="WidthField -> HeightField"
+//	bind-width="WidthField"
+//	bind-height="HeightField"
+// Example:
+//	<canvas bind-size="Chart.Width -> Chart.Height"></canvas>
+type SizeBinder struct {
+	BaseBinder
+	width, height bool
+	stop          func()
+}
+
+func (b *SizeBinder) Bind(d DomBind) {
+	var sync sizeSync
+	switch {
+	case b.width && b.height:
+		if len(d.outputs) != 1 {
+			d.Panic("The size binder takes the height field after ->.")
+		}
+		bs := &bindScope{d.scope}
+		_, binds, _, err := bs.evaluate(d.outputs[0])
+		if err != nil {
+			d.Panic(err.Error())
+		}
+		sync.width = sizeField(d, d.binds)
+		sync.height = sizeField(d, binds)
+	case b.width:
+		sync.width = sizeField(d, d.binds)
+	default:
+		sync.height = sizeField(d, d.binds)
+	}
+
+	b.stop = d.binding.resize.Observe(d.Elem, sync.resized)
+}
+
+func (b *SizeBinder) Update(d DomBind) {}
+
+func (b *SizeBinder) Teardown(d DomBind) {
+	if b.stop != nil {
+		b.stop()
+		b.stop = nil
+	}
+}
+
+func (b *SizeBinder) BindInstance() DomBinder {
+	return &SizeBinder{width: b.width, height: b.height}
+}
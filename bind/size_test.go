@@ -0,0 +1,89 @@
+package bind
+
+import (
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+type fakeSizeObserver struct {
+	fn      func(width, height float64)
+	stopped bool
+}
+
+func (o *fakeSizeObserver) Observe(elem jq.JQuery, fn func(width, height float64)) func() {
+	o.fn = fn
+	return func() {
+		o.stopped = true
+	}
+}
+
+type testChartArea struct {
+	W     int
+	H     float64
+	Label string
+}
+
+func TestSizeBinder(t *testing.T) {
+	obs := &fakeSizeObserver{}
+	b := NewBindEngine(nil)
+	b.SetSizeObserver(obs)
+
+	m := &testChartArea{}
+	bs := &bindScope{newModelScope(m)}
+	bs.scope.merge(b.scope)
+	domBind := func(bstr string, outputs ...string) DomBind {
+		_, binds, v, err := bs.evaluate(bstr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return DomBind{Elem: jq.JQuery{Length: 1}, Value: v, outputs: outputs, binding: b, scope: bs.scope, binds: binds}
+	}
+
+	sb := b.domBinders["size"].BindInstance().(*SizeBinder)
+	d := domBind("W", "H")
+	sb.Bind(d)
+	obs.fn(320.6, 240.5)
+	if m.W != 321 || m.H != 240.5 {
+		t.Errorf("Expected the model to receive the dimensions, got %v x %v.", m.W, m.H)
+	}
+	obs.fn(100, 50)
+	if m.W != 100 || m.H != 50 {
+		t.Errorf("Expected the model to be updated on resize, got %v x %v.", m.W, m.H)
+	}
+	sb.Teardown(d)
+	if !obs.stopped {
+		t.Errorf("Expected the observation to be stopped on teardown.")
+	}
+
+	m.W, m.H = 0, 0
+	b.domBinders["height"].BindInstance().Bind(domBind("H"))
+	obs.fn(80, 60)
+	if m.W != 0 || m.H != 60 {
+		t.Errorf("Expected only the height to be written, got %v x %v.", m.W, m.H)
+	}
+
+	b.domBinders["width"].BindInstance().Bind(domBind("W"))
+	obs.fn(80, 70)
+	if m.W != 80 || m.H != 60 {
+		t.Errorf("Expected only the width to be written, got %v x %v.", m.W, m.H)
+	}
+
+	for _, test := range []struct {
+		binder, bstr string
+		outputs      []string
+	}{
+		{"size", "W", nil},
+		{"width", "Label", nil},
+		{"size", "W", []string{"Label"}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected a panic for bind-%v=%q with %v.", test.binder, test.bstr, test.outputs)
+				}
+			}()
+			b.domBinders[test.binder].BindInstance().Bind(domBind(test.bstr, test.outputs...))
+		}()
+	}
+}
@@ -0,0 +1,70 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// srcsetString builds the value of a srcset attribute from a slice of image
+// descriptors, structs (or pointers to structs) with a URL string field and a
+// positive integer Width field, like "small.jpg 480w, large.jpg 1080w"
+func srcsetString(images interface{}) (string, error) {
+	v := unwrapValue(reflect.ValueOf(images))
+	if !v.IsValid() {
+		return "", nil
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", fmt.Errorf("Wrong type %v for the srcset binder, must be a slice.", v.Type())
+	}
+
+	candidates := make([]string, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		item := unwrapValue(v.Index(i))
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				return "", fmt.Errorf("The image %v of the srcset is nil.", i)
+			}
+			item = item.Elem()
+		}
+		if item.Kind() != reflect.Struct {
+			return "", fmt.Errorf("Wrong type %v of the image %v of the srcset, must be a struct with the URL and Width fields.", item.Type(), i)
+		}
+
+		url, width := item.FieldByName("URL"), item.FieldByName("Width")
+		if url.Kind() != reflect.String || !isIntKind(width.Kind()) {
+			return "", fmt.Errorf("The image %v of the srcset must have a URL string field and a Width integer field.", i)
+		}
+		if url.String() == "" || strings.ContainsAny(url.String(), " ,") {
+			return "", fmt.Errorf(`Invalid url "%v" of the image %v of the srcset.`, url.String(), i)
+		}
+		if toInt64(width) <= 0 {
+			return "", fmt.Errorf("The width of the image %v of the srcset must be positive.", i)
+		}
+
+		candidates = append(candidates, url.String()+" "+strconv.FormatInt(toInt64(width), 10)+"w")
+	}
+	return strings.Join(candidates, ", "), nil
+}
+
+// SrcsetBinder is a 1-way binder that sets the srcset attribute of an <img> or
+// <source> element from a slice of image descriptors, structs with a URL string
+// field and a Width integer field (the image's width in pixels).
+// It takes no extra dash args.
+//
+// Usage:
+//	bind-srcset="ImagesExpression"
+// Example:
+//	<img bind-attr-src="Photo.Thumb" bind-srcset="Photo.Sizes" sizes="(max-width: 600px) 480px, 800px">
+type SrcsetBinder struct{ BaseBinder }
+
+func (b *SrcsetBinder) Update(d DomBind) {
+	srcset, err := srcsetString(d.Value)
+	if err != nil {
+		d.Panic(err.Error())
+	}
+	d.Elem.SetAttr("srcset", srcset)
+}
+
+func (b *SrcsetBinder) BindInstance() DomBinder { return b }
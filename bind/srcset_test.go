@@ -0,0 +1,61 @@
+package bind
+
+import "testing"
+
+type testImageSize struct {
+	URL   string
+	Width int
+}
+
+type testPhoto struct {
+	Sizes []*testImageSize
+}
+
+func TestSrcset(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testPhoto{Sizes: []*testImageSize{
+		{"/img/cat-480.jpg", 480},
+		{"/img/cat-1080.jpg", 1080},
+	}}
+	v, err := b.Eval(model, "Sizes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err := srcsetString(v); err != nil || s != "/img/cat-480.jpg 480w, /img/cat-1080.jpg 1080w" {
+		t.Errorf("Expected the srcset to be built from the descriptors, got %q (%v).", s, err)
+	}
+
+	if s, err := srcsetString([]testImageSize{}); err != nil || s != "" {
+		t.Errorf("Expected an empty srcset for no images, got %q (%v).", s, err)
+	}
+	if s, err := srcsetString(nil); err != nil || s != "" {
+		t.Errorf("Expected an empty srcset for nil, got %q (%v).", s, err)
+	}
+
+	invalid := []interface{}{
+		"/img/cat.jpg 480w",
+		[]string{"/img/cat.jpg"},
+		[]struct{ URL string }{{"/img/cat.jpg"}},
+		[]struct {
+			URL   string
+			Width string
+		}{{"/img/cat.jpg", "480"}},
+		[]testImageSize{{"/img/cat.jpg", 0}},
+		[]testImageSize{{"", 480}},
+		[]testImageSize{{"/img/my cat.jpg", 480}},
+		[]*testImageSize{nil},
+	}
+	for _, images := range invalid {
+		if _, err := srcsetString(images); err == nil {
+			t.Errorf("Expected an error for %v.", images)
+		}
+	}
+
+	d := DomBind{Value: []int{1}, metadata: `bind-srcset="Sizes"`}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected the binder to panic for invalid images.")
+		}
+	}()
+	new(SrcsetBinder).Update(d)
+}
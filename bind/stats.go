@@ -0,0 +1,100 @@
+package bind
+
+import (
+	"time"
+)
+
+// BinderStats are the statistics of the updates performed by a binder
+type BinderStats struct {
+	// Updates is the number of calls of the binder's Update
+	Updates int
+	// Total is the time spent in the updates
+	Total time.Duration
+	// Max is the time taken by the longest update
+	Max time.Duration
+}
+
+// Stats are the statistics of the updates collected by the binding when
+// CollectStats is set. A flush is a run of the updates triggered together,
+// until the control is returned to the browser.
+type Stats struct {
+	// Binders are the statistics of each binder, by name (like "text")
+	Binders map[string]BinderStats
+	// Flushes is the number of flushes
+	Flushes int
+	// LastFlush and MaxFlush are the number of updates in the last flush
+	// and in the largest one
+	LastFlush int
+	MaxFlush  int
+}
+
+// statsCollector records the updates, timed with the browser's clock
+type statsCollector struct {
+	stats    Stats
+	flushing bool
+	current  int
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{
+		stats: Stats{Binders: make(map[string]BinderStats)},
+	}
+}
+
+// record performs the update of the binder, recording its duration.
+// The flush ends when the control is returned to the browser.
+func (c *statsCollector) record(br browser, binder string, update func()) {
+	start := br.now()
+	update()
+	d := br.now().Sub(start)
+
+	bs := c.stats.Binders[binder]
+	bs.Updates++
+	bs.Total += d
+	if d > bs.Max {
+		bs.Max = d
+	}
+	c.stats.Binders[binder] = bs
+
+	c.current++
+	if !c.flushing {
+		c.flushing = true
+		br.after(0, c.endFlush)
+	}
+}
+
+func (c *statsCollector) endFlush() {
+	c.stats.Flushes++
+	c.stats.LastFlush = c.current
+	if c.current > c.stats.MaxFlush {
+		c.stats.MaxFlush = c.current
+	}
+	c.current, c.flushing = 0, false
+}
+
+func (c *statsCollector) snapshot() Stats {
+	s := c.stats
+	s.Binders = make(map[string]BinderStats, len(c.stats.Binders))
+	for name, bs := range c.stats.Binders {
+		s.Binders[name] = bs
+	}
+	return s
+}
+
+// Stats returns the statistics of the updates collected so far,
+// they are only collected when CollectStats is set
+func (b *Binding) Stats() Stats {
+	return b.stats.snapshot()
+}
+
+// runUpdate calls the binder's Update, recording it if CollectStats is set
+func (b *Binding) runUpdate(name string, binder DomBinder, d DomBind) {
+	if !b.CollectStats {
+		binder.Update(d)
+		return
+	}
+
+	b.stats.record(b.browser, name, func() {
+		binder.Update(d)
+	})
+}
@@ -0,0 +1,87 @@
+package bind
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsCollector(t *testing.T) {
+	c := newStatsCollector()
+	br := newFakeBrowser()
+	update := func(d time.Duration) func() {
+		return func() { br.time = br.time.Add(d) }
+	}
+
+	c.record(br, "text", update(2*time.Millisecond))
+	c.record(br, "text", update(5*time.Millisecond))
+	c.record(br, "class", update(time.Millisecond))
+	if br.pending() != 1 {
+		t.Fatalf("Expected the end of the flush to be deferred once, got %v.", br.pending())
+	}
+	br.advance(0)
+	c.record(br, "text", update(3*time.Millisecond))
+	br.advance(0)
+
+	s := c.snapshot()
+	text := s.Binders["text"]
+	if text.Updates != 3 || text.Total != 10*time.Millisecond || text.Max != 5*time.Millisecond {
+		t.Errorf("Wrong stats for the text binder: %+v.", text)
+	}
+	if class := s.Binders["class"]; class.Updates != 1 || class.Total != time.Millisecond {
+		t.Errorf("Wrong stats for the class binder: %+v.", class)
+	}
+	if s.Flushes != 2 || s.LastFlush != 1 || s.MaxFlush != 3 {
+		t.Errorf("Wrong flush stats: %+v.", s)
+	}
+
+	s.Binders["text"] = BinderStats{}
+	if c.snapshot().Binders["text"].Updates != 3 {
+		t.Errorf("Expected the stats to be a copy.")
+	}
+}
+
+// slowBinder is a text binder whose updates take some time
+type slowBinder struct {
+	TextBinder
+	browser *fakeBrowser
+}
+
+func (b *slowBinder) Update(d DomBind) {
+	b.browser.time = b.browser.time.Add(4 * time.Millisecond)
+	b.TextBinder.Update(d)
+}
+
+func (b *slowBinder) BindInstance() DomBinder { return b }
+
+func TestCollectStats(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	br := newFakeBrowser()
+	b.browser = br
+	b.domBinders["slow"] = &slowBinder{browser: br}
+	model := &testBadge{Label: "new"}
+	elem := gJQ(`<div><span bind-text="Label"></span><em bind-slow="Label"></em></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	if elem.Find("em").Text() != "new" || len(b.Stats().Binders) != 0 || br.pending() != 0 {
+		t.Errorf("Expected the updates to run without collecting stats, got %+v.", b.Stats())
+	}
+
+	b.CollectStats = true
+	model.Label = "hot"
+	w.change()
+	br.advance(0)
+	w.change()
+	s := b.Stats()
+	if text := s.Binders["text"]; text.Updates != 2 || text.Total != 0 {
+		t.Errorf("Wrong stats for the text binder: %+v.", text)
+	}
+	if slow := s.Binders["slow"]; slow.Updates != 2 || slow.Total != 8*time.Millisecond || slow.Max != 4*time.Millisecond {
+		t.Errorf("Wrong stats for the slow binder: %+v.", slow)
+	}
+	if s.Flushes != 1 || s.LastFlush != 2 || elem.Find("span").Text() != "hot" {
+		t.Errorf("Wrong flush stats: %+v.", s)
+	}
+}
@@ -0,0 +1,224 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// StreamBinder is a 1-way binder that sets an element's text content to the
+// latest value received on a channel of the model, for live logs or tickers.
+// A goroutine reads the channel until it's closed or the element is torn down, each
+// value is set when the control is returned to the browser. The text keeps the last
+// value when the channel is closed. If the field is set to another channel, the new
+// one is read instead.
+// It takes no extra dash args.
+//
+// Usage:
+//	bind-stream="Expression"
+// Example:
+//	<span bind-stream="LastEvent"></span>
+type StreamBinder struct {
+	BaseBinder
+	ch   reflect.Value
+	stop chan struct{}
+}
+
+func (b *StreamBinder) Update(d DomBind) {
+	v := reflect.ValueOf(d.Value)
+	if v.Kind() != reflect.Chan {
+		d.Elem.SetText(d.ValueString())
+		return
+	}
+
+	if b.ch.IsValid() && b.ch.Pointer() == v.Pointer() {
+		return
+	}
+	if v.Type().ChanDir()&reflect.RecvDir == 0 {
+		d.Panic("Cannot receive from a send-only channel.")
+	}
+
+	b.stopReading()
+	b.ch = v
+	stop := make(chan struct{})
+	b.stop = stop
+	br := d.binding.browser
+	go receive(v, stop, func(value interface{}) {
+		br.after(0, func() {
+			// dropped if another channel is read or the element is torn down
+			if b.stop == stop {
+				d.Value = value
+				b.Update(d)
+			}
+		})
+	})
+}
+
+func (b *StreamBinder) stopReading() {
+	if b.stop != nil {
+		close(b.stop)
+		b.stop = nil
+	}
+}
+
+func (b *StreamBinder) Teardown(d DomBind) {
+	b.stopReading()
+}
+
+func (b *StreamBinder) BindInstance() DomBinder { return new(StreamBinder) }
+
+// receive calls fn with each value received on the channel, until the channel is
+// closed or stop is closed. A value received after stop is closed is dropped.
+func receive(ch reflect.Value, stop <-chan struct{}, fn func(interface{})) {
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(stop)},
+	}
+	for {
+		chosen, v, ok := reflect.Select(cases)
+		if chosen == 1 || !ok {
+			return
+		}
+
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		var value interface{}
+		if v.IsValid() {
+			value = v.Interface()
+		}
+		fn(value)
+	}
+}
+
+// streamFeed builds a list from the messages received on a channel. The messages of
+// a channel of slices replace the list, the other messages are appended to it.
+// The messages are read in a goroutine, and handled when the control is returned
+// to the browser.
+type streamFeed struct {
+	browser browser
+	ch      reflect.Value
+	items   reflect.Value
+	render  func(items interface{})
+	stop    chan struct{}
+	// done is closed when the feed stops reading, because the channel
+	// is closed or the feed is stopped
+	done chan struct{}
+}
+
+func newStreamFeed(br browser, ch reflect.Value, render func(items interface{})) *streamFeed {
+	itemsType := ch.Type().Elem()
+	if itemsType.Kind() != reflect.Slice {
+		itemsType = reflect.SliceOf(itemsType)
+	}
+	return &streamFeed{
+		browser: br,
+		ch:      ch,
+		items:   reflect.MakeSlice(itemsType, 0, 0),
+		render:  render,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// start renders the empty list and reads the channel in a goroutine
+func (f *streamFeed) start() {
+	f.render(f.items.Interface())
+	go func() {
+		receive(f.ch, f.stop, func(msg interface{}) {
+			f.browser.after(0, func() {
+				f.received(msg)
+			})
+		})
+		close(f.done)
+	}()
+}
+
+// received adds the message to the list, it's dropped if the feed has been stopped
+func (f *streamFeed) received(msg interface{}) {
+	select {
+	case <-f.stop:
+		return
+	default:
+	}
+
+	elemType := f.ch.Type().Elem()
+	v := reflect.ValueOf(msg)
+	if !v.IsValid() {
+		v = reflect.Zero(elemType)
+	}
+
+	if elemType.Kind() == reflect.Slice {
+		f.items = v
+	} else {
+		f.items = reflect.Append(f.items, v)
+	}
+	f.render(f.items.Interface())
+}
+
+// close stops reading the channel, it may be called more than once
+func (f *streamFeed) close() {
+	select {
+	case <-f.stop:
+	default:
+		close(f.stop)
+	}
+}
+
+// FeedBinder is a 1-way binder that repeats an element for a list fed by a channel,
+// typically returned by a model method, for server-sent events for example.
+// Each value received on a channel of slices (like <-chan []Message) replaces the list,
+// the values received on other channels (like <-chan Message) are appended to it.
+// A goroutine reads the channel until it's closed or the element is torn down, the
+// list is updated when the control is returned to the browser. The list is kept when
+// the channel is closed. The expression is evaluated once, when
+// the element is bound, and it's not watched: a method giving the channel is called once.
+// It takes the same dash args and outputs as the each binder.
+//
+// Usage:
+//	bind-feed="ChannelExpression -> outputKey, outputValue"
+// Example:
+//	<li bind-feed="Notifications() -> _, n"><% n.Text %></li>
+type FeedBinder struct {
+	BaseBinder
+	each *EachBinder
+	feed *streamFeed
+}
+
+func (b *FeedBinder) OneShot() {}
+
+func (b *FeedBinder) Bind(d DomBind) {
+	value, err := d.Eval(d.expr)
+	if err != nil {
+		d.Panic(err.Error())
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Chan {
+		d.Panic(fmt.Sprintf("Wrong type %v for the feed binder, must be a channel.", reflect.TypeOf(value)))
+	}
+	if v.Type().ChanDir()&reflect.RecvDir == 0 {
+		d.Panic("Cannot receive from a send-only channel.")
+	}
+
+	d.Elem.RemoveAttr(BindPrefix + strings.Join(append([]string{"feed"}, d.Args...), "-"))
+	b.each = new(EachBinder)
+	d.Value = []interface{}{}
+	b.each.Bind(d)
+
+	b.feed = newStreamFeed(d.binding.browser, v, func(items interface{}) {
+		d.Value = items
+		b.each.Update(d)
+	})
+	b.feed.start()
+}
+
+func (b *FeedBinder) Teardown(d DomBind) {
+	if b.feed != nil {
+		b.feed.close()
+	}
+}
+
+func (b *FeedBinder) BindInstance() DomBinder { return new(FeedBinder) }
@@ -0,0 +1,215 @@
+package bind
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReceive(t *testing.T) {
+	ch := make(chan string)
+	texts := make(chan interface{})
+	done := make(chan bool)
+	stop := make(chan struct{})
+	go func() {
+		receive(reflect.ValueOf(ch), stop, func(v interface{}) {
+			texts <- v
+		})
+		done <- true
+	}()
+
+	for _, msg := range []string{"started", "step 1", "finished"} {
+		ch <- msg
+		if text := <-texts; text != msg {
+			t.Errorf("Expected the text to be %v, got %v.", msg, text)
+		}
+	}
+
+	close(ch)
+	<-done
+
+	// after a teardown
+	ch = make(chan string, 1)
+	go func() {
+		receive(reflect.ValueOf(ch), stop, func(v interface{}) {
+			t.Errorf("Unexpected update with %v after the teardown.", v)
+		})
+		done <- true
+	}()
+	close(stop)
+	ch <- "late"
+	<-done
+}
+
+type testMessage struct {
+	Text string
+}
+
+func TestStreamFeed(t *testing.T) {
+	br := newFakeBrowser()
+	ch := make(chan testMessage)
+	var renders []interface{}
+	feed := newStreamFeed(br, reflect.ValueOf((<-chan testMessage)(ch)), func(items interface{}) {
+		renders = append(renders, items)
+	})
+	feed.start()
+	if len(renders) != 1 || len(renders[0].([]testMessage)) != 0 {
+		t.Errorf("Expected the empty list to be rendered first, got %v.", renders)
+	}
+
+	for i, text := range []string{"hello", "how are you", "bye"} {
+		ch <- testMessage{text}
+		br.wait(1)
+		if len(renders) != i+1 {
+			t.Errorf("Expected the message %q to wait for the browser, got %v.", text, renders)
+		}
+		br.advance(0)
+		items := renders[len(renders)-1].([]testMessage)
+		if len(items) != i+1 || items[i].Text != text {
+			t.Errorf("Expected the message %q to be appended, got %v.", text, items)
+		}
+	}
+
+	close(ch)
+	<-feed.done
+	if feed.items.Len() != 3 || br.pending() != 0 {
+		t.Errorf("Expected the list to be kept when the channel is closed.")
+	}
+
+	// a channel of slices replaces the list
+	sch := make(chan []string)
+	renders = nil
+	feed = newStreamFeed(br, reflect.ValueOf(sch), func(items interface{}) {
+		renders = append(renders, items)
+	})
+	feed.start()
+	sch <- []string{"a", "b"}
+	sch <- []string{"c"}
+	br.wait(2)
+	br.advance(0)
+	if items := renders[len(renders)-1].([]string); len(renders) != 3 || len(items) != 1 || items[0] != "c" {
+		t.Errorf("Expected the list to be replaced, got %v.", renders)
+	}
+
+	// a message received before the teardown is dropped
+	sch <- []string{"late"}
+	br.wait(1)
+	feed.close()
+	br.advance(0)
+	if len(renders) != 3 {
+		t.Errorf("Unexpected render after the teardown: %v.", renders)
+	}
+
+	// teardown stops the goroutine while it's waiting for a message
+	feed.close()
+	<-feed.done
+}
+
+type testInbox struct {
+	Unread int
+	calls  int
+	ch     chan testMessage
+}
+
+func (m *testInbox) Messages() <-chan testMessage {
+	m.calls++
+	m.ch = make(chan testMessage)
+	return m.ch
+}
+
+func TestFeedBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	br := newFakeBrowser()
+	b.browser = br
+	model := &testInbox{}
+	elem := gJQ(`<ul><li bind-feed="Messages() -> _, m"><span bind-text="m.Text"></span></li></ul>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+	b.Bind(elem, model, false, false)
+	if model.calls != 1 || elem.Find("li").Length != 0 {
+		t.Fatalf("Expected the channel to be taken once and the list to be empty, got %v calls.", model.calls)
+	}
+
+	ch := model.ch
+	ch <- testMessage{"hello"}
+	br.wait(1)
+	br.advance(0)
+	if elem.Find("li").Length != 1 || elem.Find("li").Text() != "hello" {
+		t.Fatalf("Expected the message to be rendered, got %v.", elem.Html())
+	}
+
+	// the expression is not reevaluated when the model changes,
+	// the list is kept and the same channel is read
+	model.Unread = 2
+	w.change()
+	if len(w.targets) != 0 || model.calls != 1 || model.ch != ch {
+		t.Errorf("Expected the channel expression not to be watched, got %v calls.", model.calls)
+	}
+	ch <- testMessage{"bye"}
+	br.wait(1)
+	br.advance(0)
+	if elem.Find("li").Length != 2 || elem.Find("li").Text() != "hellobye" {
+		t.Errorf("Expected the messages to be appended to the list, got %v.", elem.Html())
+	}
+
+	// the list is kept when the channel is closed
+	close(ch)
+	br.advance(0)
+	if elem.Find("li").Length != 2 {
+		t.Errorf("Expected the list to be kept, got %v.", elem.Html())
+	}
+}
+
+type testTicker struct {
+	Last <-chan string
+}
+
+func TestStreamBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	br := newFakeBrowser()
+	b.browser = br
+	ch := make(chan string)
+	model := &testTicker{ch}
+	elem := gJQ(`<div><span bind-stream="Last"></span></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	span := elem.Find("span")
+	ch <- "started"
+	br.wait(1)
+	if span.Text() != "" {
+		t.Errorf("Expected the value to wait for the browser, got %q.", span.Text())
+	}
+	br.advance(0)
+	if span.Text() != "started" {
+		t.Errorf("Expected the received value to be set, got %q.", span.Text())
+	}
+
+	// another channel is read instead, a value of the old one still waiting is dropped
+	ch <- "step 1"
+	br.wait(1)
+	next := make(chan string)
+	model.Last = next
+	w.change()
+	br.advance(0)
+	if span.Text() != "started" {
+		t.Errorf("Expected the value of the old channel to be dropped, got %q.", span.Text())
+	}
+	next <- "step 2"
+	br.wait(1)
+	br.advance(0)
+	if span.Text() != "step 2" {
+		t.Errorf("Expected the new channel to be read, got %q.", span.Text())
+	}
+
+	// teardown stops the reading
+	next <- "finished"
+	br.wait(1)
+	b.Teardown(elem)
+	br.advance(0)
+	if span.Text() != "step 2" {
+		t.Errorf("Expected no update after the teardown, got %q.", span.Text())
+	}
+}
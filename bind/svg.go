@@ -0,0 +1,56 @@
+package bind
+
+import (
+	"strings"
+)
+
+const (
+	XLinkNamespace = "http://www.w3.org/1999/xlink"
+	XMLNamespace   = "http://www.w3.org/XML/1998/namespace"
+	XMLNSNamespace = "http://www.w3.org/2000/xmlns/"
+)
+
+var (
+	// svgCaseSensitiveAttrs are the SVG attributes having uppercase letters,
+	// mapped from their lowercase versions
+	svgCaseSensitiveAttrs = make(map[string]string)
+)
+
+func init() {
+	for _, attr := range []string{
+		"attributeName", "attributeType", "baseFrequency", "baseProfile", "calcMode",
+		"clipPathUnits", "diffuseConstant", "edgeMode", "filterUnits", "glyphRef",
+		"gradientTransform", "gradientUnits", "kernelMatrix", "kernelUnitLength",
+		"keyPoints", "keySplines", "keyTimes", "lengthAdjust", "limitingConeAngle",
+		"markerHeight", "markerUnits", "markerWidth", "maskContentUnits", "maskUnits",
+		"numOctaves", "pathLength", "patternContentUnits", "patternTransform",
+		"patternUnits", "pointsAtX", "pointsAtY", "pointsAtZ", "preserveAlpha",
+		"preserveAspectRatio", "primitiveUnits", "refX", "refY", "repeatCount",
+		"repeatDur", "requiredExtensions", "requiredFeatures", "specularConstant",
+		"specularExponent", "spreadMethod", "startOffset", "stdDeviation",
+		"stitchTiles", "surfaceScale", "systemLanguage", "tableValues", "targetX",
+		"targetY", "textLength", "viewBox", "viewTarget", "xChannelSelector",
+		"yChannelSelector", "zoomAndPan",
+	} {
+		svgCaseSensitiveAttrs[strings.ToLower(attr)] = attr
+	}
+}
+
+// svgAttrName returns the real name of an SVG attribute from its possibly lowercased
+// name, and its namespace if it's a namespaced attribute
+func svgAttrName(attr string) (name string, namespace string) {
+	if n, ok := svgCaseSensitiveAttrs[strings.ToLower(attr)]; ok {
+		return n, ""
+	}
+
+	name = attr
+	switch {
+	case strings.HasPrefix(attr, "xlink:"):
+		namespace = XLinkNamespace
+	case strings.HasPrefix(attr, "xml:"):
+		namespace = XMLNamespace
+	case attr == "xmlns" || strings.HasPrefix(attr, "xmlns:"):
+		namespace = XMLNSNamespace
+	}
+	return
+}
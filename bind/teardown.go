@@ -0,0 +1,272 @@
+package bind
+
+import (
+	"strconv"
+	"strings"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+// elemIdAttr holds the id given to bound elements, to find their teardown functions
+var elemIdAttr = strings.Join([]string{ReservedBindPrefix, "id"}, "-")
+
+// TeardownBinder is implemented by binders that have something to clean up,
+// like a goroutine or a reference in the model, when the bound element is torn down
+type TeardownBinder interface {
+	Teardown(DomBind)
+}
+
+type bindEntry struct {
+	root      string
+	teardowns []func()
+	// replacement holds the nodes that replaced the element in the document,
+	// like the contents of a custom tag or of a wrapper
+	replacement *jq.JQuery
+	// infos describe the binds of the element, see Binding.BindingsFor
+	infos []*BindInfo
+	// tracker is the tracker of the model bound with BindTracked, see Binding.Reset
+	tracker *DirtyTracker
+}
+
+// bindRegistry keeps the teardown functions (removing watchers, cleaning up binders)
+// of the bound elements by element id. Each entry also records the root element of
+// the Bind call it was made in, so that a rebind of the root can tear it down even
+// after the element has been replaced.
+type bindRegistry struct {
+	lastId  int
+	entries map[string]*bindEntry
+	roots   []string
+	// afterBind holds the functions to be called when the outermost Bind call completes
+	afterBind []func()
+}
+
+func newBindRegistry() *bindRegistry {
+	return &bindRegistry{
+		entries: make(map[string]*bindEntry),
+		roots:   make([]string, 0),
+	}
+}
+
+func (r *bindRegistry) newId() string {
+	r.lastId++
+	return strconv.Itoa(r.lastId)
+}
+
+// elemId returns the id of the element, giving it one if it has none yet
+func (r *bindRegistry) elemId(elem jq.JQuery) string {
+	id := elem.Attr(elemIdAttr)
+	if id == "" {
+		id = r.newId()
+		elem.SetAttr(elemIdAttr, id)
+	}
+	return id
+}
+
+// currentRoot returns the id of the root element of the outermost Bind call
+// that's being performed, or "" if none is
+func (r *bindRegistry) currentRoot() string {
+	if len(r.roots) == 0 {
+		return ""
+	}
+	return r.roots[0]
+}
+
+func (r *bindRegistry) entry(id string) *bindEntry {
+	entry, ok := r.entries[id]
+	if !ok {
+		entry = &bindEntry{root: r.currentRoot()}
+		r.entries[id] = entry
+	}
+	return entry
+}
+
+func (r *bindRegistry) add(id string, fn func()) {
+	entry := r.entry(id)
+	entry.teardowns = append(entry.teardowns, fn)
+}
+
+// addInfo records the description of a bind of the element with the given id
+func (r *bindRegistry) addInfo(id string, info *BindInfo) {
+	entry := r.entry(id)
+	entry.infos = append(entry.infos, info)
+}
+
+// infos returns copies of the descriptions of the binds of the element with the given id
+func (r *bindRegistry) infos(id string) []BindInfo {
+	infos := make([]BindInfo, 0)
+	if entry, ok := r.entries[id]; ok {
+		for _, info := range entry.infos {
+			infos = append(infos, *info)
+		}
+	}
+	return infos
+}
+
+// replace records the nodes that replaced the element with the given id
+func (r *bindRegistry) replace(id string, nodes jq.JQuery) {
+	r.entry(id).replacement = &nodes
+}
+
+// replacement returns the nodes that replaced the element with the given id, if any
+func (r *bindRegistry) replacement(id string) (nodes jq.JQuery, ok bool) {
+	entry, ok := r.entries[id]
+	if !ok || entry.replacement == nil {
+		return nodes, false
+	}
+	return *entry.replacement, true
+}
+
+// teardown runs and removes the teardown functions of the entries with the given ids,
+// and those made under the given root if it's not empty
+func (r *bindRegistry) teardown(ids []string, root string) {
+	for _, id := range ids {
+		r.teardownEntry(id)
+	}
+
+	if root != "" {
+		for id, entry := range r.entries {
+			if entry.root == root {
+				r.teardownEntry(id)
+			}
+		}
+	}
+}
+
+func (r *bindRegistry) teardownEntry(id string) {
+	entry, ok := r.entries[id]
+	if !ok {
+		return
+	}
+
+	delete(r.entries, id)
+	for _, fn := range entry.teardowns {
+		fn()
+	}
+}
+
+// addTeardown registers a function to be called when the element is torn down
+func (b *Binding) addTeardown(elem jq.JQuery, fn func()) {
+	b.registry.add(b.registry.elemId(elem), fn)
+}
+
+// BindInfo describes a bind of an element, for debugging and tests
+type BindInfo struct {
+	// Name is the bind attribute, like "bind-text" or "bind" for the attribute
+	// binds of custom tags
+	Name string
+	// BindStr is the bind string, for attribute binds it's the one of the field
+	BindStr string
+	// Value is the last value of the bind expression
+	Value interface{}
+}
+
+// addBindInfo records the description of a bind of the element, the returned
+// BindInfo is updated when the value changes
+func (b *Binding) addBindInfo(elem jq.JQuery, name, bstr string, value interface{}) *BindInfo {
+	info := &BindInfo{name, bstr, value}
+	b.registry.addInfo(b.registry.elemId(elem), info)
+	return info
+}
+
+// BindingsFor returns the descriptions of the binds of the element that are active,
+// the result is empty if the element is not bound or has been torn down
+func (b *Binding) BindingsFor(elem jq.JQuery) []BindInfo {
+	id := elem.Attr(elemIdAttr)
+	if id == "" {
+		return []BindInfo{}
+	}
+	return b.registry.infos(id)
+}
+
+// replaceElem replaces elem in the document with the given nodes. The watchers
+// of elem's bindings then update the nodes instead, see liveElem.
+func (b *Binding) replaceElem(elem jq.JQuery, nodes jq.JQuery) {
+	if id := elem.Attr(elemIdAttr); id != "" {
+		b.registry.replace(id, nodes)
+	}
+	elem.ReplaceWith(nodes)
+}
+
+// liveElem returns the node(s) that currently stand for the bound elem in the
+// document. It's elem itself unless elem has been replaced, by the contents of
+// a custom tag or a wrapper for example.
+func (b *Binding) liveElem(elem jq.JQuery) jq.JQuery {
+	if jqExists(elem) {
+		return elem
+	}
+	if nodes, ok := b.registry.replacement(elem.Attr(elemIdAttr)); ok {
+		return nodes
+	}
+	return elem
+}
+
+// runTasks runs the bind tasks of a Bind call on relem, keeping track of relem as the root
+func (b *Binding) runTasks(relem jq.JQuery, btasks, customElemTasks []func()) {
+	r := b.registry
+	r.roots = append(r.roots, r.elemId(relem))
+	defer func() {
+		r.roots = r.roots[:len(r.roots)-1]
+	}()
+
+	runBindTasks(btasks, customElemTasks)
+	if len(r.roots) == 1 {
+		r.runAfterBind()
+	}
+}
+
+// afterBind calls fn when the Bind call being performed completes, so that the bound
+// elements are in place (the custom elements have been rendered, for example).
+// It's called right away if no Bind call is being performed.
+func (b *Binding) afterBind(fn func()) {
+	r := b.registry
+	if len(r.roots) == 0 {
+		fn()
+		return
+	}
+	r.afterBind = append(r.afterBind, fn)
+}
+
+func (r *bindRegistry) runAfterBind() {
+	for len(r.afterBind) > 0 {
+		fn := r.afterBind[0]
+		r.afterBind = r.afterBind[1:]
+		fn()
+	}
+}
+
+// Teardown removes the bindings of the elements and their descendants: their watchers
+// are removed and the binders are cleaned up. The elements may be bound again afterwards.
+func (b *Binding) Teardown(relem jq.JQuery) {
+	b.teardown(relem, false)
+}
+
+func (b *Binding) teardown(relem jq.JQuery, withRoot bool) {
+	ids := make([]string, 0)
+	collect := func(elem jq.JQuery) {
+		if id := elem.Attr(elemIdAttr); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	relem.Filter("[" + elemIdAttr + "]").Each(func(_ int, elem jq.JQuery) {
+		collect(elem)
+	})
+	relem.Find("[" + elemIdAttr + "]").Each(func(_ int, elem jq.JQuery) {
+		collect(elem)
+	})
+
+	root := ""
+	if withRoot {
+		root = relem.Attr(elemIdAttr)
+	}
+	b.registry.teardown(ids, root)
+	clearBindingMarks(relem, false)
+}
+
+// Rebind tears down the existing bindings made by binding relem, then binds
+// the model to its contents again. It's meant for development, to apply a
+// changed template without a full reload: the markup of relem can be replaced
+// before calling Rebind, the bindings of the replaced elements are still torn down.
+func (b *Binding) Rebind(relem jq.JQuery, model interface{}) {
+	b.teardown(relem, true)
+	b.Bind(relem, model, false, false)
+}
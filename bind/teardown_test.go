@@ -0,0 +1,141 @@
+package bind
+
+import (
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+func TestBindRegistry(t *testing.T) {
+	r := newBindRegistry()
+	torn := make(map[string]int)
+	add := func(id string) {
+		r.add(id, func() {
+			torn[id]++
+		})
+	}
+
+	// first binding of the root "1", with elements "2" and "3"
+	r.roots = append(r.roots, "1")
+	add("2")
+	add("2")
+	add("3")
+	r.roots = r.roots[:0]
+	// an unrelated binding
+	add("9")
+
+	// the template of "1" is replaced, so only the new elements can be found
+	r.teardown([]string{"1"}, "1")
+	if torn["2"] != 2 || torn["3"] != 1 {
+		t.Errorf("Expected the old bindings under the root to be torn down, got %v.", torn)
+	}
+	if torn["9"] != 0 {
+		t.Errorf("Expected the unrelated binding to be kept.")
+	}
+	if _, ok := r.entries["2"]; ok {
+		t.Errorf("Expected the torn down entries to be removed.")
+	}
+
+	// rebinding with the new template
+	r.roots = append(r.roots, "1")
+	add("4")
+	r.roots = r.roots[:0]
+	if r.entries["4"] == nil || r.entries["4"].root != "1" {
+		t.Fatalf("Expected the new binding to be active under the root.")
+	}
+
+	r.teardown([]string{"9"}, "")
+	r.teardown([]string{"9"}, "")
+	if torn["9"] != 1 || torn["4"] != 0 {
+		t.Errorf("Expected only the given element to be torn down once, got %v.", torn)
+	}
+	if r.newId() == r.newId() {
+		t.Errorf("Expected unique element ids.")
+	}
+}
+
+func TestReplacement(t *testing.T) {
+	r := newBindRegistry()
+	if _, ok := r.replacement("1"); ok {
+		t.Errorf("Expected no replacement for an unknown element.")
+	}
+
+	torn := false
+	r.add("1", func() {
+		torn = true
+	})
+	// a custom tag replaced by its contents
+	r.replace("1", jq.JQuery{Selector: "contents"})
+	nodes, ok := r.replacement("1")
+	if !ok || nodes.Selector != "contents" {
+		t.Fatalf("Expected the contents to stand for the replaced element, got %v.", nodes)
+	}
+	if len(r.entries["1"].teardowns) != 1 {
+		t.Errorf("Expected the teardown functions to be kept.")
+	}
+
+	// the live nodes are replaced again after a rebind of the contents
+	r.replace("1", jq.JQuery{Selector: "new contents"})
+	if nodes, _ = r.replacement("1"); nodes.Selector != "new contents" {
+		t.Errorf("Expected the latest replacement, got %v.", nodes)
+	}
+
+	r.teardown([]string{"1"}, "")
+	if _, ok := r.replacement("1"); ok || !torn {
+		t.Errorf("Expected the replacement to be forgotten on teardown.")
+	}
+}
+
+func TestBindInfos(t *testing.T) {
+	b := NewBindEngine(nil)
+	if infos := b.BindingsFor(jq.JQuery{}); infos == nil || len(infos) != 0 {
+		t.Errorf("Expected no bindings for an unbound element, got %v.", infos)
+	}
+
+	r := b.registry
+	text := &BindInfo{"bind-text", "Name", "wade"}
+	r.addInfo("1", text)
+	r.addInfo("1", &BindInfo{"bind-class-done", "Done", false})
+	r.add("1", func() {})
+
+	text.Value = "gopher"
+	infos := r.infos("1")
+	if len(infos) != 2 || infos[0].Name != "bind-text" || infos[0].BindStr != "Name" || infos[1].Value != false {
+		t.Fatalf("Unexpected infos %v.", infos)
+	}
+	if infos[0].Value != "gopher" {
+		t.Errorf("Expected the last value, got %v.", infos[0].Value)
+	}
+
+	r.teardown([]string{"1"}, "")
+	if infos := r.infos("1"); len(infos) != 0 {
+		t.Errorf("Expected no bindings after the teardown, got %v.", infos)
+	}
+}
+
+func TestTeardownRemovedItems(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	model := &testBadge{Label: "new", Tags: []string{"a", "b", "c"}}
+	elem := gJQ(`<div><ul><li bind-each="Tags -> _, tag"><span bind-text="tag"></span></li></ul><div bind-list="Tags"><em bind-text="$item"></em></div></div>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+
+	b.Bind(elem, model, false, false)
+	entries := len(b.registry.entries)
+	for i := 0; i < 50; i++ {
+		w.change()
+	}
+	if n := len(b.registry.entries); n != entries {
+		t.Errorf("Expected the removed items to be torn down, got %v entries instead of %v.", n, entries)
+	}
+	if elem.Find("span").Length != 3 || elem.Find("em").Length != 3 {
+		t.Errorf("Expected the items to be rendered, got %v.", elem.Html())
+	}
+
+	model.Tags = model.Tags[:1]
+	w.change()
+	if n := len(b.registry.entries); n >= entries {
+		t.Errorf("Expected the entries of the removed items to be dropped, got %v.", n)
+	}
+}
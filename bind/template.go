@@ -0,0 +1,72 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// templateSwitch keeps track of the template rendered in an element
+type templateSwitch struct {
+	current string
+	render  func(id string)
+	clear   func()
+}
+
+// show renders the template with the given id in place of the current one,
+// nothing is rendered for an empty id
+func (s *templateSwitch) show(id string) {
+	if id == s.current {
+		return
+	}
+
+	if s.current != "" {
+		s.clear()
+	}
+	s.current = id
+	if id != "" {
+		s.render(id)
+	}
+}
+
+// TemplateBinder is a 1-way binder that fills the element with a copy of the contents
+// of a template, bound in the scope of the element. The template is given by its id,
+// looked up like the templates of BindFragment. Unlike with IncludeTag, the id is an
+// expression, when it changes the contents are torn down and replaced with the new
+// template. An empty id leaves the element empty.
+// It takes no extra dash args.
+//
+// Usage:
+//	bind-template="TemplateIdExpression"
+// Example:
+//	<tr bind-each="Rows -> _, row" bind-template="RowTemplate"></tr>
+type TemplateBinder struct {
+	BaseBinder
+	templates *templateSwitch
+}
+
+func (b *TemplateBinder) Bind(d DomBind) {
+	b.templates = &templateSwitch{
+		render: func(id string) {
+			tmpl, ok := d.binding.template(id)
+			if !ok {
+				d.Panic(fmt.Sprintf(`Template "%v" cannot be found.`, id))
+			}
+			d.Elem.Append(tmpl.Clone().Contents())
+			d.bind(d.Elem, nil, d.once, false)
+		},
+		clear: func() {
+			d.binding.Teardown(d.Elem.Children("*"))
+			d.Elem.Empty()
+		},
+	}
+}
+
+func (b *TemplateBinder) Update(d DomBind) {
+	id, ok := d.Value.(string)
+	if !ok && d.Value != nil {
+		d.Panic(fmt.Sprintf("Wrong type %v for the template binder, must be a string.", reflect.TypeOf(d.Value)))
+	}
+	b.templates.show(id)
+}
+
+func (b *TemplateBinder) BindInstance() DomBinder { return new(TemplateBinder) }
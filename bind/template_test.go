@@ -0,0 +1,42 @@
+package bind
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testRowView struct {
+	RowTemplate string
+}
+
+func TestTemplateSwitch(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testRowView{RowTemplate: "row-full"}
+	events := []string{}
+	s := &templateSwitch{
+		render: func(id string) { events = append(events, "render "+id) },
+		clear:  func() { events = append(events, "clear") },
+	}
+
+	update := func() {
+		v, err := b.Eval(model, "RowTemplate")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		s.show(v.(string))
+	}
+
+	update()
+	update()
+	model.RowTemplate = "row-compact"
+	update()
+	model.RowTemplate = ""
+	update()
+	model.RowTemplate = "row-full"
+	update()
+
+	expected := []string{"render row-full", "clear", "render row-compact", "clear", "render row-full"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected the template to be replaced when the id changes, got %v.", events)
+	}
+}
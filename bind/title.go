@@ -0,0 +1,43 @@
+package bind
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// TitleSetter sets the title of the document
+type TitleSetter interface {
+	SetTitle(title string)
+}
+
+type documentTitle struct{}
+
+func (t documentTitle) SetTitle(title string) {
+	js.Global.Get("document").Set("title", title)
+}
+
+// SetTitleSetter sets what sets the document title for SetTitle and the title binder
+func (b *Binding) SetTitleSetter(t TitleSetter) {
+	b.title = t
+}
+
+// SetTitle sets the title of the document
+func (b *Binding) SetTitle(title string) {
+	b.title.SetTitle(title)
+}
+
+// TitleBinder is a 1-way binder that sets the title of the document, it's put on
+// an element of a page so that each page sets its own title. The title is set
+// after the default title of the page, when the page is bound.
+// It takes no extra dash args.
+//
+// Usage:
+//	bind-title="Expression"
+// Example:
+//	<div bind-title="concat(Post.Title, ` - Blog`)"></div>
+type TitleBinder struct{ BaseBinder }
+
+func (b *TitleBinder) Update(d DomBind) {
+	d.binding.SetTitle(d.ValueString())
+}
+
+func (b *TitleBinder) BindInstance() DomBinder { return b }
@@ -0,0 +1,46 @@
+package bind
+
+import (
+	"testing"
+)
+
+type fakeTitle struct {
+	titles []string
+}
+
+func (t *fakeTitle) SetTitle(title string) { t.titles = append(t.titles, title) }
+
+type testPostPage struct {
+	Title string
+}
+
+func TestTitleBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	title := &fakeTitle{}
+	b.SetTitleSetter(title)
+	model := &testPostPage{Title: "First post"}
+	binder := b.domBinders["title"].BindInstance()
+
+	update := func() {
+		v, err := b.Eval(model, "concat(Title, ` - Blog`)")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		binder.Update(DomBind{Value: v, binding: b})
+	}
+
+	b.SetTitle("Blog")
+	update()
+	model.Title = "Second post"
+	update()
+
+	expected := []string{"Blog", "First post - Blog", "Second post - Blog"}
+	if len(title.titles) != len(expected) {
+		t.Fatalf("Expected the titles %v, got %v.", expected, title.titles)
+	}
+	for i, s := range expected {
+		if title.titles[i] != s {
+			t.Errorf("Expected the title %q, got %q.", s, title.titles[i])
+		}
+	}
+}
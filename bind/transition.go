@@ -0,0 +1,102 @@
+package bind
+
+import (
+	jq "github.com/gopherjs/jquery"
+)
+
+const (
+	// TransitionAttr sets the name of the transitions of bind-each-transition,
+	// the items that are added get the class NAME-enter until their transition
+	// or animation ends, the removed items get NAME-leave and are removed when
+	// it ends. The default name is DefaultTransition.
+	//
+	// Usage:
+	//	<li bind-each-transition="Entries -> _, entry" wade-transition="fade"><% entry.Title %></li>
+	TransitionAttr = "wade-transition"
+
+	DefaultTransition = "wade"
+)
+
+// transitionGroup applies the enter and leave transitions of the items of a repeating
+// binder, the items are identified by their keys across updates
+type transitionGroup struct {
+	browser browser
+	name    string
+	started bool
+}
+
+func newTransitionGroup(br browser, name string) *transitionGroup {
+	if name == "" {
+		name = DefaultTransition
+	}
+	return &transitionGroup{browser: br, name: name}
+}
+
+// onEnd calls fn when the transition or animation of the nodes ends,
+// right away if they have none
+func (g *transitionGroup) onEnd(nodes jq.JQuery, fn func()) {
+	elems := nodes.Filter("*")
+	if elems.Length == 0 || !g.browser.transitioning(elems.First()) {
+		fn()
+		return
+	}
+
+	done := false
+	elems.First().One("transitionend animationend", func() {
+		if !done {
+			done = true
+			fn()
+		}
+	})
+}
+
+// leave starts the leave transition of the old items whose keys are not among the new keys,
+// they're removed when it ends. It returns the indexes of those items.
+func (g *transitionGroup) leave(oldKeys []interface{}, oldNodes []jq.JQuery, newKeys []interface{}) map[int]bool {
+	current := make(map[interface{}]bool)
+	for _, key := range newKeys {
+		current[key] = true
+	}
+
+	leaving := make(map[int]bool)
+	for i, key := range oldKeys {
+		if current[key] {
+			continue
+		}
+
+		leaving[i] = true
+		nodes := oldNodes[i]
+		nodes.Filter("*").AddClass(g.name + "-leave")
+		g.onEnd(nodes, func() {
+			nodes.Remove()
+		})
+	}
+	return leaving
+}
+
+// enter starts the enter transition of the new items whose keys were not among the
+// old keys. The items of the first rendering don't transition.
+func (g *transitionGroup) enter(oldKeys []interface{}, newKeys []interface{}, newNodes []jq.JQuery) {
+	if !g.started {
+		g.started = true
+		return
+	}
+
+	previous := make(map[interface{}]bool)
+	for _, key := range oldKeys {
+		previous[key] = true
+	}
+
+	class := g.name + "-enter"
+	for i, key := range newKeys {
+		if previous[key] {
+			continue
+		}
+
+		nodes := newNodes[i]
+		nodes.Filter("*").AddClass(class)
+		g.onEnd(nodes, func() {
+			nodes.Filter("*").RemoveClass(class)
+		})
+	}
+}
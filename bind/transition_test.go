@@ -0,0 +1,59 @@
+package bind
+
+import (
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+func TestTransitionGroup(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	br := newFakeBrowser()
+	b.browser = br
+	a, c := &testKeyed{1, "a"}, &testKeyed{3, "c"}
+	model := &testKeyedList{[]*testKeyed{a, {2, "b"}, c}}
+	elem := gJQ(`<ul><li bind-each-transition="Items -> _, item" wade-key="Id" wade-transition="fade"><span bind-text="item.Title"></span></li></ul>`).AppendTo(gJQ("body"))
+	defer elem.Remove()
+	items := func() (s string) {
+		elem.Find("li").Each(func(i int, li jq.JQuery) {
+			s += li.Text()
+			if class := li.Attr("class"); class != "" {
+				s += "(" + class + ")"
+			}
+		})
+		return
+	}
+
+	// the first rendering doesn't transition
+	br.animated = "li"
+	b.Bind(elem, model, false, false)
+	if s := items(); s != "abc" {
+		t.Errorf("Expected no transition for the first rendering, got %v.", s)
+	}
+
+	// b is removed and d is added
+	model.Items = []*testKeyed{a, c, {4, "d"}}
+	w.change()
+	if s := items(); s != "acd(fade-enter)b(fade-leave)" {
+		t.Errorf("Expected b to leave after the items and d to enter, got %v.", s)
+	}
+
+	elem.Find(".fade-leave, .fade-enter").Trigger("transitionend")
+	if s := items(); s != "acd" {
+		t.Errorf("Expected the classes to be removed with the leaving item at the end, got %v.", s)
+	}
+
+	// without a css transition, the items are removed right away
+	br.animated = ""
+	model.Items = []*testKeyed{c, {5, "e"}}
+	w.change()
+	if s := items(); s != "ce" {
+		t.Errorf("Expected the transitions to end right away, got %v.", s)
+	}
+
+	if g := newTransitionGroup(br, ""); g.name != DefaultTransition {
+		t.Errorf("Expected the default name, got %v.", g.name)
+	}
+}
@@ -0,0 +1,97 @@
+package bind
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// interpolationRegexp matches the <% Expression %> interpolations of templates
+var interpolationRegexp = regexp.MustCompile(`<%([^"<>]+)%>`)
+
+// UnusedSymbols analyses the template like Check, and returns the names of the model's
+// fields and methods and of the registered helpers and constants that no bind string
+// of the template references. The built-in helpers are not reported. Like for Check,
+// the contents of custom element tags are not analysed.
+func (b *Binding) UnusedSymbols(model interface{}, templateHTML string) []string {
+	used := make(map[string]bool)
+	b.checkTemplate(templateHTML, model, used)
+
+	var mtype reflect.Type
+	if model != nil {
+		mtype = reflect.TypeOf(model)
+	}
+	ts := &typeScope{
+		dynamic:    make(map[string]bool),
+		model:      mtype,
+		helpers:    b.helpers,
+		precedence: b.HelperPrecedence,
+		used:       used,
+	}
+	for _, m := range interpolationRegexp.FindAllStringSubmatch(templateHTML, -1) {
+		if root, err := parseCached(strings.TrimSpace(m[1])); err == nil {
+			ts.check(root)
+		}
+	}
+
+	unused := make([]string, 0)
+	for _, name := range modelSymbols(mtype) {
+		if !used[name] && !usedAccessor(name, used) {
+			unused = append(unused, name)
+		}
+	}
+
+	builtins := defaultHelpers()
+	for name := range internalHelpers(nil) {
+		builtins[name] = nil
+	}
+	for name := range b.engineHelpers() {
+		builtins[name] = nil
+	}
+	helpers := make([]string, 0)
+	for name := range b.helpers.m {
+		if _, builtin := builtins[name]; !builtin && !used[name] {
+			helpers = append(helpers, name)
+		}
+	}
+	sort.Strings(helpers)
+
+	return append(unused, helpers...)
+}
+
+// modelSymbols returns the names of the exported fields and methods of the model type
+func modelSymbols(mtype reflect.Type) []string {
+	names := make([]string, 0)
+	if mtype == nil {
+		return names
+	}
+
+	t := mtype
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath == "" && !f.Anonymous {
+				names = append(names, f.Name)
+			}
+		}
+	}
+
+	pt := reflect.PtrTo(t)
+	for i := 0; i < pt.NumMethod(); i++ {
+		names = append(names, pt.Method(i).Name)
+	}
+	return names
+}
+
+// usedAccessor checks whether the method is an accessor of a used field, like XxxGet for Xxx
+func usedAccessor(name string, used map[string]bool) bool {
+	for _, suffix := range []string{AccessorGetSuffix, AccessorSetSuffix} {
+		if strings.HasSuffix(name, suffix) && used[strings.TrimSuffix(name, suffix)] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,324 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+const (
+	ValidationErrorClass   = "has-error"
+	ValidationMessageClass = "validation-message"
+)
+
+// FormState holds the validation state of a form's fields.
+// A FormState is meant to be a field of the model, the validate binders report
+// the validation results of their fields to it.
+type FormState struct {
+	// Valid is true when none of the fields has a validation error
+	Valid bool
+	// Errors maps each validated field's name to its current error message,
+	// an empty message means the field is valid
+	Errors map[string]string
+
+	listeners    map[int]func(field, msg string)
+	lastListener int
+}
+
+// SetError sets the error message for a field and recomputes Valid.
+// An empty message marks the field as valid.
+func (f *FormState) SetError(field, msg string) {
+	if f.Errors == nil {
+		f.Errors = make(map[string]string)
+	}
+	f.Errors[field] = msg
+
+	valid := true
+	for _, m := range f.Errors {
+		if m != "" {
+			valid = false
+			break
+		}
+	}
+	f.Valid = valid
+
+	for _, fn := range f.listeners {
+		fn(field, msg)
+	}
+}
+
+// onChange registers a function called when the error of a field is set,
+// it returns the function that unregisters it
+func (f *FormState) onChange(fn func(field, msg string)) (stop func()) {
+	if f.listeners == nil {
+		f.listeners = make(map[int]func(field, msg string))
+	}
+	f.lastListener++
+	id := f.lastListener
+	f.listeners[id] = fn
+	return func() {
+		delete(f.listeners, id)
+	}
+}
+
+// formStateOf returns the FormState with the given name in the scope of the bind
+func formStateOf(d DomBind, name string) *FormState {
+	sym, err := d.scope.lookup(name)
+	if err != nil {
+		d.Panic(err.Error())
+	}
+
+	v, err := sym.value()
+	if err != nil {
+		d.Panic(err.Error())
+	}
+
+	if v.Kind() != reflect.Ptr && v.CanAddr() {
+		v = v.Addr()
+	}
+
+	form, ok := v.Interface().(*FormState)
+	if !ok {
+		d.Panic(fmt.Sprintf(`"%v" is of type %v, it must be a FormState.`, name, v.Type()))
+	}
+	return form
+}
+
+// ValidateBinder is a 1-way binder that validates a field with a validator
+// expression, which evaluates to an error message (empty when the value is valid).
+// It takes 1 extra dash arg that is the name of the field being validated.
+// The output after "->" names the FormState that receives the validation result.
+//
+// When the value is invalid, the element gets the "has-error" class and the error
+// message is put into its descendant elements with the "validation-message" class.
+//
+// Usage:
+//	bind-validate-fieldName="ValidatorExpression -> FormState"
+// Example:
+//	<div bind-validate-username="required(Data.Username) -> Form">
+//		<input bind-value="Data.Username">
+//		<span class="validation-message"></span>
+//	</div>
+type ValidateBinder struct {
+	*BaseBinder
+	form *FormState
+}
+
+func (b *ValidateBinder) Bind(d DomBind) {
+	if len(d.Args) != 1 {
+		d.Panic("The validate binder requires exactly 1 dash arg, the name of the field.")
+	}
+
+	if len(d.outputs) != 1 {
+		d.Panic(`The validate binder requires the FormState to be specified after "->".`)
+	}
+
+	b.form = formStateOf(d, d.outputs[0])
+}
+
+func (b *ValidateBinder) Update(d DomBind) {
+	msg, ok := d.Value.(string)
+	if !ok {
+		d.Panic(fmt.Sprintf("Wrong type %v for the validator result, must be a string.", reflect.TypeOf(d.Value)))
+	}
+
+	b.form.SetError(d.Args[0], msg)
+	if msg == "" {
+		d.Elem.RemoveClass(ValidationErrorClass)
+	} else {
+		d.Elem.AddClass(ValidationErrorClass)
+	}
+	d.Elem.Find("." + ValidationMessageClass).SetText(msg)
+}
+func (b *ValidateBinder) BindInstance() DomBinder { return new(ValidateBinder) }
+
+// ErrorBinder is a 1-way binder that displays the current validation error of a field,
+// as reported to the FormState by its validate binder. It's updated whenever the error
+// changes and renders empty when the field is valid, the element also gets the
+// "has-error" class while there's an error.
+// It takes 1 extra dash arg that is the name of the field, as in its validate binder.
+//
+// Usage:
+//	bind-error-fieldName="FormState"
+// Example:
+//	<input bind-value="Data.Username" bind-validate-username="required(Data.Username) -> Form">
+//	<span bind-error-username="Form"></span>
+type ErrorBinder struct {
+	BaseBinder
+	msg  string
+	stop func()
+}
+
+func (b *ErrorBinder) OneShot() {}
+
+func (b *ErrorBinder) Bind(d DomBind) {
+	if len(d.Args) != 1 {
+		d.Panic("The error binder requires exactly 1 dash arg, the name of the field.")
+	}
+
+	form := formStateOf(d, d.expr)
+	field := d.Args[0]
+	b.render(d, form.Errors[field])
+	b.stop = form.onChange(func(f, msg string) {
+		if f == field {
+			b.render(d, msg)
+		}
+	})
+}
+
+func (b *ErrorBinder) render(d DomBind, msg string) {
+	b.msg = msg
+	elem := d.binding.liveElem(d.Elem)
+	elem.SetText(msg)
+	if msg == "" {
+		elem.RemoveClass(ValidationErrorClass)
+	} else {
+		elem.AddClass(ValidationErrorClass)
+	}
+}
+
+func (b *ErrorBinder) Teardown(d DomBind) {
+	if b.stop != nil {
+		b.stop()
+	}
+}
+
+func (b *ErrorBinder) BindInstance() DomBinder { return new(ErrorBinder) }
+
+// isEmptyValue checks whether v is the zero value of its type, or an empty
+// collection
+func isEmptyValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+// toFloat converts a numeric value to float64
+func toFloat(v reflect.Value) (f float64, ok bool) {
+	ok = true
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = v.Float()
+	default:
+		ok = false
+	}
+	return
+}
+
+// compareBound compares a number, or the length of a string or a collection,
+// to the given numeric bound. It returns the sign of the difference and whether
+// the length was used.
+func compareBound(value, bound interface{}) (cmp int, isLen bool, err error) {
+	b, ok := toFloat(reflect.ValueOf(bound))
+	if !ok {
+		err = fmt.Errorf("The bound %v is not a number.", bound)
+		return
+	}
+
+	v := reflect.ValueOf(value)
+	n, ok := toFloat(v)
+	if !ok {
+		switch v.Kind() {
+		case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+			n = float64(v.Len())
+			isLen = true
+		default:
+			err = fmt.Errorf("Cannot compare a value of type %v to a number.", reflect.TypeOf(value))
+			return
+		}
+	}
+
+	switch {
+	case n < b:
+		cmp = -1
+	case n > b:
+		cmp = 1
+	}
+	return
+}
+
+// PatternCacheSize is the maximum number of compiled patterns of the pattern validator kept in the cache
+const PatternCacheSize = 256
+
+// patternCache caches the compiled patterns of the pattern validator,
+// which is evaluated each time the validated value changes
+var patternCache = newLruCache(PatternCacheSize)
+
+// compilePattern compiles the pattern, or returns it from the cache
+func compilePattern(pattern string) (re *regexp.Regexp, err error) {
+	if cached, ok := patternCache.get(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	re, err = regexp.Compile(pattern)
+	if err != nil {
+		err = fmt.Errorf(`Invalid pattern "%v": %v`, pattern, err.Error())
+		return
+	}
+
+	patternCache.add(pattern, re)
+	return
+}
+
+func validationHelpers() map[string]interface{} {
+	return map[string]interface{}{
+		"required": func(value interface{}) string {
+			if isEmptyValue(reflect.ValueOf(value)) {
+				return "This field is required."
+			}
+			return ""
+		},
+		"min": func(value, bound interface{}) (string, error) {
+			cmp, isLen, err := compareBound(value, bound)
+			if err != nil || cmp >= 0 {
+				return "", err
+			}
+			if isLen {
+				return fmt.Sprintf("The length must be at least %v.", bound), nil
+			}
+			return fmt.Sprintf("The value must be at least %v.", bound), nil
+		},
+		"max": func(value, bound interface{}) (string, error) {
+			cmp, isLen, err := compareBound(value, bound)
+			if err != nil || cmp <= 0 {
+				return "", err
+			}
+			if isLen {
+				return fmt.Sprintf("The length must be at most %v.", bound), nil
+			}
+			return fmt.Sprintf("The value must be at most %v.", bound), nil
+		},
+		"pattern": func(value interface{}, pattern string) (string, error) {
+			re, err := compilePattern(pattern)
+			if err != nil {
+				return "", err
+			}
+			if !re.MatchString(toString(value)) {
+				return "The value has an invalid format.", nil
+			}
+			return "", nil
+		},
+		// firstError returns the first non-empty error message, it's used to combine validators
+		"firstError": func(msgs ...string) string {
+			for _, msg := range msgs {
+				if msg != "" {
+					return msg
+				}
+			}
+			return ""
+		},
+	}
+}
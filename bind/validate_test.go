@@ -0,0 +1,140 @@
+package bind
+
+import (
+	"testing"
+)
+
+type testSignup struct {
+	Username string
+	Age      int
+	Tags     []string
+	Pattern  string
+}
+
+func TestValidationHelpers(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testSignup{Pattern: "^a"}
+	invalid := []string{
+		"required(Username)",
+		"min(Age, 18)",
+		"min(Username, 3)",
+		"pattern(Username, Pattern)",
+		"firstError(required(Username), min(Age, 18))",
+	}
+	for _, bstr := range invalid {
+		_, _, v, err := b.evaluate(bstr, model)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if v == "" {
+			t.Errorf("Expected %v to return an error message.", bstr)
+		}
+	}
+
+	model.Username = "abc"
+	model.Age = 20
+	valid := []string{
+		"required(Username)",
+		"min(Age, 18)",
+		"max(Age, 20.5)",
+		"min(Username, 3)",
+		"max(Tags, 0)",
+		"pattern(Username, Pattern)",
+		"firstError(required(Username), min(Age, 18))",
+	}
+	for _, bstr := range valid {
+		_, _, v, err := b.evaluate(bstr, model)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if v != "" {
+			t.Errorf("Expected %v to return no error message, got %v.", bstr, v)
+		}
+	}
+
+	_, _, v, _ := b.evaluate("max(Username, 2)", model)
+	if v == "" {
+		t.Errorf("Expected max(Username, 2) to return an error message.")
+	}
+
+	model.Pattern = "a(b"
+	for _, bstr := range []string{"pattern(Username, Pattern)", "min(Age, Username)", "max(Pattern == `x`, 1)"} {
+		if _, _, _, err := b.evaluate(bstr, model); err == nil {
+			t.Errorf("Expected an error for %v.", bstr)
+		}
+	}
+
+	model.Pattern = "^[a-c]+$"
+	b.evaluate("pattern(Username, Pattern)", model)
+	cached, ok := patternCache.get(model.Pattern)
+	if !ok {
+		t.Fatalf("Expected the pattern to be cached.")
+	}
+	if re, _ := compilePattern(model.Pattern); re != cached {
+		t.Errorf("Expected the cached pattern to be reused.")
+	}
+}
+
+func TestFormState(t *testing.T) {
+	form := new(FormState)
+	form.SetError("username", "This field is required.")
+	form.SetError("age", "")
+	if form.Valid {
+		t.Errorf("Expected the form to be invalid.")
+	}
+
+	form.SetError("username", "")
+	if !form.Valid {
+		t.Errorf("Expected the form to be valid.")
+	}
+	if len(form.Errors) != 2 {
+		t.Errorf("Expected 2 fields in the form state, got %v.", len(form.Errors))
+	}
+}
+
+type testRegistration struct {
+	Username string
+	Form     FormState
+}
+
+func TestErrorBinder(t *testing.T) {
+	b := NewBindEngine(nil)
+	model := &testRegistration{}
+	model.Form.SetError("username", "This field is required.")
+	s := newModelScope(model)
+	s.merge(b.scope)
+	d := DomBind{Args: []string{"username"}, expr: "Form", scope: s, binding: b}
+
+	binder := b.domBinders["error"].BindInstance().(*ErrorBinder)
+	binder.Bind(d)
+	if binder.msg != "This field is required." {
+		t.Errorf("Expected the current error to be displayed, got %q.", binder.msg)
+	}
+
+	model.Form.SetError("email", "Invalid email.")
+	if binder.msg != "This field is required." {
+		t.Errorf("Expected the errors of other fields to be ignored, got %q.", binder.msg)
+	}
+	model.Form.SetError("username", "")
+	if binder.msg != "" || model.Form.Valid {
+		t.Errorf("Expected the error to be cleared on fix, got %q.", binder.msg)
+	}
+	model.Form.SetError("username", "Too short.")
+	if binder.msg != "Too short." {
+		t.Errorf("Expected the new error to be displayed, got %q.", binder.msg)
+	}
+
+	binder.Teardown(d)
+	model.Form.SetError("username", "")
+	if binder.msg != "Too short." || len(model.Form.listeners) != 0 {
+		t.Errorf("Expected the binder to stop listening after teardown.")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a value that's not a FormState.")
+		}
+	}()
+	d.expr = "Username"
+	binder.Bind(d)
+}
@@ -0,0 +1,288 @@
+package bind
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// watchBudget counts the active watchers of a Binding against Binding.MaxWatchers
+type watchBudget struct {
+	active int
+	// exceeded is set once the warning has been printed, until a watcher is released
+	exceeded bool
+}
+
+// take reserves n watchers for the field, it returns false and prints a warning
+// the first time the maximum is reached, 0 means no maximum
+func (w *watchBudget) take(max, n int, field string) bool {
+	if max > 0 && w.active+n > max {
+		if !w.exceeded {
+			w.exceeded = true
+			println(fmt.Sprintf(`Warning: the maximum of %v watchers is reached, the changes of "%v" and the fields bound after it are not watched.`,
+				max, field))
+		}
+		return false
+	}
+
+	w.active += n
+	return true
+}
+
+func (w *watchBudget) release(n int) {
+	w.active -= n
+	w.exceeded = false
+}
+
+// fieldWatcher registers watchers of the fields of model objects
+type fieldWatcher interface {
+	// watch calls fn when the field of the object changes, or one of the values nested
+	// in it up to level levels (all of them if level is negative). It returns the function
+	// removing the watcher.
+	watch(obj reflect.Value, field string, level int, fn func()) (unwatch func())
+}
+
+// watchJS watches the fields with watch.js
+type watchJS struct{}
+
+func (w watchJS) watch(obj reflect.Value, field string, level int, fn func()) func() {
+	//workaround for gopherjs's protection disallowing js access to maps
+	//setDummyHopFn(obj, "")
+	o := js.InternalObject(obj.Interface()).Get("$val")
+	handler := func(prop string, action string,
+		_ js.Object,
+		_2 js.Object) {
+		fn()
+	}
+
+	args := []interface{}{o, field, handler}
+	if level >= 0 {
+		args = append(args, level)
+	}
+	js.Global.Call("watch", args...)
+	return func() {
+		js.Global.Call("unwatch", o, field, handler)
+	}
+}
+
+// watchLevel returns the level of the values nested in the target that are watched,
+// it's limited by MaxWatchDepth, -1 means no limit
+func (b *Binding) watchLevel(t watchTarget) int {
+	switch {
+	case t.shallow:
+		return 0
+	case b.MaxWatchDepth > 0:
+		return b.MaxWatchDepth
+	}
+	return -1
+}
+
+// watcherCount returns the number of watchers registered for the targets: one for
+// each target, and for the targets watched with their nested values, one for each
+// of the values nested in the field up to MaxWatchDepth levels, since watch.js
+// watches them one by one
+func (b *Binding) watcherCount(targets []watchTarget) int {
+	n := 0
+	for _, t := range targets {
+		n++
+		if !t.shallow {
+			n += nestedCount(t.value, 1, b.MaxWatchDepth)
+		}
+	}
+	return n
+}
+
+// nestedCount returns the number of the fields, elements and map values nested in v,
+// those deeper than depth (if it's not 0) are not counted
+func nestedCount(v reflect.Value, level, depth int) int {
+	if depth > 0 && level > depth {
+		return 0
+	}
+
+	n := 0
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if !v.IsNil() {
+			n = nestedCount(v.Elem(), level, depth)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				n += 1 + nestedCount(v.Field(i), level+1, depth)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			n += 1 + nestedCount(v.Index(i), level+1, depth)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			n += 1 + nestedCount(v.MapIndex(key), level+1, depth)
+		}
+	}
+	return n
+}
+
+// watchTarget is a field of an object to watch with watch.js
+type watchTarget struct {
+	obj   reflect.Value
+	field string
+	// shallow is set to watch the field without its nested values
+	shallow bool
+	// value is the value of the field
+	value reflect.Value
+}
+
+// watchTargets returns what to watch for a bound field. The field is watched with its
+// nested values by watch.js, unless they refer back to themselves, like a child pointing
+// to its parent: watch.js would then recurse forever. Instead, the field and the fields
+// of the objects reachable from it are watched without their nested values, each object
+// once, up to MaxWatchDepth levels.
+// The model root of the field is looked for cycles once for all its watched fields, see
+// cycleCache, the returned function must be called when the targets are not watched anymore.
+func (b *Binding) watchTargets(bo *objEval) (targets []watchTarget, release func()) {
+	v := bo.watched()
+	rootCycle, release := b.cycles.hold(bo.root)
+	if !rootCycle || !hasCycle(v) {
+		return []watchTarget{{bo.modelRefl, bo.field, false, v}}, release
+	}
+
+	targets = []watchTarget{{bo.modelRefl, bo.field, true, v}}
+	seen := make(map[uintptr]bool)
+	switch {
+	case bo.modelRefl.Kind() == reflect.Ptr:
+		seen[bo.modelRefl.Pointer()] = true
+	case bo.modelRefl.CanAddr():
+		seen[bo.modelRefl.Addr().Pointer()] = true
+	}
+	return appendNestedTargets(targets, v, 1, b.MaxWatchDepth, seen), release
+}
+
+// appendNestedTargets appends the fields of the structs reachable from v that are
+// not in seen, the structs deeper than depth (if it's not 0) are not visited
+func appendNestedTargets(targets []watchTarget, v reflect.Value, level, depth int, seen map[uintptr]bool) []watchTarget {
+	if depth > 0 && level > depth {
+		return targets
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			targets = appendNestedTargets(targets, v.Elem(), level, depth, seen)
+		}
+	case reflect.Struct:
+		if v.CanAddr() {
+			targets = appendNestedTargets(targets, v.Addr(), level, depth, seen)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			targets = appendNestedTargets(targets, v.Index(i), level, depth, seen)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			targets = appendNestedTargets(targets, v.MapIndex(key), level, depth, seen)
+		}
+	case reflect.Ptr:
+		if v.IsNil() || v.Elem().Kind() != reflect.Struct || seen[v.Pointer()] {
+			return targets
+		}
+		seen[v.Pointer()] = true
+
+		st := v.Elem().Type()
+		for i := 0; i < st.NumField(); i++ {
+			if st.Field(i).PkgPath != "" {
+				continue
+			}
+			targets = append(targets, watchTarget{v, st.Field(i).Name, true, v.Elem().Field(i)})
+			targets = appendNestedTargets(targets, v.Elem().Field(i), level+1, depth, seen)
+		}
+	}
+
+	return targets
+}
+
+// cycleCache remembers whether the model roots refer back to themselves, so that a
+// model is walked once for all its watched fields instead of once for each of them.
+// The entry of a root is held while fields of the root are watched, a root that's
+// watched again after all its watchers have been removed is walked again.
+type cycleCache map[uintptr]*cycleEntry
+
+type cycleEntry struct {
+	cyclic bool
+	refs   int
+}
+
+// hold returns whether the root may have a cycle, it's always true for a root that's
+// not a pointer. The returned function releases the entry of the root.
+func (c cycleCache) hold(root reflect.Value) (cyclic bool, release func()) {
+	if root.Kind() != reflect.Ptr || root.IsNil() {
+		return true, func() {}
+	}
+
+	p := root.Pointer()
+	entry, ok := c[p]
+	if !ok {
+		entry = &cycleEntry{cyclic: hasCycle(root)}
+		c[p] = entry
+	}
+	entry.refs++
+	return entry.cyclic, func() {
+		entry.refs--
+		if entry.refs == 0 {
+			delete(c, p)
+		}
+	}
+}
+
+// hasCycle reports whether the value refers back to an object that it's nested in
+func hasCycle(v reflect.Value) bool {
+	return findCycle(v, make(map[uintptr]bool), make(map[uintptr]bool))
+}
+
+// findCycle walks the value depth first, onPath are the pointers being walked
+// and done are the ones that are walked entirely without finding a cycle
+func findCycle(v reflect.Value, onPath, done map[uintptr]bool) bool {
+	switch v.Kind() {
+	case reflect.Interface:
+		return !v.IsNil() && findCycle(v.Elem(), onPath, done)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if findCycle(v.Field(i), onPath, done) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if findCycle(v.Index(i), onPath, done) {
+				return true
+			}
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			if findCycle(v.MapIndex(key), onPath, done) {
+				return true
+			}
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return false
+		}
+		p := v.Pointer()
+		if onPath[p] {
+			return true
+		}
+		if done[p] {
+			return false
+		}
+
+		onPath[p] = true
+		if findCycle(v.Elem(), onPath, done) {
+			return true
+		}
+		delete(onPath, p)
+		done[p] = true
+	}
+
+	return false
+}
@@ -0,0 +1,241 @@
+package bind
+
+import (
+	"reflect"
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+)
+
+type testDashboard struct {
+	Widgets []map[string]interface{}
+}
+
+// fakeWatcher records the watchers registered by the binding
+type fakeWatcher struct {
+	targets []string
+	levels  map[string]int
+	fns     []func()
+}
+
+func newFakeWatcher() *fakeWatcher {
+	return &fakeWatcher{levels: make(map[string]int)}
+}
+
+func (w *fakeWatcher) watch(obj reflect.Value, field string, level int, fn func()) func() {
+	w.targets = append(w.targets, field)
+	w.levels[field] = level
+	w.fns = append(w.fns, fn)
+	return func() {
+		for i, t := range w.targets {
+			if t == field {
+				w.targets = append(w.targets[:i], w.targets[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// change calls the functions of the watchers, as if the fields had changed
+func (w *fakeWatcher) change() {
+	for _, fn := range w.fns {
+		fn()
+	}
+}
+
+// watchBind watches the binds of the bind string for the element
+func watchBind(b *Binding, elem jq.JQuery, model interface{}, bstr string, callback func(interface{})) {
+	s := newModelScope(model)
+	s.merge(b.scope)
+	bs := &bindScope{s}
+	root, binds, _, err := bs.evaluate(bstr)
+	if err != nil {
+		panic(err.Error())
+	}
+	b.watchModel(elem, binds, root, bs, callback)
+}
+
+func TestWatchBudget(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	b.MaxWatchers = 20
+
+	// a wide model, each widget has a few values
+	model := &testDashboard{}
+	for i := 0; i < 10; i++ {
+		model.Widgets = append(model.Widgets, map[string]interface{}{"Title": "w", "Value": i, "Unit": "%"})
+	}
+
+	// the field, the 10 widgets and their 30 values are watched
+	watchBind(b, gJQ("<p></p>"), model, "Widgets", func(interface{}) {})
+	if len(w.targets) != 0 || b.watchers.active != 0 || !b.watchers.exceeded {
+		t.Errorf("Expected the nested values to be counted and the field not to be watched, got %v watchers.", b.watchers.active)
+	}
+
+	b.MaxWatchDepth = 1
+	first := gJQ("<p></p>")
+	updates := 0
+	watchBind(b, first, model, "Widgets", func(interface{}) { updates++ })
+	if len(w.targets) != 1 || b.watchers.active != 11 {
+		t.Errorf("Expected the field and the widgets to be watched, got %v watchers.", b.watchers.active)
+	}
+	w.change()
+	if updates != 1 {
+		t.Errorf("Expected a change to reevaluate the bind, got %v updates.", updates)
+	}
+
+	second := gJQ("<p></p>")
+	watchBind(b, second, model, "Widgets", func(interface{}) {})
+	if len(w.targets) != 1 || b.watchers.active != 11 {
+		t.Errorf("Expected the budget to stop the second bind, got %v watchers.", b.watchers.active)
+	}
+
+	// torn down watchers free the budget
+	b.Teardown(first)
+	if len(w.targets) != 0 || b.watchers.active != 0 || b.watchers.exceeded {
+		t.Errorf("Expected the teardown to release the watchers, got %v.", b.watchers.active)
+	}
+	w.change()
+	if updates != 1 {
+		t.Errorf("Expected no update after the teardown, got %v.", updates)
+	}
+	watchBind(b, second, model, "Widgets", func(interface{}) {})
+	if len(w.targets) != 1 || b.watchers.active != 11 {
+		t.Errorf("Expected the bind to be watched once the budget is released, got %v watchers.", b.watchers.active)
+	}
+
+	b.MaxWatchers, b.MaxWatchDepth = 0, 0
+	for i := 0; i < 10; i++ {
+		watchBind(b, gJQ("<p></p>"), model, "Widgets", func(interface{}) {})
+	}
+	if len(w.targets) != 11 || b.watchers.active != 11+10*41 {
+		t.Errorf("Expected no limit without a maximum, got %v watchers.", b.watchers.active)
+	}
+}
+
+func TestWatchDepth(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+	model := &testDashboard{}
+
+	watchBind(b, gJQ("<p></p>"), model, "Widgets", func(interface{}) {})
+	if w.levels["Widgets"] != -1 {
+		t.Errorf("Expected the watch to be unlimited by default, got %v.", w.levels["Widgets"])
+	}
+
+	b.MaxWatchDepth = 2
+	watchBind(b, gJQ("<p></p>"), model, "Widgets", func(interface{}) {})
+	if w.levels["Widgets"] != 2 {
+		t.Errorf("Expected the depth to be passed to watch, got %v.", w.levels["Widgets"])
+	}
+}
+
+type testDrive struct {
+	Name string
+	Root *testFolder
+}
+
+type testFolder struct {
+	Name     string
+	Parent   *testFolder
+	Children []*testFolder
+}
+
+func TestWatchCycles(t *testing.T) {
+	b := NewBindEngine(nil)
+	root := &testFolder{Name: "root"}
+	docs := &testFolder{Name: "docs", Parent: root}
+	pics := &testFolder{Name: "pics", Parent: root}
+	root.Children = []*testFolder{docs, pics}
+	model := &testDrive{Name: "drive", Root: root}
+
+	bo, ok := evaluateObjField("Root", reflect.ValueOf(model))
+	if !ok || !hasCycle(bo.fieldRefl) {
+		t.Fatalf("Expected a cycle through the parents.")
+	}
+
+	targets, release := b.watchTargets(bo)
+	defer release()
+	watched := make([]string, 0)
+	for _, target := range targets {
+		if !target.shallow {
+			t.Errorf("Expected the fields to be watched without their nested values.")
+		}
+		watched = append(watched, reflect.Indirect(target.obj).FieldByName("Name").String()+"."+target.field)
+	}
+	expected := []string{
+		"drive.Root",
+		"root.Name", "root.Parent", "root.Children",
+		"docs.Name", "docs.Parent", "docs.Children",
+		"pics.Name", "pics.Parent", "pics.Children",
+	}
+	if !reflect.DeepEqual(watched, expected) {
+		t.Errorf("Expected each object to be watched once, got %v.", watched)
+	}
+
+	b.MaxWatchDepth = 1
+	if targets, release := b.watchTargets(bo); len(targets) != 4 {
+		t.Errorf("Expected the nested objects to be limited by the depth, got %v targets.", len(targets))
+	} else {
+		release()
+	}
+
+	docs.Parent, pics.Parent = nil, nil
+	if hasCycle(bo.fieldRefl) {
+		t.Errorf("Expected no cycle once the back-pointers are removed.")
+	}
+	shared := &testFolder{Name: "shared"}
+	root.Children = []*testFolder{shared, shared}
+	if hasCycle(bo.fieldRefl) {
+		t.Errorf("Expected an object referred to twice not to be a cycle.")
+	}
+	if targets, _ := b.watchTargets(bo); len(targets) != 1 || targets[0].shallow {
+		t.Errorf("Expected the field to be watched with its nested values by watch.js, got %v.", targets)
+	}
+}
+
+func TestWatchCycleCache(t *testing.T) {
+	b := NewBindEngine(nil)
+	w := newFakeWatcher()
+	b.fields = w
+
+	model := &testDrive{Name: "drive", Root: &testFolder{Name: "root"}}
+	elem := gJQ("<p></p>")
+	watchBind(b, elem, model, "Name", func(interface{}) {})
+	watchBind(b, elem, model, "Root", func(interface{}) {})
+	watchBind(b, elem, model, "Root.Children.length", func(interface{}) {})
+	entry, ok := b.cycles[reflect.ValueOf(model).Pointer()]
+	if !ok || entry.cyclic || entry.refs != 3 || len(b.cycles) != 1 {
+		t.Fatalf("Expected the model to be looked for cycles once for its fields, got %v.", b.cycles)
+	}
+	if w.levels["Root"] != -1 || w.levels["Children"] != -1 {
+		t.Errorf("Expected the fields of a model without cycles to be watched with their nested values.")
+	}
+
+	b.Teardown(elem)
+	if len(b.cycles) != 0 || b.watchers.active != 0 {
+		t.Errorf("Expected the entry of the model to be released with its watchers, got %v.", b.cycles)
+	}
+
+	// once unwatched the model is looked for cycles again
+	root := model.Root
+	docs := &testFolder{Name: "docs", Parent: root}
+	root.Children = []*testFolder{docs}
+	watchBind(b, elem, model, "Root", func(interface{}) {})
+	if entry := b.cycles[reflect.ValueOf(model).Pointer()]; entry == nil || !entry.cyclic {
+		t.Errorf("Expected the cycle to be found once the model is watched again.")
+	}
+	// each object is watched without its nested values, and each
+	// of the targets counts for a watcher
+	if len(w.targets) != 7 || w.levels["Root"] != 0 || b.watchers.active != 7 {
+		t.Errorf("Expected the fields of the objects to be watched one by one, got %v for %v watchers.",
+			w.targets, b.watchers.active)
+	}
+
+	watchBind(b, elem, model, "Root.Children.length", func(interface{}) {})
+	if w.levels["Children"] != 0 {
+		t.Errorf("Expected the collection of a length to be looked for cycles, got level %v.", w.levels["Children"])
+	}
+}
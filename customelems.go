@@ -64,7 +64,7 @@ func (t *CustomTag) PrepareTagContents(elem jq.JQuery, model interface{}) error
 		}
 	}
 
-	elem.Find("wcontents").ReplaceWith(ce.Contents.Html())
+	fillSlots(elem, ce.Contents)
 	return nil
 }
 
@@ -117,12 +117,14 @@ consider using attribute binding instead.`, kind, attr, t.name)
 
 type CustagMan struct {
 	custags    map[string]*CustomTag
+	lazyTags   map[string]*lazyTag
 	tcontainer jq.JQuery
 }
 
 func newCustagMan(tcontainer jq.JQuery) *CustagMan {
 	return &CustagMan{
 		custags:    make(map[string]*CustomTag),
+		lazyTags:   make(map[string]*lazyTag),
 		tcontainer: tcontainer,
 	}
 }
@@ -144,18 +146,9 @@ func (tm *CustagMan) registerTags(tagElems []jq.JQuery, protoMap map[string]inte
 		}
 
 		if prototype, ok := protoMap[tagname]; ok {
-			p := reflect.ValueOf(prototype)
-			if p.Kind() == reflect.Ptr {
-				p = p.Elem()
+			if err := tm.registerTag(tagname, elem, prototype); err != nil {
+				return err
 			}
-
-			if p.Kind() != reflect.Struct {
-				return fmt.Errorf(`Custom tag prototype for "%v", type "%v" is not a struct or pointer to struct.`, tagname, p.Type().String())
-			}
-
-			custag := &CustomTag{tagname, elem, p.Interface(), nil}
-			custag.prepareAttributes(p.Type())
-			tm.custags[strings.ToUpper(tagname)] = custag
 		} else {
 			return fmt.Errorf(`No prototype is specified for the custom element tag "%v", there must be one.`, tagname)
 		}
@@ -164,12 +157,96 @@ func (tm *CustagMan) registerTags(tagElems []jq.JQuery, protoMap map[string]inte
 	return nil
 }
 
+// registerTag validates the prototype and registers the tag with the given template element
+func (tm *CustagMan) registerTag(tagname string, elem jq.JQuery, prototype interface{}) error {
+	p := reflect.ValueOf(prototype)
+	if p.Kind() == reflect.Ptr {
+		p = p.Elem()
+	}
+
+	if !p.IsValid() {
+		return fmt.Errorf(`Custom tag prototype for "%v" is nil, it must be a struct or pointer to struct.`, tagname)
+	}
+
+	if p.Kind() != reflect.Struct {
+		return fmt.Errorf(`Custom tag prototype for "%v", type "%v" is not a struct or pointer to struct.`, tagname, p.Type().String())
+	}
+
+	custag := &CustomTag{tagname, elem, p.Interface(), nil}
+	custag.prepareAttributes(p.Type())
+	tm.custags[strings.ToUpper(tagname)] = custag
+	return nil
+}
+
+// TagDef defines a custom tag for RegisterAll
+type TagDef struct {
+	// Name is the tag name
+	Name string
+	// TemplateId is the id of the element in the templates whose contents
+	// are the tag's template, like a <welement>
+	TemplateId string
+	// Model is the prototype of the tag's models
+	Model interface{}
+}
+
+// TagErrors holds the errors of the tags that could not be registered by RegisterAll
+type TagErrors []error
+
+func (errs TagErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// RegisterAll registers a batch of custom tags. The tags are validated like the ones
+// registered with RegisterCustomTags, a tag whose name has already been taken is a
+// conflict. The valid tags are registered even if some others fail, the returned
+// error is a TagErrors with all the failures.
+func (tm *CustagMan) RegisterAll(defs []TagDef) error {
+	errs := make(TagErrors, 0)
+	for _, def := range defs {
+		if def.Name == "" {
+			errs = append(errs, fmt.Errorf(`No tag name specified for the template "%v".`, def.TemplateId))
+			continue
+		}
+
+		_, isLazy := tm.lazyTags[strings.ToUpper(def.Name)]
+		if _, exists := tm.custags[strings.ToUpper(def.Name)]; exists || isLazy {
+			errs = append(errs, fmt.Errorf(`Custom tag "%v" has already been registered.`, def.Name))
+			continue
+		}
+
+		elem := tm.tcontainer.Find("#" + def.TemplateId)
+		if def.TemplateId == "" || elem.Length == 0 {
+			errs = append(errs, fmt.Errorf(`Template "%v" for the custom tag "%v" cannot be found.`, def.TemplateId, def.Name))
+			continue
+		}
+
+		if err := tm.registerTag(def.Name, elem.First(), def.Model); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
 // GetCustomTag checks if the element's tag is of a registered custom tag
 func (tm *CustagMan) GetCustomTag(elem jq.JQuery) (ct bind.CustomTag, ok bool) {
 	ct, ok = tm.custags[strings.ToUpper(elem.Prop("tagName").(string))]
 	return
 }
 
+// GetCustomTagByName returns the registered custom tag with the given name
+func (tm *CustagMan) GetCustomTagByName(tagname string) (ct bind.CustomTag, ok bool) {
+	ct, ok = tm.custags[strings.ToUpper(tagname)]
+	return
+}
+
 func isForbiddenAttr(attr string) bool {
 	lattr := strings.ToLower(attr)
 	for _, a := range ForbiddenAttrs {
@@ -179,3 +256,99 @@ func isForbiddenAttr(attr string) bool {
 	}
 	return false
 }
+
+// Template returns the element with the given id in the templates,
+// it implements bind.TemplateSource
+func (tm *CustagMan) Template(id string) (elem jq.JQuery, ok bool) {
+	elem = tm.tcontainer.Find("#" + id)
+	return elem, elem.Length > 0
+}
+
+// TagLoader loads the definition of a lazy custom tag, for example by fetching a
+// script or a template that's not part of the initial page. It's called in a goroutine
+// so it may block. The template of the returned TagDef must be in the templates
+// when it returns.
+type TagLoader func() (TagDef, error)
+
+// lazyTag is a custom tag whose definition is loaded the first time it's used
+type lazyTag struct {
+	name   string
+	loader TagLoader
+	state  bind.AsyncState
+	loaded chan struct{}
+}
+
+// RegisterLazy registers a custom tag whose definition is loaded by the loader
+// the first time the tag is encountered while binding. The elements of the tag get
+// bind.TagLoadingClass as a placeholder until it's loaded, they're bound afterwards.
+func (tm *CustagMan) RegisterLazy(tagname string, loader TagLoader) error {
+	name := strings.ToUpper(tagname)
+	if _, exists := tm.custags[name]; exists {
+		return fmt.Errorf(`Custom tag "%v" has already been registered.`, tagname)
+	}
+	if _, exists := tm.lazyTags[name]; exists {
+		return fmt.Errorf(`Custom tag "%v" has already been registered.`, tagname)
+	}
+
+	tm.lazyTags[name] = &lazyTag{name: tagname, loader: loader}
+	return nil
+}
+
+// TagStatus returns the loading status of a lazy custom tag,
+// it's AsyncResolved for the other registered tags
+func (tm *CustagMan) TagStatus(tagname string) bind.AsyncStatus {
+	if lt, ok := tm.lazyTags[strings.ToUpper(tagname)]; ok {
+		return lt.state.Status
+	}
+	if _, ok := tm.custags[strings.ToUpper(tagname)]; ok {
+		return bind.AsyncResolved
+	}
+	return bind.AsyncIdle
+}
+
+// PendingCustomTag checks whether the element is of a lazy custom tag that's
+// not loaded yet, it implements bind.PendingTagSource
+func (tm *CustagMan) PendingCustomTag(elem jq.JQuery) (loaded <-chan struct{}, pending bool) {
+	tagname, _ := elem.Prop("tagName").(string)
+	return tm.pendingTag(tagname)
+}
+
+// pendingTag starts loading the lazy tag if it's not loaded yet
+func (tm *CustagMan) pendingTag(tagname string) (loaded <-chan struct{}, pending bool) {
+	name := strings.ToUpper(tagname)
+	lt, ok := tm.lazyTags[name]
+	if !ok {
+		return nil, false
+	}
+
+	switch lt.state.Status {
+	case bind.AsyncResolved, bind.AsyncFailed:
+		return nil, false
+	case bind.AsyncIdle:
+		lt.state.Start()
+		lt.loaded = make(chan struct{})
+		go tm.loadTag(lt)
+	}
+	return lt.loaded, true
+}
+
+func (tm *CustagMan) loadTag(lt *lazyTag) {
+	defer close(lt.loaded)
+
+	def, err := lt.loader()
+	if err == nil {
+		elem := tm.tcontainer.Find("#" + def.TemplateId)
+		if def.TemplateId == "" || elem.Length == 0 {
+			err = fmt.Errorf(`Template "%v" for the custom tag "%v" cannot be found.`, def.TemplateId, lt.name)
+		} else {
+			err = tm.registerTag(lt.name, elem.First(), def.Model)
+		}
+	}
+
+	if err != nil {
+		println(fmt.Sprintf(`Loading the custom tag "%v" failed: %v`, lt.name, err.Error()))
+		lt.state.Fail(err)
+		return
+	}
+	lt.state.Resolve()
+}
@@ -0,0 +1,171 @@
+package wade
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	jq "github.com/gopherjs/jquery"
+
+	"github.com/phaikawl/wade/bind"
+)
+
+type testTagModel struct {
+	Subject string
+}
+
+func TestRegisterAll(t *testing.T) {
+	tm := newCustagMan(jq.JQuery{Length: 1})
+	err := tm.RegisterAll([]TagDef{
+		{"errorlist", "tmpl-errorlist", testTagModel{}},
+		{"userinfo", "tmpl-userinfo", &testTagModel{}},
+		{"ErrorList", "tmpl-errorlist2", testTagModel{}},
+		{"badtag", "tmpl-badtag", "not a struct"},
+		{"", "tmpl-noname", testTagModel{}},
+		{"niltag", "tmpl-niltag", nil},
+		{"nilptrtag", "tmpl-nilptrtag", (*testTagModel)(nil)},
+	})
+
+	errs, ok := err.(TagErrors)
+	if !ok || len(errs) != 5 {
+		t.Fatalf("Expected 5 aggregated errors, got %v.", err)
+	}
+	for i, expected := range []string{`"ErrorList" has already been registered`, `"badtag"`, `"tmpl-noname"`, `"niltag" is nil`, `"nilptrtag" is nil`} {
+		if !strings.Contains(errs[i].Error(), expected) {
+			t.Errorf("Expected error %v to be about %v, got %v.", i, expected, errs[i])
+		}
+	}
+	if !strings.Contains(err.Error(), "\n") {
+		t.Errorf("Expected the errors to be reported together, got %q.", err.Error())
+	}
+
+	for _, name := range []string{"errorlist", "userinfo"} {
+		if _, ok := tm.GetCustomTagByName(name); !ok {
+			t.Errorf("Expected %v to be registered.", name)
+		}
+	}
+	for _, name := range []string{"badtag", "niltag", "nilptrtag"} {
+		if _, ok := tm.GetCustomTagByName(name); ok {
+			t.Errorf("Expected the invalid tag %v not to be registered.", name)
+		}
+	}
+
+	if err := tm.RegisterAll([]TagDef{{"newtag", "tmpl-newtag", testTagModel{}}}); err != nil {
+		t.Errorf("Unexpected error %v.", err)
+	}
+}
+
+func TestLazyTags(t *testing.T) {
+	tm := newCustagMan(jq.JQuery{Length: 1})
+	loads := 0
+	err := tm.RegisterLazy("chart", func() (TagDef, error) {
+		loads++
+		return TagDef{"chart", "tmpl-chart", testTagModel{}}, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tm.RegisterLazy("broken", func() (TagDef, error) {
+		return TagDef{}, fmt.Errorf("network error")
+	})
+	if tm.RegisterLazy("Chart", nil) == nil || tm.RegisterAll([]TagDef{{"chart", "tmpl-chart", testTagModel{}}}) == nil {
+		t.Errorf("Expected an error for a name taken by a lazy tag.")
+	}
+
+	if _, pending := tm.pendingTag("p"); pending {
+		t.Errorf("Expected an ordinary element not to be pending.")
+	}
+	if tm.TagStatus("chart") != bind.AsyncIdle || loads != 0 {
+		t.Errorf("Expected the tag not to be loaded before it's used.")
+	}
+
+	loaded, pending := tm.pendingTag("CHART")
+	again, pendingAgain := tm.pendingTag("chart")
+	if !pending || !pendingAgain || loaded != again || tm.TagStatus("chart") != bind.AsyncPending {
+		t.Fatalf("Expected the tag to be loading.")
+	}
+	if _, ok := tm.GetCustomTagByName("chart"); ok {
+		t.Errorf("Expected the tag not to be registered before it's loaded.")
+	}
+
+	<-loaded
+	if _, ok := tm.GetCustomTagByName("chart"); !ok || loads != 1 || tm.TagStatus("chart") != bind.AsyncResolved {
+		t.Errorf("Expected the tag to be registered once loaded.")
+	}
+	if _, pending := tm.pendingTag("chart"); pending {
+		t.Errorf("Expected the loaded tag not to be pending anymore.")
+	}
+
+	loaded, _ = tm.pendingTag("broken")
+	<-loaded
+	if _, pending := tm.pendingTag("broken"); pending || tm.TagStatus("broken") != bind.AsyncFailed {
+		t.Errorf("Expected the failed tag to be marked as failed.")
+	}
+	if _, ok := tm.GetCustomTagByName("broken"); ok {
+		t.Errorf("Expected the failed tag not to be registered.")
+	}
+}
+
+func TestAssignSlots(t *testing.T) {
+	// <h2 slot="header">, text, <p slot="body">, <p>, <em slot="footer">, <p slot="body">
+	children := []string{"header", "", "body", "", "footer", "body"}
+	declared := map[string]bool{"header": true, "body": true}
+
+	assigned := assignSlots(children, declared)
+	expected := map[string][]int{
+		"header": {0},
+		"body":   {2, 5},
+		"":       {1, 3, 4},
+	}
+	if !reflect.DeepEqual(assigned, expected) {
+		t.Errorf("Expected the children to be distributed to %v, got %v.", expected, assigned)
+	}
+
+	assigned = assignSlots([]string{"", ""}, declared)
+	if _, ok := assigned["header"]; ok || len(assigned[""]) != 2 {
+		t.Errorf("Expected the unnamed children to go to the default slot only, got %v.", assigned)
+	}
+}
+
+func TestPrepareTagContents(t *testing.T) {
+	tag := &CustomTag{
+		name: "mypanel",
+		elem: gJQ(`<welement tagname="mypanel">` +
+			`<header><wslot name="header"></wslot></header>` +
+			`<section><wslot name="body">No content</wslot></section>` +
+			`<aside><wcontents></wcontents></aside>` +
+			`</welement>`),
+		prototype:   testTagModel{},
+		publicAttrs: []string{},
+	}
+
+	elem := gJQ(`<mypanel><h2 slot="header">Title</h2><p>Note</p><p slot="body">Text</p><em slot="footer">Footer</em></mypanel>`)
+	if err := tag.PrepareTagContents(elem, tag.NewModel(elem)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if elem.Find(SlotTag+", "+ContentsTag).Length != 0 {
+		t.Errorf("Expected the slots to be replaced, got %v.", elem.Html())
+	}
+	if h := elem.Find("header").Html(); h != `<h2 slot="header">Title</h2>` {
+		t.Errorf("Expected the header to go to the header slot, got %v.", h)
+	}
+	if h := elem.Find("section").Html(); h != `<p slot="body">Text</p>` {
+		t.Errorf("Expected the body to go to the body slot, got %v.", h)
+	}
+	if h := elem.Find("aside").Html(); h != `<p>Note</p><em slot="footer">Footer</em>` {
+		t.Errorf("Expected the other children to go to the default slot, got %v.", h)
+	}
+
+	// the contents of a slot without children are kept as fallback
+	elem = gJQ(`<mypanel><h2 slot="header">Title</h2></mypanel>`)
+	if err := tag.PrepareTagContents(elem, tag.NewModel(elem)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if h := elem.Find("section").Html(); h != "No content" {
+		t.Errorf("Expected the fallback contents of the body slot, got %v.", h)
+	}
+	if elem.Find("aside").Html() != "" || elem.Find("header > h2").Text() != "Title" {
+		t.Errorf("Expected the default slot to be empty, got %v.", elem.Html())
+	}
+}
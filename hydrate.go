@@ -0,0 +1,38 @@
+package wade
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JsonScriptType is the type of the script elements holding embedded JSON data
+const JsonScriptType = "application/json"
+
+// HydrateFrom decodes the JSON embedded in the document by the server,
+// in a <script type="application/json"> element with the given id, into the model.
+// It's meant to be called in a page controller before the model is bound.
+// For example, with
+//	<script type="application/json" id="initial-todos">[{"Title": "Write docs"}]</script>
+// the entries can be loaded with
+//	err := wd.HydrateFrom("initial-todos", &model.Entries)
+func (wd *Wade) HydrateFrom(scriptId string, model interface{}) error {
+	elem := gJQ("script#" + scriptId)
+	if elem.Length == 0 {
+		return fmt.Errorf(`Script element "%v" for the embedded JSON cannot be found.`, scriptId)
+	}
+
+	if typ := elem.Attr("type"); typ != JsonScriptType {
+		return fmt.Errorf(`Script element "%v" has type "%v", it should be "%v".`, scriptId, typ, JsonScriptType)
+	}
+
+	return hydrate(scriptId, elem.Text(), model)
+}
+
+// hydrate decodes the JSON source of the script element into the model
+func hydrate(scriptId, src string, model interface{}) error {
+	if err := json.Unmarshal([]byte(src), model); err != nil {
+		return fmt.Errorf(`Invalid JSON in the script element "%v": %v`, scriptId, err.Error())
+	}
+
+	return nil
+}
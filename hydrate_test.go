@@ -0,0 +1,34 @@
+package wade
+
+import (
+	"testing"
+)
+
+type testHydratedUser struct {
+	Name  string
+	Age   int
+	Roles []string
+}
+
+func TestHydrate(t *testing.T) {
+	user := &testHydratedUser{}
+	err := hydrate("user", `{"Name": "wade", "Age": 3, "Roles": ["admin", "dev"]}`, user)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user.Name != "wade" || user.Age != 3 || len(user.Roles) != 2 || user.Roles[1] != "dev" {
+		t.Errorf("Unexpected hydrated model %+v.", user)
+	}
+
+	if err := hydrate("user", `{"Name": "wade",`, user); err == nil {
+		t.Errorf("Expected an error for malformed JSON.")
+	}
+	if err := hydrate("user", `{"Age": "three"}`, user); err == nil {
+		t.Errorf("Expected an error for a mismatched type.")
+	}
+
+	wd := &Wade{}
+	if err := wd.HydrateFrom("missing", user); err == nil {
+		t.Errorf("Expected an error for a missing script element.")
+	}
+}
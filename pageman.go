@@ -2,6 +2,7 @@ package wade
 
 import (
 	"fmt"
+	"net/url"
 	"reflect"
 	"regexp"
 	"strings"
@@ -103,18 +104,55 @@ type PageHandler func()
 
 // PageManager is Page Manager
 type PageManager struct {
-	router       js.Object
+	router       pageRouter
 	currentPage  *page
 	startPageId  string
 	basePath     string
 	notFoundPage *page
 	container    jq.JQuery
 	tcontainer   jq.JQuery
+	// path and query are the path of the current page and its query parameters
+	path  string
+	query url.Values
 
 	binding       *bind.Binding
 	tm            *CustagMan
 	pc            *PageCtrl
 	displayScopes map[string]displayScope
+	ready         readyCallbacks
+}
+
+// readyCallbacks holds the functions to be called when a page has been bound
+type readyCallbacks struct {
+	fired bool
+	once  []func()
+	each  []func()
+}
+
+// addOnce adds a function to be called after the first page is bound,
+// it's called right away if that has already happened
+func (r *readyCallbacks) addOnce(fn func()) {
+	if r.fired {
+		fn()
+		return
+	}
+	r.once = append(r.once, fn)
+}
+
+func (r *readyCallbacks) addEach(fn func()) {
+	r.each = append(r.each, fn)
+}
+
+func (r *readyCallbacks) fire() {
+	fns := r.once
+	r.once = nil
+	r.fired = true
+	for _, fn := range fns {
+		fn()
+	}
+	for _, fn := range r.each {
+		fn()
+	}
 }
 
 // PageView provides access to the page-specific data inside a controller func
@@ -183,7 +221,7 @@ func newPageManager(startPage, basePath string,
 	container := gJQ("<div class='wade-wrapper'></div>")
 	container.AppendTo(gJQ("body"))
 	return &PageManager{
-		router:        js.Global.Get("RouteRecognizer").New(),
+		router:        jsRouter{js.Global.Get("RouteRecognizer").New()},
 		currentPage:   nil,
 		basePath:      basePath,
 		startPageId:   startPage,
@@ -249,17 +287,64 @@ func documentUrl() string {
 	if location.IsNull() || location.IsUndefined() {
 		location = js.Global.Get("document").Get("location")
 	}
-	return location.Get("pathname").Str()
+	return location.Get("pathname").Str() + location.Get("search").Str()
+}
+
+// splitQuery splits a url into its path and query parameters
+func splitQuery(u string) (path string, query url.Values) {
+	path = u
+	query = make(url.Values)
+	if i := strings.Index(u, "?"); i != -1 {
+		path = u[:i]
+		query, _ = url.ParseQuery(u[i+1:])
+	}
+	return
+}
+
+// encodeQuery returns the query string for the parameters, with the leading "?"
+func encodeQuery(query url.Values) string {
+	if q := query.Encode(); q != "" {
+		return "?" + q
+	}
+	return ""
 }
 
 func (pm *PageManager) setupPageOnLoad() {
-	path := pm.cutPath(documentUrl())
+	path, query := splitQuery(pm.cutPath(documentUrl()))
 	if path == "/" {
 		startPage := pm.page(pm.startPageId)
 		path = startPage.path
-		gHistory.Call("replaceState", nil, startPage.title, pm.Url(path))
+		gHistory.Call("replaceState", nil, startPage.title, pm.Url(path)+encodeQuery(query))
 	}
-	pm.updatePage(path, false)
+	pm.updatePage(path+encodeQuery(query), false)
+}
+
+// QueryParam returns the value of a query parameter of the current page's url,
+// ok is false if it's not set. The Pager is the location of the query binder,
+// which reads the parameters when the page is bound.
+func (pm *PageManager) QueryParam(name string) (value string, ok bool) {
+	if _, ok = pm.query[name]; ok {
+		value = pm.query.Get(name)
+	}
+	return
+}
+
+// SetQueryParams sets the query parameters of the current page's url, without
+// reloading the page or adding a history entry. The parameters with an empty
+// value are removed.
+func (pm *PageManager) SetQueryParams(params map[string]string) {
+	if pm.query == nil {
+		pm.query = make(url.Values)
+	}
+	for name, value := range params {
+		if value == "" {
+			pm.query.Del(name)
+		} else {
+			pm.query.Set(name, value)
+		}
+	}
+
+	gHistory.Call("replaceState", nil, "", pm.Url(pm.path)+encodeQuery(pm.query))
 }
 
 func (pm *PageManager) prepare() {
@@ -309,29 +394,14 @@ func walk(elem jq.JQuery, pm *PageManager) {
 
 func (pm *PageManager) updatePage(url string, pushState bool) {
 	url = pm.cutPath(url)
-	matches := pm.router.Call("recognize", url)
 	println("path: " + url)
-	if matches.IsUndefined() || matches.Length() == 0 {
-		if pm.notFoundPage != nil {
-			pm.updatePage(pm.notFoundPage.path, false)
-		} else {
-			panic("Page not found. No 404 handler declared.")
-		}
-	}
-
-	match := matches.Index(0)
-	pageId := match.Get("handler").Invoke().Str()
+	pm.path, pm.query = splitQuery(url)
+	pageId, params := pm.resolve(pm.path)
 	page := pm.page(pageId)
 	if pushState {
 		gHistory.Call("pushState", nil, page.title, pm.Url(url))
 	}
-	params := make(map[string]interface{})
-	prs := match.Get("params")
-	if !prs.IsUndefined() {
-		params = prs.Interface().(map[string]interface{})
-	}
-
-	gJQ("head title").SetText(page.title)
+	pm.SetTitle(page.title)
 	if pm.currentPage != page {
 		pm.currentPage = page
 		pcontents := pm.tcontainer.Clone()
@@ -367,9 +437,18 @@ func (pm *PageManager) updatePage(url string, pushState bool) {
 
 			pm.updatePage(pagepath, true)
 		})
+
+		pm.ready.fire()
 	}
 }
 
+// SetTitle sets the title of the document. On navigation, the title is set to the
+// title of the page, before the page is bound; the title binder can set it from the
+// model instead.
+func (pm *PageManager) SetTitle(title string) {
+	pm.binding.SetTitle(title)
+}
+
 // PageUrl returns the url and route parameters for the specified pageId
 func (pm *PageManager) PageUrl(pageId string, params []interface{}) (u string, err error) {
 	err = nil
@@ -433,6 +512,19 @@ func (pm *PageManager) bind(params map[string]interface{}) {
 	pm.pc = pc
 }
 
+// OnReady registers a function to be called once, after the first page has been
+// bound (including its custom elements), to hide a loading splash for example.
+// It's called right away if that has already happened.
+func (pm *PageManager) OnReady(fn func()) {
+	pm.ready.addOnce(fn)
+}
+
+// OnPageReady registers a function to be called each time a page has been bound,
+// on start and after each navigation.
+func (pm *PageManager) OnPageReady(fn func()) {
+	pm.ready.addEach(fn)
+}
+
 // RegisterController sets the controller function for the specified
 // page / page group.
 func (pm *PageManager) RegisterController(displayScope string, fn PageControllerFunc) {
@@ -462,14 +554,7 @@ func (p Page) Register(pageId string, pm *PageManager) displayScope {
 		panic(fmt.Sprintf(`Page or page group with id "%v" already registered.`, pageId))
 	}
 
-	pm.router.Call("add", []map[string]interface{}{
-		map[string]interface{}{
-			"path": route,
-			"handler": func() string {
-				return pageId
-			},
-		},
-	})
+	pm.router.add(route, pageId)
 
 	page := newPage(pageId, route, p.Title)
 	pm.displayScopes[pageId] = page
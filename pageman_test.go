@@ -0,0 +1,230 @@
+package wade
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gopherjs/gopherjs/js"
+
+	"github.com/phaikawl/wade/bind"
+)
+
+func TestReadyCallbacks(t *testing.T) {
+	tm := newCustagMan(gJQ(`<div><welement id="tmpl-heading"><h1 bind-text="Subject"></h1></welement></div>`))
+	if err := tm.RegisterAll([]TagDef{{"heading", "tmpl-heading", testTagModel{}}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b := bind.NewBindEngine(tm)
+	b.SetTitleSetter(&fakeTitle{})
+	// the pages have no controllers, so they're bound once without watchers
+	b.RegisterConstant("Greeting", "Welcome")
+	b.RegisterConstant("About", "About us")
+	container := gJQ("<div></div>").AppendTo(gJQ("body"))
+	defer container.Remove()
+	pm := &PageManager{
+		router:        &fakeRouter{},
+		displayScopes: make(map[string]displayScope),
+		binding:       b,
+		tm:            tm,
+		container:     container,
+		tcontainer: gJQ(`<div><p w-belong="pg-home"><heading bind="Subject: Greeting"></heading></p>` +
+			`<p w-belong="pg-about"><heading bind="Subject: About"></heading></p><span bind-text="Greeting"></span></div>`),
+	}
+	pm.Routes([]Route{
+		{Path: "/home", PageId: "pg-home", Title: "Home"},
+		{Path: "/about", PageId: "pg-about", Title: "About"},
+	})
+
+	// the callbacks see the page bound, with its custom elements rendered
+	var ready, pageReady []string
+	rendered := func() string {
+		return container.Find("span").Text() + "/" + container.Find("h1").Text()
+	}
+	pm.OnReady(func() { ready = append(ready, rendered()) })
+	pm.OnPageReady(func() { pageReady = append(pageReady, rendered()) })
+
+	// the initial page is bound
+	pm.updatePage("/home", false)
+	if !reflect.DeepEqual(ready, []string{"Welcome/Welcome"}) || !reflect.DeepEqual(pageReady, ready) {
+		t.Errorf("Expected both callbacks to be fired after the initial binding, got %v and %v.", ready, pageReady)
+	}
+
+	// navigation
+	pm.updatePage("/about", false)
+	if len(ready) != 1 || !reflect.DeepEqual(pageReady, []string{"Welcome/Welcome", "Welcome/About us"}) {
+		t.Errorf("Expected only the page callback to be fired after a navigation, got %v and %v.", ready, pageReady)
+	}
+
+	// staying on the same page doesn't bind it again
+	pm.updatePage("/about", false)
+	if len(pageReady) != 2 {
+		t.Errorf("Expected no callback without a page change, got %v.", pageReady)
+	}
+
+	late := false
+	pm.OnReady(func() { late = true })
+	if !late {
+		t.Errorf("Expected a callback registered after the app is ready to be called right away.")
+	}
+}
+
+type fakeTitle struct {
+	title string
+}
+
+func (t *fakeTitle) SetTitle(title string) { t.title = title }
+
+func TestPageTitle(t *testing.T) {
+	title := &fakeTitle{}
+	b := bind.NewBindEngine(nil)
+	b.SetTitleSetter(title)
+	pm := &PageManager{binding: b}
+
+	pm.SetTitle("Home")
+	if title.title != "Home" {
+		t.Errorf("Expected the document title to be set, got %q.", title.title)
+	}
+}
+
+// fakeRouter matches urls segment by segment, route parameters match any segment
+type fakeRouter struct {
+	paths, ids []string
+}
+
+func (r *fakeRouter) add(path, pageId string) {
+	r.paths = append(r.paths, path)
+	r.ids = append(r.ids, pageId)
+}
+
+func (r *fakeRouter) recognize(url string) (string, map[string]interface{}, bool) {
+	urlSegs := strings.Split(strings.Trim(url, "/"), "/")
+	for i, path := range r.paths {
+		segs := strings.Split(strings.Trim(path, "/"), "/")
+		if len(segs) != len(urlSegs) {
+			continue
+		}
+		params := make(map[string]interface{})
+		matched := true
+		for j, seg := range segs {
+			if strings.HasPrefix(seg, ":") {
+				params[seg[1:]] = urlSegs[j]
+			} else if seg != urlSegs[j] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return r.ids[i], params, true
+		}
+	}
+	return "", nil, false
+}
+
+func TestRoutes(t *testing.T) {
+	newPm := func() *PageManager {
+		return &PageManager{
+			router:        &fakeRouter{},
+			displayScopes: make(map[string]displayScope),
+		}
+	}
+	ctrl := func(pc *PageCtrl) interface{} { return nil }
+
+	pm := newPm()
+	pm.Routes([]Route{
+		{Path: "/home", PageId: "pg-home", Title: "Home", Controller: ctrl},
+		{Path: "/post/:postid", PageId: "pg-post", Title: "Post", Controller: ctrl},
+		{Path: "/user/:name/posts", PageId: "pg-user-posts", Title: "Posts"},
+		{Path: "/404", PageId: "pg-not-found", Title: "Not found", NotFound: true},
+	})
+
+	tests := []struct {
+		url    string
+		pageId string
+		params map[string]interface{}
+	}{
+		{"/home", "pg-home", map[string]interface{}{}},
+		{"/post/12", "pg-post", map[string]interface{}{"postid": "12"}},
+		{"/user/alice/posts", "pg-user-posts", map[string]interface{}{"name": "alice"}},
+		{"/404", "pg-not-found", map[string]interface{}{}},
+		{"/nowhere", "pg-not-found", map[string]interface{}{}},
+	}
+	for _, test := range tests {
+		pageId, params := pm.resolve(test.url)
+		if pageId != test.pageId || !reflect.DeepEqual(params, test.params) {
+			t.Errorf("Navigating to %v, expected page %v with %v, got %v with %v.",
+				test.url, test.pageId, test.params, pageId, params)
+		}
+	}
+
+	if pm.page("pg-post").controller == nil || pm.page("pg-user-posts").controller != nil {
+		t.Errorf("Expected the controllers of the table to be registered.")
+	}
+	if pm.page("pg-post").title != "Post" {
+		t.Errorf("Expected the page title to be registered.")
+	}
+
+	conflicts := [][]Route{
+		{{Path: "/post/:id", PageId: "pg-a"}, {Path: "/post/:slug/", PageId: "pg-b"}},
+		{{Path: "/home", PageId: "pg-a"}, {Path: "/about", PageId: "pg-a"}},
+		{{Path: "/", PageId: ""}},
+	}
+	for _, routes := range conflicts {
+		pm := newPm()
+		if pm.routeConflicts(routes) == nil {
+			t.Errorf("Expected a conflict for %v.", routes)
+		}
+	}
+
+	// the table is checked against the pages registered before it
+	pm = newPm()
+	pm.RegisterDisplayScopes(map[string]DisplayScope{
+		"pg-post": MakePage("/post/:postid", "Post"),
+	})
+	if pm.routeConflicts([]Route{{Path: "/post/:slug", PageId: "pg-slug"}}) == nil {
+		t.Errorf("Expected a conflict with an already registered page.")
+	}
+	if pm.routeConflicts([]Route{{Path: "/post/new", PageId: "pg-new"}}) != nil {
+		t.Errorf("Expected a static path not to conflict with a parameter.")
+	}
+}
+
+// fakeHistory records the urls set through the history api
+type fakeHistory struct {
+	JsStub
+	urls []string
+}
+
+func (h *fakeHistory) Call(name string, args ...interface{}) js.Object {
+	h.urls = append(h.urls, name+" "+args[2].(string))
+	return h
+}
+
+func TestPagerQuery(t *testing.T) {
+	history := &fakeHistory{}
+	gHistory = history
+	defer func() { gHistory = nil }()
+
+	pm := &PageManager{basePath: "/app"}
+	pm.path, pm.query = splitQuery("/users?filter=active&page=2")
+	if pm.path != "/users" {
+		t.Errorf("Expected the path without the query, got %v.", pm.path)
+	}
+	if v, ok := pm.QueryParam("filter"); !ok || v != "active" {
+		t.Errorf("Expected the filter parameter to be read from the url, got %q.", v)
+	}
+	if _, ok := pm.QueryParam("sort"); ok {
+		t.Errorf("Expected a missing parameter not to be set.")
+	}
+
+	// the Pager is the location of the query binder
+	var location bind.QueryLocation = pm
+	location.SetQueryParams(map[string]string{"filter": "done", "page": ""})
+	if len(history.urls) != 1 || history.urls[0] != "replaceState /app/users?filter=done" {
+		t.Errorf("Expected the url to be replaced with the new parameters, got %v.", history.urls)
+	}
+	location.SetQueryParams(map[string]string{"filter": ""})
+	if history.urls[1] != "replaceState /app/users" {
+		t.Errorf("Expected the query to be removed from the url, got %v.", history.urls[1])
+	}
+}
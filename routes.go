@@ -0,0 +1,145 @@
+package wade
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// pageRouter maps the paths of the pages to their ids
+type pageRouter interface {
+	add(path, pageId string)
+	// recognize returns the id of the page matching url and its route parameters
+	recognize(url string) (pageId string, params map[string]interface{}, ok bool)
+}
+
+// jsRouter is a pageRouter using route-recognizer
+type jsRouter struct {
+	js.Object
+}
+
+func (r jsRouter) add(path, pageId string) {
+	r.Call("add", []map[string]interface{}{
+		map[string]interface{}{
+			"path": path,
+			"handler": func() string {
+				return pageId
+			},
+		},
+	})
+}
+
+func (r jsRouter) recognize(url string) (pageId string, params map[string]interface{}, ok bool) {
+	matches := r.Call("recognize", url)
+	if matches.IsUndefined() || matches.Length() == 0 {
+		return
+	}
+
+	match := matches.Index(0)
+	pageId = match.Get("handler").Invoke().Str()
+	params = make(map[string]interface{})
+	prs := match.Get("params")
+	if !prs.IsUndefined() {
+		params = prs.Interface().(map[string]interface{})
+	}
+	return pageId, params, true
+}
+
+// Route is an entry of a routing table registered with PageManager.Routes
+type Route struct {
+	Path       string
+	PageId     string
+	Title      string
+	Controller PageControllerFunc
+
+	// NotFound makes the page the one shown when no route matches the url
+	NotFound bool
+}
+
+// routeKey returns the shape of a route path, paths with the same shape
+// match the same urls. Parameters are replaced by ":" and globs by "*".
+func routeKey(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range segments {
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			segments[i] = ":"
+		case strings.HasPrefix(seg, "*"):
+			segments[i] = "*"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// routeConflicts checks the routes against each other and against the already
+// registered pages. It returns an error for a page id used twice or for two
+// paths that only differ by the names of their parameters.
+func (pm *PageManager) routeConflicts(routes []Route) error {
+	ids := make(map[string]bool)
+	paths := make(map[string]string)
+	for id, ds := range pm.displayScopes {
+		ids[id] = true
+		if p, ok := ds.(*page); ok {
+			paths[routeKey(p.path)] = p.path
+		}
+	}
+
+	for _, r := range routes {
+		if r.PageId == "" {
+			return fmt.Errorf(`The route "%v" has an empty page id.`, r.Path)
+		}
+		if ids[r.PageId] {
+			return fmt.Errorf(`Page or page group with id "%v" already registered.`, r.PageId)
+		}
+		ids[r.PageId] = true
+
+		key := routeKey(r.Path)
+		if other, exist := paths[key]; exist {
+			return fmt.Errorf(`The route "%v" of page "%v" overlaps with the route "%v".`,
+				r.Path, r.PageId, other)
+		}
+		paths[key] = r.Path
+	}
+
+	return nil
+}
+
+// Routes registers the pages of a routing table in one call, with their controllers.
+// Nothing is registered if a page id is already used or if two routes match the
+// same urls, for example "/post/:id" and "/post/:slug".
+// Usage:
+//	pm.Routes([]Route{
+//		{Path: "/home", PageId: "pg-home", Title: "Home", Controller: homeCtrl},
+//		{Path: "/post/:postid", PageId: "pg-post", Title: "Post", Controller: postCtrl},
+//		{Path: "/404", PageId: "pg-not-found", Title: "Not found", NotFound: true},
+//	})
+func (pm *PageManager) Routes(routes []Route) {
+	if err := pm.routeConflicts(routes); err != nil {
+		panic(err.Error())
+	}
+
+	for _, r := range routes {
+		page := Page{Route: r.Path, Title: r.Title}.Register(r.PageId, pm)
+		if r.Controller != nil {
+			page.setController(r.Controller)
+		}
+		if r.NotFound {
+			pm.SetNotFoundPage(r.PageId)
+		}
+	}
+}
+
+// resolve returns the page to be shown for url and its route parameters,
+// falling back to the not-found page
+func (pm *PageManager) resolve(url string) (pageId string, params map[string]interface{}) {
+	pageId, params, ok := pm.router.recognize(url)
+	if ok {
+		return
+	}
+
+	if pm.notFoundPage == nil {
+		panic("Page not found. No 404 handler declared.")
+	}
+	return pm.notFoundPage.id, make(map[string]interface{})
+}
@@ -0,0 +1,180 @@
+package wade
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+	jq "github.com/gopherjs/jquery"
+)
+
+// keyAliases maps the names accepted in shortcut combos to the values of KeyboardEvent.key
+var keyAliases = map[string]string{
+	"space": " ",
+	"esc":   "escape",
+	"del":   "delete",
+	"up":    "arrowup",
+	"down":  "arrowdown",
+	"left":  "arrowleft",
+	"right": "arrowright",
+	"plus":  "+",
+}
+
+// keyCombo is a key with the state of the modifier keys
+type keyCombo struct {
+	key                    string
+	ctrl, shift, alt, meta bool
+}
+
+// parseCombo parses a shortcut combo like "ctrl+k", "shift+alt+n" or "/",
+// the key names are case-insensitive
+func parseCombo(combo string) (c keyCombo, err error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(combo)), "+")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if i == len(parts)-1 {
+			if part == "" {
+				return c, fmt.Errorf(`Invalid shortcut "%v", the key is missing.`, combo)
+			}
+			if alias, ok := keyAliases[part]; ok {
+				part = alias
+			}
+			c.key = part
+			break
+		}
+
+		switch part {
+		case "ctrl", "control":
+			c.ctrl = true
+		case "shift":
+			c.shift = true
+		case "alt":
+			c.alt = true
+		case "meta", "cmd":
+			c.meta = true
+		default:
+			return c, fmt.Errorf(`Invalid modifier "%v" in the shortcut "%v".`, part, combo)
+		}
+	}
+	return
+}
+
+// keyPress is a keydown event
+type keyPress struct {
+	keyCombo
+	// editable tells whether the event's target is a text input or an editable element
+	editable       bool
+	preventDefault func()
+}
+
+// matches returns whether the key press triggers the combo. The shift state is
+// ignored for the symbols, since it's needed to type some of them, like "?".
+// A key press in an editable element only triggers a combo with ctrl, alt or meta,
+// so that typing "/" in a text input doesn't trigger the "/" shortcut.
+func (c keyCombo) matches(kp keyPress) bool {
+	if kp.editable && !c.ctrl && !c.alt && !c.meta {
+		return false
+	}
+
+	key := strings.ToLower(kp.key)
+	isSymbol := len([]rune(key)) == 1 && strings.ToUpper(key) == key
+	return key == c.key &&
+		kp.ctrl == c.ctrl && kp.alt == c.alt && kp.meta == c.meta &&
+		(isSymbol || kp.shift == c.shift)
+}
+
+// Shortcut is a registered keyboard shortcut
+type Shortcut struct {
+	// PreventDefault tells whether the default action of the key press
+	// is prevented when the shortcut is triggered, it's true by default
+	PreventDefault bool
+
+	combo   keyCombo
+	handler func()
+	sc      *shortcuts
+}
+
+// Unregister removes the shortcut, its handler is not called anymore
+func (s *Shortcut) Unregister() {
+	for i, other := range s.sc.list {
+		if other == s {
+			s.sc.list = append(s.sc.list[:i], s.sc.list[i+1:]...)
+			return
+		}
+	}
+}
+
+// shortcuts holds the registered shortcuts, the keydown events are listened
+// to when the first shortcut is registered
+type shortcuts struct {
+	list      []*Shortcut
+	listen    func(fn func(keyPress))
+	listening bool
+}
+
+func newShortcuts(listen func(fn func(keyPress))) *shortcuts {
+	return &shortcuts{listen: listen}
+}
+
+func (sc *shortcuts) register(combo string, handler func()) *Shortcut {
+	c, err := parseCombo(combo)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	s := &Shortcut{PreventDefault: true, combo: c, handler: handler, sc: sc}
+	sc.list = append(sc.list, s)
+	if !sc.listening {
+		sc.listening = true
+		sc.listen(sc.keyDown)
+	}
+	return s
+}
+
+// keyDown calls the handlers of the shortcuts triggered by the key press
+func (sc *shortcuts) keyDown(kp keyPress) {
+	list := append([]*Shortcut{}, sc.list...)
+	for _, s := range list {
+		if !s.combo.matches(kp) {
+			continue
+		}
+
+		if s.PreventDefault {
+			kp.preventDefault()
+		}
+		s.handler()
+	}
+}
+
+// listenDocumentKeys listens to the keydown events of the document
+func listenDocumentKeys(fn func(keyPress)) {
+	gJQ(js.Global.Get("document")).On(jq.KEYDOWN, func(e jq.Event) {
+		fn(keyPress{
+			keyCombo: keyCombo{
+				key:   e.Get("key").Str(),
+				ctrl:  e.CtrlKey,
+				shift: e.ShiftKey,
+				alt:   e.AltKey,
+				meta:  e.MetaKey,
+			},
+			editable:       gJQ(e.Target).Is("input, textarea, select, [contenteditable]"),
+			preventDefault: e.PreventDefault,
+		})
+	})
+}
+
+// RegisterShortcut registers a global keyboard shortcut, the handler is called when
+// the combo is pressed anywhere in the document. A combo is a key, like "/", "k" or
+// "escape", optionally preceded by modifiers: "ctrl", "shift", "alt" and "meta" (or "cmd"),
+// joined with "+". The default action of the key press is prevented unless the
+// returned Shortcut's PreventDefault is set to false.
+// Unless they have ctrl, alt or meta, the shortcuts are not triggered while typing
+// in a text input.
+// Usage:
+//	wd.RegisterShortcut("/", func() { jquery.NewJQuery("#search").Focus() })
+//	save := wd.RegisterShortcut("ctrl+s", model.Save)
+//	...
+//	save.Unregister()
+func (wd *Wade) RegisterShortcut(combo string, handler func()) *Shortcut {
+	return wd.shortcuts.register(combo, handler)
+}
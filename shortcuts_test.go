@@ -0,0 +1,86 @@
+package wade
+
+import "testing"
+
+func TestParseCombo(t *testing.T) {
+	tests := map[string]keyCombo{
+		"/":             {key: "/"},
+		"ctrl+k":        {key: "k", ctrl: true},
+		"Shift+Alt+N":   {key: "n", shift: true, alt: true},
+		"cmd + enter":   {key: "enter", meta: true},
+		"esc":           {key: "escape"},
+		"ctrl+plus":     {key: "+", ctrl: true},
+		"control+space": {key: " ", ctrl: true},
+	}
+	for combo, expected := range tests {
+		if c, err := parseCombo(combo); err != nil || c != expected {
+			t.Errorf("%q: expected %+v, got %+v (%v).", combo, expected, c, err)
+		}
+	}
+
+	for _, combo := range []string{"", "ctrl+", "hyper+k"} {
+		if _, err := parseCombo(combo); err == nil {
+			t.Errorf("Expected an error for %q.", combo)
+		}
+	}
+}
+
+func TestShortcuts(t *testing.T) {
+	var keyDown func(keyPress)
+	listens := 0
+	wd := &Wade{shortcuts: newShortcuts(func(fn func(keyPress)) {
+		listens++
+		keyDown = fn
+	})}
+
+	search, palette := 0, 0
+	wd.RegisterShortcut("/", func() { search++ })
+	ctrlK := wd.RegisterShortcut("ctrl+k", func() { palette++ })
+	if listens != 1 {
+		t.Fatalf("Expected the document to be listened to once, got %v.", listens)
+	}
+
+	prevented := 0
+	press := func(key string, ctrl, shift, editable bool) {
+		keyDown(keyPress{
+			keyCombo:       keyCombo{key: key, ctrl: ctrl, shift: shift},
+			editable:       editable,
+			preventDefault: func() { prevented++ },
+		})
+	}
+
+	press("k", false, false, false)
+	press("K", true, true, false)
+	press("/", true, false, false)
+	if search != 0 || palette != 0 || prevented != 0 {
+		t.Errorf("Expected no handler to be called for other combos, got %v and %v.", search, palette)
+	}
+
+	press("/", false, false, false)
+	press("k", true, false, false)
+	press("K", true, false, false)
+	if search != 1 || palette != 2 || prevented != 3 {
+		t.Errorf("Expected the handlers to be called on their combos, got %v and %v.", search, palette)
+	}
+
+	// typing in a text input
+	press("/", false, false, true)
+	press("k", true, false, true)
+	if search != 1 || palette != 3 {
+		t.Errorf("Expected only the shortcuts with ctrl to be triggered in an input, got %v and %v.", search, palette)
+	}
+
+	ctrlK.PreventDefault = false
+	prevented = 0
+	press("k", true, false, false)
+	if palette != 4 || prevented != 0 {
+		t.Errorf("Expected the default action not to be prevented.")
+	}
+
+	ctrlK.Unregister()
+	press("k", true, false, false)
+	press("/", false, false, false)
+	if palette != 4 || search != 2 {
+		t.Errorf("Expected only the unregistered shortcut to stop, got %v and %v.", search, palette)
+	}
+}
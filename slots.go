@@ -0,0 +1,79 @@
+package wade
+
+import (
+	jq "github.com/gopherjs/jquery"
+)
+
+const (
+	// ContentsTag is replaced by the contents given to a custom element, except the
+	// ones assigned to named slots. It's the default slot.
+	ContentsTag = "wcontents"
+	// SlotTag is a named slot in the template of a custom tag, it's replaced by the
+	// children of the custom element whose SlotAttr is its name. A slot without name
+	// is the default slot, like ContentsTag. The contents of a slot are shown when no
+	// child is assigned to it.
+	//
+	// Usage:
+	//	<wslot name="header"></wslot>
+	//	<wslot name="body">No content</wslot>
+	// and in the page:
+	//	<mypanel><h2 slot="header">Title</h2><p slot="body">Text</p></mypanel>
+	SlotTag = "wslot"
+	// SlotAttr is the attribute of the children of a custom element that names the slot they go to
+	SlotAttr = "slot"
+	// SlotNameAttr is the attribute of a SlotTag holding its name
+	SlotNameAttr = "name"
+)
+
+// assignSlots assigns the children of a custom element, given by the names of their
+// slots ("" for the children without slot), to the slots declared by the template.
+// The children of a slot that's not declared go to the default slot, named "".
+// It returns the indexes of the children of each slot.
+func assignSlots(children []string, declared map[string]bool) map[string][]int {
+	assigned := make(map[string][]int)
+	for i, name := range children {
+		if !declared[name] {
+			name = ""
+		}
+		assigned[name] = append(assigned[name], i)
+	}
+	return assigned
+}
+
+// fillSlots replaces the slots of the custom element's contents with
+// copies of the children of the element assigned to them
+func fillSlots(elem jq.JQuery, contents jq.JQuery) {
+	nodes := contents.Contents()
+	children := make([]string, nodes.Length)
+	nodes.Each(func(i int, node jq.JQuery) {
+		if node.Is("[" + SlotAttr + "]") {
+			children[i] = node.Attr(SlotAttr)
+		}
+	})
+
+	slots := elem.Find(SlotTag)
+	declared := make(map[string]bool)
+	slots.Each(func(_ int, slot jq.JQuery) {
+		declared[slot.Attr(SlotNameAttr)] = true
+	})
+	assigned := assignSlots(children, declared)
+
+	copies := func(indexes []int) jq.JQuery {
+		c := gJQ()
+		for _, i := range indexes {
+			c = c.Add(nodes.Eq(i).Clone())
+		}
+		return c
+	}
+
+	slots.Each(func(_ int, slot jq.JQuery) {
+		if indexes, ok := assigned[slot.Attr(SlotNameAttr)]; ok {
+			slot.ReplaceWith(copies(indexes))
+		} else {
+			slot.ReplaceWith(slot.Contents())
+		}
+	})
+	elem.Find(ContentsTag).Each(func(_ int, placeholder jq.JQuery) {
+		placeholder.ReplaceWith(copies(assigned[""]))
+	})
+}
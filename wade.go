@@ -23,6 +23,7 @@ type Wade struct {
 	tcontainer jq.JQuery
 	binding    *bind.Binding
 	serverbase string
+	shortcuts  *shortcuts
 }
 
 var (
@@ -66,7 +67,9 @@ func WadeUp(startPage, basePath string, initFn func(*Wade)) *Wade {
 		binding:    binding,
 		tcontainer: tElem,
 		serverbase: serverbase,
+		shortcuts:  newShortcuts(listenDocumentKeys),
 	}
+	binding.SetQueryLocation(wd.pm)
 	wd.init()
 	initFn(wd)
 	return wd
@@ -118,6 +121,18 @@ func (wd *Wade) RegisterCustomTags(srcFile string, protomap map[string]interface
 	wd.tm.registerTags(tagElems, protomap)
 }
 
+// OnReady registers a function to be called once the app has been bound, after
+// the initial page's bindings and custom elements are done. See PageManager.OnPageReady
+// for a function to be called again after each navigation.
+func (wd *Wade) OnReady(fn func()) {
+	wd.pm.OnReady(fn)
+}
+
+// Custags returns the custom tag manager
+func (wd *Wade) Custags() *CustagMan {
+	return wd.tm
+}
+
 // Binding returns the binding engine
 func (wd *Wade) Binding() *bind.Binding {
 	return wd.binding